@@ -0,0 +1,37 @@
+package structexp // nolint:golint // in another file
+
+import "regexp"
+
+// ansiRegexp matches the ANSI escape sequences of terminal output:
+// control sequences, such as colors and erasing the line, operating
+// system commands, such as hyperlinks and window titles, and the
+// other escape sequences of a single character
+var ansiRegexp = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// StripANSI removes the ANSI escape sequences, such as colors, from the input
+// before matching it, for text captured from a terminal, like CI console logs.
+// Parsing methods taking a string or bytes strip them, and the offsets of
+// ParseWithOffsets and ParseResult, and the rest returned by ParsePrefix, are
+// those of the stripped input. AllMatches and SplitFunc read raw input.
+func StripANSI() Option {
+	return func(o *options) {
+		o.stripANSI = true
+	}
+}
+
+// input returns the string to match, without its
+// ANSI escape sequences with the StripANSI option
+func (p *typeParser) input(s string) string {
+	if p.opts.stripANSI {
+		return ansiRegexp.ReplaceAllString(s, "")
+	}
+	return s
+}
+
+// inputBytes returns the bytes to match, like input
+func (p *typeParser) inputBytes(b []byte) []byte {
+	if p.opts.stripANSI {
+		return ansiRegexp.ReplaceAll(b, nil)
+	}
+	return b
+}
@@ -0,0 +1,68 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripANSI(t *testing.T) {
+	type TestCase struct {
+		Name  string
+		Input string
+		Value Header
+		Error error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:  "colors",
+			Input: "\x1b[1;31mERROR\x1b[0m: \x1b[33m42\x1b[m",
+			Value: Header{Level: "ERROR", Code: 42},
+			Error: nil,
+		},
+		{
+			Name:  "erase line and hyperlink",
+			Input: "\x1b[2K\x1b]8;;https://example.com\x07WARN\x1b]8;;\x1b\\: 7",
+			Value: Header{Level: "WARN", Code: 7},
+			Error: nil,
+		},
+		{
+			Name:  "no escapes",
+			Input: "INFO: 3",
+			Value: Header{Level: "INFO", Code: 3},
+			Error: nil,
+		},
+	}
+
+	parser, err := Compile[Header](StripANSI())
+	require.NoError(t, err)
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			value, err := parser.Parse(tc.Input)
+			assert.EqualValues(t, tc.Value, value)
+			assert.EqualValues(t, tc.Error, err)
+
+			value, err = parser.ParseBytes([]byte(tc.Input))
+			assert.EqualValues(t, tc.Value, value)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestStripANSIOffsets(t *testing.T) {
+	parser, err := Compile[Header](StripANSI())
+	require.NoError(t, err)
+
+	_, offsets, err := parser.ParseWithOffsets("\x1b[31mERROR\x1b[0m: 42")
+	require.NoError(t, err)
+	assert.EqualValues(t, []FieldOffset{{"Level", "level", 0, 5}, {"Code", "code", 7, 9}}, offsets)
+}
+
+func TestWithoutStripANSI(t *testing.T) {
+	_, err := ParseAs[Header]("\x1b[31mERROR\x1b[0m: 42")
+	assert.EqualValues(t, noMatch[Header]("\x1b[31mERROR\x1b[0m: 42"), err)
+}
@@ -0,0 +1,82 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"html"
+	"strings"
+)
+
+// AttributesSubParser parses the capture as the attributes of an XML or HTML tag
+const AttributesSubParser = "attrs"
+
+func init() {
+	RegisterSubParser(AttributesSubParser, ParseAttributes)
+}
+
+// ParseAttributes parses the attributes of a single XML or HTML tag, such as
+// <img src="a.png" alt='An "A"' hidden>, into the argument, which must be the
+// address of a map[string]string or a struct. The tag name and brackets are
+// optional. Values may be double quoted, single quoted, or unquoted, and
+// character references in them are unescaped. Attributes without a value are
+// given an empty value. Struct fields are matched to attribute names the same
+// way as ParseKeyValues matches keys.
+//
+// Errors occur if:
+//  - argument is not the address of a struct or map[string]string
+//  - an attribute's value fails to parse into its field
+func ParseAttributes(s string, i interface{}) error {
+	return assignKeyValues(splitAttributes(s), i)
+}
+
+const attributeSpace = " \t\r\n\f"
+
+// splitAttributes splits the attributes of the tag into pairs
+func splitAttributes(s string) []keyValue {
+	s = strings.TrimLeft(s, attributeSpace)
+	if strings.HasPrefix(s, "<") {
+		s = s[1:]
+		if end := strings.IndexAny(s, attributeSpace+"/>"); end != -1 {
+			s = s[end:]
+		} else {
+			s = ""
+		}
+	}
+
+	var pairs []keyValue
+	for {
+		s = strings.TrimLeft(s, attributeSpace+"/")
+		if s == "" || s[0] == '>' {
+			return pairs
+		}
+
+		end := strings.IndexAny(s, attributeSpace+"=/>")
+		if end == -1 {
+			end = len(s)
+		}
+		pair := keyValue{Key: s[:end]}
+		s = strings.TrimLeft(s[end:], attributeSpace)
+
+		if strings.HasPrefix(s, "=") {
+			s = strings.TrimLeft(s[1:], attributeSpace)
+			var value string
+			value, s = attributeValue(s)
+			pair.Value = html.UnescapeString(value)
+		}
+		pairs = append(pairs, pair)
+	}
+}
+
+// attributeValue splits the attribute value from the start of the
+// string, returning the value without quotes and the remaining string
+func attributeValue(s string) (string, string) {
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		if end := strings.IndexByte(s[1:], s[0]); end != -1 {
+			return s[1 : end+1], s[end+2:]
+		}
+		return s[1:], ""
+	}
+	end := strings.IndexAny(s, attributeSpace+">")
+	if end == -1 {
+		end = len(s)
+	}
+	return s[:end], s[end:]
+}
@@ -0,0 +1,74 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Image struct {
+	Source string `structexp.name:"src"`
+	Alt    string `structexp.name:"alt"`
+	Width  int    `structexp.name:"width"`
+	Hidden bool   `structexp.name:"hidden"`
+}
+
+type ImageTag struct {
+	StructExp `structexp:"{{img}}"`
+	Image     Image `structexp.name:"img" structexp.sub:"attrs" structexp.exp:"<img[^>]*>"`
+}
+
+func TestParseAttributes(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Parse    func(string, interface{}) error
+		String   string
+		Input    interface{}
+		Expected interface{}
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "Map",
+			Parse:  ParseAttributes,
+			String: `<img src="a b.png" alt='An "A"' width=10 hidden data-x="&lt;&amp;&gt;"/>`,
+			Input:  &map[string]string{},
+			Expected: &map[string]string{
+				"src":    "a b.png",
+				"alt":    `An "A"`,
+				"width":  "10",
+				"hidden": "",
+				"data-x": "<&>",
+			},
+		},
+		{
+			Name:     "WithoutTag",
+			Parse:    ParseAttributes,
+			String:   `src = "a.png"  width=10`,
+			Input:    &map[string]string{},
+			Expected: &map[string]string{"src": "a.png", "width": "10"},
+		},
+		{
+			Name:     "Struct",
+			Parse:    ParseAttributes,
+			String:   `<img src="a.png" alt='An "A"' width=10 hidden=true>`,
+			Input:    &Image{},
+			Expected: &Image{Source: "a.png", Alt: `An "A"`, Width: 10, Hidden: true},
+		},
+		{
+			Name:     "SubParser",
+			Parse:    Parse,
+			String:   `<p><img src=a.png width="20"></p>`,
+			Input:    &ImageTag{},
+			Expected: &ImageTag{Image: Image{Source: "a.png", Width: 20}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.NoError(t, tc.Parse(tc.String, tc.Input))
+			assert.EqualValues(t, tc.Expected, tc.Input)
+		})
+	}
+}
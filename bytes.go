@@ -0,0 +1,179 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+)
+
+// ParseBytes is like Parse, but parses a byte slice without converting the
+// whole input to a string; only the captured submatches are copied. This
+// suits inputs such as bufio.Scanner.Bytes() on high-volume streams.
+//
+// Errors occur in the same cases as Parse.
+func ParseBytes(b []byte, i interface{}) error {
+	t := reflect.TypeOf(i)
+	if kind := t.Kind(); kind != reflect.Ptr {
+		return &NotStruct{kind}
+	}
+
+	t = t.Elem()
+	if kind := t.Kind(); kind != reflect.Struct {
+		return &NotStruct{kind}
+	}
+
+	p, err := cachedParser(t)
+	if err != nil {
+		return err
+	}
+	return p.parseBytes(reflect.ValueOf(i).Elem(), b)
+}
+
+// ParseBytes returns a new T parsed from the byte slice, like ParseBytes
+func (p *Parser[T]) ParseBytes(b []byte) (T, error) {
+	var v T
+	err := p.parseBytes(reflect.ValueOf(&v).Elem(), b)
+	return v, err
+}
+
+// ParseBytesInto parses the byte slice into the value, like ParseBytes,
+// without allocating a new T, so that a hot loop can reuse one value for every
+// record. Fields the input does not set keep their values, as with Parse.
+//
+// If every field is set directly from its capture, as are the fields of the
+// basic kinds without a trim, default, transform, or sub-parser, the captures
+// are converted in place, and the only allocations are those of the regular
+// expression engine and the copies of the string fields' captures. Matching
+// the other templates of T, stripping with the StripANSI option, and decoding
+// a JSON object sniffed with the SniffJSON option allocate more.
+func (p *Parser[T]) ParseBytesInto(b []byte, v *T) error {
+	return p.parseBytes(reflect.ValueOf(v).Elem(), b)
+}
+
+// parseBytes sets the struct value from the byte slice, like parse
+func (p *typeParser) parseBytes(v reflect.Value, b []byte) error {
+	b = p.inputBytes(b)
+	if err := checkInputSize(len(b), p.opts); err != nil {
+		return err
+	}
+	start := p.stats.start()
+	if p.opts.sniffJSON && isJSONObjectBytes(b) {
+		err := json.Unmarshal(b, v.Addr().Interface())
+		p.stats.observe(start, err)
+		return err
+	}
+	c, loc := p.candidateBytes(b)
+	if len(c.scanned) > 0 {
+		loc = scanMatch(newSearcher(c.regexp), c.scanned, string(b), 0, loc)
+	}
+	err := setStructMatchBytes(v, c.regexp, c.fields, b, loc)
+	p.stats.observe(start, err)
+	if err == nil && p.opts.warningHook != nil {
+		p.warn(matchWarnings(v.Type(), c.regexp, c.fields, string(b), loc))
+	}
+	return err
+}
+
+// candidateBytes returns the typeParser of the first template whose regular
+// expression matches the byte slice, and the location of the match, like candidate
+func (p *typeParser) candidateBytes(b []byte) (*typeParser, []int) {
+	if loc := p.regexp.FindSubmatchIndex(b); loc != nil || len(p.alternates) == 0 {
+		return p, loc
+	}
+	for _, alternate := range p.alternates {
+		if loc := alternate.regexp.FindSubmatchIndex(b); loc != nil {
+			return alternate, loc
+		}
+	}
+	return p, nil
+}
+
+// setStructMatchBytes sets the struct's fields from the match of the regular
+// expression in the byte slice at the location, as from FindSubmatchIndex
+func setStructMatchBytes(v reflect.Value, regxp *regexp.Regexp, fields []*field, b []byte, loc []int) error {
+	if loc == nil {
+		return newNoMatchBytes(regxp, b)
+	}
+	if bytesFields(regxp, fields) {
+		return setMatchBytes(v, fields, b, loc)
+	}
+
+	// The whole match is only copied for the fields bound to it
+	wholeMatch := false
+	for _, f := range fields {
+		wholeMatch = wholeMatch || f.group() == WholeMatch
+	}
+	matches := make([]string, len(loc)/2)
+	for i := range matches {
+		if start, end := loc[2*i], loc[2*i+1]; start >= 0 && (i > 0 || wholeMatch) {
+			matches[i] = string(b[start:end])
+		}
+	}
+	return setFields(v, regxp, fields, matches)
+}
+
+// bytesField reports whether the field is set directly from the bytes of its
+// capture, with nothing needing the capture as a string but its conversion
+func (f *field) bytesField() bool {
+	return f.Converter.SetBytes != nil && f.Raw == "" && f.Trim == nil && f.Default == "" &&
+		f.Transform == nil && len(f.Transforms) == 0 && f.SubParser == nil && f.Discriminator == "" &&
+		f.Nested == nil && f.Split == "" && f.Submatches == nil
+}
+
+// bytesFields reports whether every field is a bytesField,
+// with its capture group planned for the regular expression
+func bytesFields(regxp *regexp.Regexp, fields []*field) bool {
+	names := regxp.SubexpNames()
+	for _, f := range fields {
+		if !f.bytesField() || !f.plannedFor(names) {
+			return false
+		}
+	}
+	return true
+}
+
+// setMatchBytes sets the bytesFields of the struct value from the submatch
+// indexes in the byte slice, like setSubmatches, converting the captures
+// without copying them, except into string fields
+func setMatchBytes(v reflect.Value, fields []*field, b []byte, loc []int) error {
+	var errs []error
+	for _, f := range fields {
+		if f.FormatOnly || f.Group == -1 {
+			continue
+		}
+		if err := f.setMatchBytes(v, b, loc); err != nil {
+			if !f.JoinErrors {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setMatchBytes sets the field of the struct value from its
+// submatch index in the byte slice, like setSubmatch
+func (f *field) setMatchBytes(v reflect.Value, b []byte, loc []int) error {
+	var capture []byte
+	if start := loc[2*f.Group]; start >= 0 {
+		capture = b[start:loc[2*f.Group+1]]
+	}
+	if len(capture) == 0 {
+		if f.Strict && !f.Optional {
+			return &EmptyCapture{fieldPath(v.Type(), f.Index), f.CaptureGroupName}
+		}
+		if f.Optional {
+			// Keep the zero value
+			return nil
+		}
+	}
+	val := fieldByIndex(v, f.Index)
+	if err := f.Converter.SetBytes(val, capture); err != nil {
+		// Set from the string, for the overflow policy and errors of setValue
+		if err := f.setValue(val, string(capture)); err != nil {
+			return f.fieldError(v.Type(), string(capture), err)
+		}
+	}
+	return f.checkValue(v)
+}
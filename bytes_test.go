@@ -0,0 +1,192 @@
+package structexp
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBytes(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Bytes    []byte
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "NestedStructExp",
+			Bytes:    []byte("[WARN: 12] 34"),
+			Input:    &ParentHeaderStruct{},
+			Expected: &ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}, Code: 34},
+			Error:    nil,
+		},
+		{
+			Name:     "NoMatchError",
+			Bytes:    []byte("a"),
+			Input:    &Int{},
+			Expected: &Int{},
+			Error:    noMatch[Int]("a"),
+		},
+		{
+			Name:     "NotStructError",
+			Bytes:    []byte("1"),
+			Input:    new(int),
+			Expected: new(int),
+			Error:    &NotStruct{reflect.Int},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ParseBytes(tc.Bytes, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestParserParseBytes(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct]()
+	value, err := parser.ParseBytes([]byte("[WARN: 12] 34"))
+	require.NoError(t, err)
+	assert.EqualValues(t, ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}, Code: 34}, value)
+}
+
+type Counters struct {
+	StructExp `structexp:"^{{name}} rx={{rx}} tx={{tx}}(?: up={{up}})?(?: drops={{drops}})?$"`
+	Name      string `structexp.name:"name" structexp.exp:"[a-z0-9]+"`
+	RX        int    `structexp.name:"rx"`
+	TX        int    `structexp.name:"tx"`
+	Up        bool   `structexp.name:"up" structexp.exp:"true|false" structexp.optional:"true"`
+	Drops     int    `structexp.name:"drops" structexp.optional:"true"`
+}
+
+func TestParseBytesInto(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Bytes    []byte
+		Expected Counters
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "AllFields",
+			Bytes:    []byte("eth0 rx=9223372036854775807 tx=12 up=true drops=3"),
+			Expected: Counters{Name: "eth0", RX: 9223372036854775807, TX: 12, Up: true, Drops: 3},
+			Error:    nil,
+		},
+		{
+			Name:     "OptionalFields",
+			Bytes:    []byte("lo rx=1 tx=2"),
+			Expected: Counters{Name: "lo", RX: 1, TX: 2},
+			Error:    nil,
+		},
+		{
+			Name:     "OutOfRange",
+			Bytes:    []byte("lo rx=1 tx=2 drops=9223372036854775808"),
+			Expected: Counters{Name: "lo", RX: 1, TX: 2},
+			Error: &FieldError{"Drops", "drops", "9223372036854775808",
+				&OutOfRange{reflect.TypeOf(0), "9223372036854775808", &strconv.NumError{Func: "ParseInt", Num: "9223372036854775808", Err: strconv.ErrRange}}},
+		},
+		{
+			Name:     "NoMatch",
+			Bytes:    []byte("lo"),
+			Expected: Counters{},
+			Error:    noMatch[Counters]("lo"),
+		},
+	}
+
+	parser := MustCompile[Counters]()
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var value Counters
+			err := parser.ParseBytesInto(tc.Bytes, &value)
+			assert.EqualValues(t, tc.Expected, value)
+			assert.EqualValues(t, tc.Error, err)
+
+			// Set the same as from a string
+			var fromString Counters
+			assert.EqualValues(t, tc.Error, Parse(string(tc.Bytes), &fromString))
+			assert.EqualValues(t, fromString, value)
+		})
+	}
+}
+
+func TestParseBytesWholeMatch(t *testing.T) {
+	// Set directly from the bytes, and from a copy of the captures
+	for _, b := range [][]byte{[]byte("at 10:00 WARN: 12"), []byte("WARN: 12 ")} {
+		var direct RawHeader
+		require.NoError(t, ParseBytes(b, &direct))
+		assert.EqualValues(t, RawHeader{Raw: "WARN: 12", Level: "WARN", Code: 12}, direct)
+	}
+	parser := MustCompile[RawHeader](Strict())
+	value, err := parser.ParseBytes([]byte("WARN: 12"))
+	require.NoError(t, err)
+	assert.EqualValues(t, RawHeader{Raw: "WARN: 12", Level: "WARN", Code: 12}, value)
+
+	var trimmed TrimmedRawHeader
+	require.NoError(t, ParseBytes([]byte("WARN:  12 "), &trimmed))
+	assert.EqualValues(t, TrimmedRawHeader{Raw: "WARN:  12 ", Level: "WARN", Code: 12}, trimmed)
+}
+
+type TrimmedRawHeader struct {
+	StructExp `structexp:"{{level}}:{{code}}"`
+	Raw       string `structexp.name:"$0"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code      int    `structexp.name:"code" structexp.exp:" *[0-9]+ *" structexp.trim:"true"`
+}
+
+func TestParseBytesIntoAllocs(t *testing.T) {
+	if testing.Short() || raceEnabled {
+		t.Skip("allocations are only counted exactly without the race detector")
+	}
+	parser := MustCompile[Counters]()
+	numeric := []byte("lo rx=1 tx=2 up=false drops=3")
+	var value Counters
+	require.NoError(t, parser.ParseBytesInto(numeric, &value))
+
+	// Only the regular expression engine allocates its submatch indexes,
+	// and the string field its copy of the capture
+	engine := testing.AllocsPerRun(100, func() {
+		parser.Regexp().FindSubmatchIndex(numeric)
+	})
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := parser.ParseBytesInto(numeric, &value); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.LessOrEqual(t, allocs, engine+1)
+}
+
+func TestParseBytesIntoLikeParse(t *testing.T) {
+	// The same Parser parses bytes like strings, with its templates and options
+	parser := MustCompile[JSONHeader](SniffJSON())
+	for _, input := range []string{"WARN: 12", `{"level": "INFO", "code": 3}`, "warn"} {
+		expected, expectedErr := parser.Parse(input)
+		var value JSONHeader
+		err := parser.ParseBytesInto([]byte(input), &value)
+		assert.EqualValues(t, expectedErr, err)
+		assert.EqualValues(t, expected, value)
+	}
+}
+
+func BenchmarkParserParseBytesInto(b *testing.B) {
+	parser := MustCompile[Counters]()
+	line := []byte("eth0 rx=2776770 tx=11307 up=true drops=0")
+	var value Counters
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := parser.ParseBytesInto(line, &value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
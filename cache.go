@@ -0,0 +1,52 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// compiledType is the typeParser of a struct type, compiled
+// without options while the registrations were of the generation
+type compiledType struct {
+	parser     *typeParser
+	generation uint64
+}
+
+var (
+	// compiledTypes holds the compiledType of every struct type parsed by
+	// the package functions, so that parsing a type again skips reflecting
+	// over its fields and compiling its regular expression
+	compiledTypes sync.Map
+	// compileGeneration is incremented by every registration that may change
+	// how a struct type compiles, such as of a sub-parser, which makes the
+	// compiled types of earlier generations stale
+	compileGeneration atomic.Uint64
+)
+
+// cachedCompile returns the typeParser of the struct type compiled by the
+// function, compiling it only if it has no compiledType of the current
+// generation. Compile errors are not cached, as a registration may fix them.
+func cachedCompile(t reflect.Type, compile func(reflect.Type) (*typeParser, error)) (*typeParser, error) {
+	generation := compileGeneration.Load()
+	if cached, ok := compiledTypes.Load(t); ok {
+		if c := cached.(*compiledType); c.generation == generation {
+			return c.parser, nil
+		}
+	}
+
+	p, err := compile(t)
+	if err != nil {
+		return nil, err
+	}
+	// Stored with the generation from before compiling, a type compiled
+	// during a registration is compiled again by the next call
+	compiledTypes.Store(t, &compiledType{p, generation})
+	return p, nil
+}
+
+// invalidateCompiledTypes makes the compiled types stale,
+// after a registration that may change how they compile
+func invalidateCompiledTypes() {
+	compileGeneration.Add(1)
+}
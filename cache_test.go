@@ -0,0 +1,70 @@
+package structexp
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CachedSubParserStruct struct {
+	StructExp `structexp:"^{{name}}$"`
+	Name      string `structexp.name:"name" structexp.sub:"cachetest"`
+}
+
+func TestCompileCache(t *testing.T) {
+	t.Cleanup(func() {
+		defaultRegistry.mu.Lock()
+		delete(defaultRegistry.subParsers, "cachetest")
+		defaultRegistry.mu.Unlock()
+		invalidateCompiledTypes()
+	})
+
+	typ := reflect.TypeOf(ParentHeaderStruct{})
+	regxp, fields, err := compile(typ)
+	require.NoError(t, err)
+	cachedRegexp, cachedFields, err := compile(typ)
+	require.NoError(t, err)
+	assert.Same(t, regxp, cachedRegexp)
+	assert.Equal(t, fields, cachedFields)
+
+	// Errors are not cached, and registrations are seen by the next parse
+	var value CachedSubParserStruct
+	assert.EqualValues(t, &UnknownSubParser{"cachetest"}, Parse("a", &value))
+	RegisterSubParser("cachetest", func(s string, i interface{}) error {
+		*i.(*string) = strings.ToUpper(s)
+		return nil
+	})
+	require.NoError(t, Parse("a", &value))
+	assert.Equal(t, "A", value.Name)
+	RegisterSubParser("cachetest", func(s string, i interface{}) error {
+		*i.(*string) = s + s
+		return nil
+	})
+	require.NoError(t, Parse("a", &value))
+	assert.Equal(t, "aa", value.Name)
+
+	uncachedRegexp, _, err := compile(typ)
+	require.NoError(t, err)
+	assert.NotSame(t, regxp, uncachedRegexp)
+}
+
+func TestCompileCacheConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				var value ParentHeaderStruct
+				if assert.NoError(t, Parse("[WARN: 12] 34", &value)) {
+					assert.Equal(t, ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}, Code: 34}, value)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,111 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	minKey   = "structexp.min"
+	maxKey   = "structexp.max"
+	oneOfKey = "structexp.oneof"
+	matchKey = "structexp.match"
+)
+
+// checkKeys are the tag keys of the checks of a field's value, in the order they are checked
+var checkKeys = []string{minKey, maxKey, oneOfKey, matchKey}
+
+// check is a validation tag of a field, checked against the field's value after it is set
+type check struct {
+	Key   string
+	Value string
+	// Reports whether the value passes the check
+	OK func(v reflect.Value) bool
+}
+
+// newChecks returns the checks of the validation tags of the field type:
+//  - structexp.min and structexp.max: the least and greatest number, or length of strings and slices
+//  - structexp.oneof: a comma separated list of the allowed values, in their default format
+//  - structexp.match: a regular expression matching the value in its default format
+func newChecks(t reflect.Type, tag reflect.StructTag) ([]check, error) {
+	var checks []check
+	for _, key := range checkKeys {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		c := check{Key: key, Value: value}
+		switch key {
+		case minKey, maxKey:
+			limit, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := measure(reflect.Zero(derefType(t))); !ok {
+				return nil, fmt.Errorf("%v has no number or length to compare", t)
+			}
+			isMin := key == minKey
+			c.OK = func(v reflect.Value) bool {
+				n, _ := measure(v)
+				if isMin {
+					return n >= limit
+				}
+				return n <= limit
+			}
+		case oneOfKey:
+			allowed := strings.Split(value, ",")
+			c.OK = func(v reflect.Value) bool {
+				return containsString(allowed, fmt.Sprint(v.Interface()))
+			}
+		case matchKey:
+			regxp, err := regexp.Compile(value)
+			if err != nil {
+				return nil, err
+			}
+			c.OK = func(v reflect.Value) bool {
+				return regxp.MatchString(fmt.Sprint(v.Interface()))
+			}
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// measure returns the number of a numeric value, or
+// the length of a string, slice, array, or map value
+func measure(v reflect.Value) (float64, bool) {
+	// nolint:exhaustive // unnecessary
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	default:
+		return 0, false
+	}
+}
+
+// checkValue checks the field's value within the struct value against the
+// field's validation tags. Nil pointers, such as of optional fields, are not checked.
+func (f field) checkValue(v reflect.Value) error {
+	if len(f.Checks) == 0 {
+		return nil
+	}
+	val := underlyingValue(fieldByIndex(v, f.Index))
+	if !val.IsValid() {
+		return nil
+	}
+	for _, c := range f.Checks {
+		if !c.OK(val) {
+			return &ValidationError{fieldPath(v.Type(), f.Index), c.Key, c.Value, fmt.Sprint(val.Interface())}
+		}
+	}
+	return nil
+}
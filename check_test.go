@@ -0,0 +1,108 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CheckedStruct struct {
+	StructExp `structexp:"^{{method}} {{port}} {{host}}(?: {{tags}})?$"`
+	Method    string   `structexp.name:"method" structexp.exp:"[A-Z]+" structexp.oneof:"GET,POST"`
+	Port      int      `structexp.name:"port" structexp.min:"1" structexp.max:"65535"`
+	Host      string   `structexp.name:"host" structexp.exp:"[^ ]+" structexp.match:"^[a-z.]+$"`
+	Tags      []string `structexp.name:"tags" structexp.exp:"[a-z,]+" structexp.split:"," structexp.max:"2" structexp.optional:"true"`
+}
+
+type InvalidCheckStruct struct {
+	StructExp `structexp:"^{{a}} {{b}} {{c}}$"`
+	A         int  `structexp.name:"a" structexp.min:"low"`
+	B         bool `structexp.name:"b" structexp.max:"1"`
+	C         int  `structexp.name:"c" structexp.match:"(1"`
+}
+
+func TestCheck(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "valid",
+			String:   "GET 8080 example.com a,b",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "GET", Port: 8080, Host: "example.com", Tags: []string{"a", "b"}},
+			Error:    nil,
+		},
+		{
+			Name:     "optional is not checked",
+			String:   "POST 1 localhost",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "POST", Port: 1, Host: "localhost"},
+			Error:    nil,
+		},
+		{
+			Name:     "oneof",
+			String:   "PUT 8080 example.com",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "PUT"},
+			Error:    &ValidationError{"Method", "structexp.oneof", "GET,POST", "PUT"},
+		},
+		{
+			Name:     "min",
+			String:   "GET 0 example.com",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "GET"},
+			Error:    &ValidationError{"Port", "structexp.min", "1", "0"},
+		},
+		{
+			Name:     "max",
+			String:   "GET 70000 example.com",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "GET", Port: 70000},
+			Error:    &ValidationError{"Port", "structexp.max", "65535", "70000"},
+		},
+		{
+			Name:     "match",
+			String:   "GET 80 Example.com",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "GET", Port: 80, Host: "Example.com"},
+			Error:    &ValidationError{"Host", "structexp.match", "^[a-z.]+$", "Example.com"},
+		},
+		{
+			Name:     "max length",
+			String:   "GET 80 example.com a,b,c",
+			Input:    &CheckedStruct{},
+			Expected: &CheckedStruct{Method: "GET", Port: 80, Host: "example.com", Tags: []string{"a", "b", "c"}},
+			Error:    &ValidationError{"Tags", "structexp.max", "2", "[a b c]"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestValidateCheck(t *testing.T) {
+	assert.NoError(t, Validate(CheckedStruct{}))
+
+	err := Validate(InvalidCheckStruct{})
+	var invalid *InvalidDefinition
+	if assert.ErrorAs(t, err, &invalid) {
+		assert.Equal(t, reflect.TypeOf(InvalidCheckStruct{}), invalid.Type)
+		assert.Len(t, invalid.Problems, 3)
+		for _, problem := range invalid.Problems {
+			assert.IsType(t, &InvalidTag{}, problem)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Tag keys read from the struct fields, as in package structexp
+const (
+	tagKey              = "structexp"
+	captureGroupNameKey = "structexp.name"
+	flagsKey            = "structexp.flags"
+	expKey              = "structexp.exp"
+	optionalKey         = "structexp.optional"
+	defaultKey          = "structexp.default"
+	ignoreCaseKey       = "structexp.ignorecase"
+	trimKey             = "structexp.trim"
+	onlyKey             = "structexp.only"
+)
+
+// Tag keys of features the generated Parse functions do not implement
+var unsupportedKeys = []string{
+	"structexp.sub", "structexp.split", "structexp.index", "structexp.raw",
+	"structexp.transform", "structexp.discriminator",
+	"structexp.min", "structexp.max", "structexp.oneof", "structexp.match",
+}
+
+// Default regular expressions of the field types, as in package structexp
+var defaultExps = map[string]string{
+	"bool":   `1|t|T|TRUE|true|True|0|f|F|FALSE|false|False`,
+	"int":    `[[:digit:]]+`,
+	"string": `[[:print:]]+`,
+}
+
+// wholeMatch is the capture group name of the whole match, as in package structexp
+const wholeMatch = "$0"
+
+// Methods of the template builders besides those of the fields
+var builderMethods = map[string]bool{"Literal": true, "Exp": true, "String": true}
+
+// structType is a struct type declared in the package
+type structType struct {
+	Name   string
+	Fields []structField
+	// The template of the first StructExp field, with its flags
+	Template    string
+	HasTemplate bool
+}
+
+// structField is a field of a struct type parsed from a capture group
+type structField struct {
+	Name  string
+	Group string
+	// The type of the field, as written in its declaration
+	Type       string
+	Tag        reflect.StructTag
+	Exp        string
+	Optional   bool
+	Default    string
+	IgnoreCase bool
+	Trim       string
+}
+
+// generate returns the formatted source of the code generated for the
+// struct types declared in the package in the directory, with their
+// Parse functions if parse is set
+func generate(dir string, typeNames []string, parse bool) ([]byte, error) {
+	pkgName, decls, err := parsePackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	funcs := &parseFuncs{imports: map[string]bool{}}
+	for _, name := range typeNames {
+		st, ok := decls[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+		t := newStructType(name, st)
+		for _, f := range t.Fields {
+			if builderMethods[f.Name] {
+				return nil, fmt.Errorf("field %s.%s conflicts with the %s method of %sTemplateBuilder", name, f.Name, f.Name, name)
+			}
+		}
+		writeTemplate(&b, t)
+		if parse {
+			if err := funcs.writeParse(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var src bytes.Buffer
+	args := "-type " + strings.Join(typeNames, ",")
+	if parse {
+		args = "-parse " + args
+	}
+	fmt.Fprintf(&src, "// Code generated by structexp-gen %s; DO NOT EDIT.\n\n", args)
+	fmt.Fprintf(&src, "package %s\n\n", pkgName)
+	src.WriteString(funcs.importBlock())
+	src.Write(b.Bytes())
+	src.Write(funcs.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// parsePackage parses the non-test Go files in the directory, returning
+// the package name and the struct types declared by name
+func parsePackage(dir string) (string, map[string]*ast.StructType, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pkgName string
+	decls := map[string]*ast.StructType{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || strings.HasSuffix(file, "_structexp.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkgName = f.Name.Name
+		ast.Inspect(f, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					decls[spec.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+	if pkgName == "" {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return pkgName, decls, nil
+}
+
+// newStructType lists the fields of the struct type parsed from capture groups
+func newStructType(name string, st *ast.StructType) structType {
+	t := structType{Name: name}
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			s, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(s)
+		}
+		if isStructExp(field.Type) {
+			if !t.HasTemplate {
+				t.Template, t.HasTemplate = tag.Get(tagKey), true
+				if flags := tag.Get(flagsKey); flags != "" {
+					t.Template = "(?" + flags + ")" + t.Template
+				}
+			}
+			continue
+		}
+		if tag.Get(tagKey) == "-" {
+			continue
+		}
+		// byte and rune fields are only parsed when explicitly tagged
+		if ident, ok := field.Type.(*ast.Ident); ok && tag.Get(captureGroupNameKey) == "" {
+			switch ident.Name {
+			case "byte", "uint8", "rune", "int32":
+				continue
+			}
+		}
+		for _, fieldName := range field.Names {
+			if !fieldName.IsExported() {
+				continue
+			}
+			group := fieldName.Name
+			if name := tag.Get(captureGroupNameKey); name != "" {
+				group = name
+			}
+			t.Fields = append(t.Fields, newStructField(fieldName.Name, group, field.Type, tag))
+		}
+	}
+	return t
+}
+
+// newStructField creates the field parsed from the capture group, reading its tags
+func newStructField(name, group string, typ ast.Expr, tag reflect.StructTag) structField {
+	f := structField{Name: name, Group: group, Type: types.ExprString(typ), Tag: tag}
+	f.Exp = defaultExps[f.Type]
+	if exp := tag.Get(expKey); exp != "" {
+		f.Exp = exp
+	}
+	// Invalid values are reported by structexp.Validate
+	f.Optional, _ = strconv.ParseBool(tag.Get(optionalKey))
+	f.Default = tag.Get(defaultKey)
+	f.IgnoreCase, _ = strconv.ParseBool(tag.Get(ignoreCaseKey))
+	if trim := tag.Get(trimKey); trim != "false" {
+		f.Trim = trim
+	}
+	return f
+}
+
+// namedCaptureGroup returns the capture group of the field, as in package structexp
+func (f structField) namedCaptureGroup() string {
+	exp := f.Exp
+	if f.IgnoreCase {
+		exp = "(?i:" + exp + ")"
+	}
+	if f.Optional {
+		return fmt.Sprintf("(?:(?P<%s>%s))?", f.Group, exp)
+	}
+	return fmt.Sprintf("(?P<%s>%s)", f.Group, exp)
+}
+
+// isStructExp reports whether the type expression is the StructExp type
+func isStructExp(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name == "StructExp"
+	case *ast.SelectorExpr:
+		return expr.Sel.Name == "StructExp"
+	}
+	return false
+}
+
+// writeTemplate writes the placeholders variable and template builder of the struct type
+func writeTemplate(b *bytes.Buffer, t structType) {
+	fmt.Fprintf(b, "\n// %sTemplate holds the placeholders of the capture groups of %s\n", t.Name, t.Name)
+	fmt.Fprintf(b, "var %sTemplate = struct {\n", t.Name)
+	for _, f := range t.Fields {
+		fmt.Fprintf(b, "%s string\n", f.Name)
+	}
+	b.WriteString("}{\n")
+	for _, f := range t.Fields {
+		fmt.Fprintf(b, "%s: %s,\n", f.Name, strconv.Quote("{{"+f.Group+"}}"))
+	}
+	b.WriteString("}\n")
+
+	builder := t.Name + "TemplateBuilder"
+	fmt.Fprintf(b, "\n// %s builds a template of %s from its placeholders\n", builder, t.Name)
+	fmt.Fprintf(b, "type %s struct {\nb strings.Builder\n}\n", builder)
+	fmt.Fprintf(b, "\n// Literal appends text matched literally\n")
+	fmt.Fprintf(b, "func (b *%s) Literal(s string) *%s {\nb.b.WriteString(regexp.QuoteMeta(s))\nreturn b\n}\n", builder, builder)
+	fmt.Fprintf(b, "\n// Exp appends a regular expression\n")
+	fmt.Fprintf(b, "func (b *%s) Exp(exp string) *%s {\nb.b.WriteString(exp)\nreturn b\n}\n", builder, builder)
+	for _, f := range t.Fields {
+		fmt.Fprintf(b, "\n// %s appends the placeholder of the %s field\n", f.Name, f.Name)
+		fmt.Fprintf(b, "func (b *%s) %s() *%s {\nb.b.WriteString(%sTemplate.%s)\nreturn b\n}\n", builder, f.Name, builder, t.Name, f.Name)
+	}
+	fmt.Fprintf(b, "\n// String returns the template\n")
+	fmt.Fprintf(b, "func (b *%s) String() string {\nreturn b.b.String()\n}\n", builder)
+}
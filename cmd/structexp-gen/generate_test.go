@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("testdata/logline", []string{"LogLine"}, false)
+	require.NoError(t, err)
+
+	golden, err := os.ReadFile("testdata/logline/logline_structexp.go.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(src))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := generate("testdata/logline", []string{"Missing"}, false)
+	assert.EqualError(t, err, "struct type Missing not found in testdata/logline")
+
+	_, err = generate("testdata/logline", []string{"Conflict"}, false)
+	assert.EqualError(t, err, "field Conflict.String conflicts with the String method of ConflictTemplateBuilder")
+}
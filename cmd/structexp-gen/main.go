@@ -0,0 +1,68 @@
+// Command structexp-gen generates code for structexp structs, to run with
+// go:generate from the package declaring them:
+//
+//	//go:generate structexp-gen -type LogLine
+//
+// For each struct type, it writes the placeholders of its fields' capture
+// groups as a variable, such as LogLineTemplate.Status, and a builder of
+// templates from them, such as LogLineTemplateBuilder, so that templates
+// built in code cannot reference fields that do not exist.
+//
+// With the -parse flag, it also writes a ParseT function of each struct type
+// T, such as ParseLogLine, and its regular expression as a package variable,
+// compiled once like the Parser of structexp.Compile. The function assigns the
+// fields directly, without reflection, for performance critical services.
+// It supports string, int, and bool fields, with the structexp.name,
+// structexp.exp, structexp.optional, structexp.default, structexp.ignorecase,
+// structexp.trim, and structexp.only tags, parsed with the template of the
+// first StructExp field. Other field types and tags, such as structexp.sub,
+// are reported as errors, rather than parsed differently than structexp.Parse.
+// The function returns the errors of structexp.Parse, a structexp.NoMatch
+// with the pattern and input, or a structexp.FieldError of the field that
+// fails to convert.
+//
+// Only the fields declared in the struct itself are read, and fields with
+// the structexp:"-" tag, StructExp fields, and embedded fields are skipped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma separated list of struct type names; required")
+	output := flag.String("output", "", "output file name; default <type>_structexp.go of the first type")
+	parse := flag.Bool("parse", false, "also generate a Parse function of each type, without reflection")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: structexp-gen -type T[,T...] [-parse] [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(types[0])+"_structexp.go")
+	}
+
+	src, err := generate(dir, types, *parse)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "structexp-gen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "structexp-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
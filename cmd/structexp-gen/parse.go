@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// placeholderRegexp matches the {{name}} and {{name:token}} placeholders, as in package structexp
+var placeholderRegexp = regexp.MustCompile(`{{([^{}:]+)(?::([^{}]*))?}}`)
+
+// parseFuncs is the code of the Parse functions of the struct types,
+// and the packages it imports besides regexp and strings
+type parseFuncs struct {
+	bytes.Buffer
+	imports map[string]bool
+}
+
+// fillTemplate returns the regular expression of the struct type, with the
+// capture groups of its fields, as in package structexp, and the fields
+// set from it. Fields without a placeholder are not set.
+func fillTemplate(t structType) (*regexp.Regexp, []structField, error) {
+	if !t.HasTemplate {
+		return nil, nil, fmt.Errorf("struct type %s has no StructExp field", t.Name)
+	}
+
+	pending := map[string]structField{}
+	var set []structField
+	for _, f := range t.Fields {
+		if f.Tag.Get(onlyKey) == "format" {
+			continue
+		}
+		for _, key := range unsupportedKeys {
+			if _, ok := f.Tag.Lookup(key); ok {
+				return nil, nil, fmt.Errorf("field %s.%s has the %s tag, which generated Parse functions do not support", t.Name, f.Name, key)
+			}
+		}
+		if _, ok := defaultExps[f.Type]; !ok {
+			return nil, nil, fmt.Errorf("field %s.%s has type %s, which generated Parse functions do not support", t.Name, f.Name, f.Type)
+		}
+		if other, ok := pending[f.Group]; ok {
+			return nil, nil, fmt.Errorf("fields %s.%s and %s.%s have the same capture group name %s", t.Name, other.Name, t.Name, f.Name, f.Group)
+		}
+		pending[f.Group] = f
+	}
+
+	var err error
+	exp := placeholderRegexp.ReplaceAllStringFunc(t.Template, func(placeholder string) string {
+		submatches := placeholderRegexp.FindStringSubmatch(placeholder)
+		name, tokenName := submatches[1], submatches[2]
+		f, ok := pending[name]
+		switch {
+		case err != nil:
+		case !ok || name == wholeMatch:
+			err = fmt.Errorf("placeholder %s of %s has no field", placeholder, t.Name)
+		case tokenName != "":
+			err = fmt.Errorf("placeholder %s of %s has a token, which generated Parse functions do not support", placeholder, t.Name)
+		default:
+			delete(pending, name)
+			set = append(set, f)
+			return f.namedCaptureGroup()
+		}
+		return placeholder
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	// Bound to the whole match, the field has no placeholder
+	if f, ok := pending[wholeMatch]; ok {
+		set = append(set, f)
+	}
+	regxp, err := regexp.Compile(exp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("regular expression of %s: %w", t.Name, err)
+	}
+
+	// Set in the order of the struct's fields, as in package structexp
+	var ordered []structField
+	for _, f := range t.Fields {
+		for _, s := range set {
+			if s.Name == f.Name {
+				ordered = append(ordered, f)
+				break
+			}
+		}
+	}
+	return regxp, ordered, nil
+}
+
+// writeParse writes the regular expression variable and Parse function of the struct type
+func (p *parseFuncs) writeParse(t structType) error {
+	regxp, fields, err := fillTemplate(t)
+	if err != nil {
+		return err
+	}
+
+	regexpVar := unexported(t.Name) + "Regexp"
+	fmt.Fprintf(p, "\n// %s is the regular expression of %s\n", regexpVar, t.Name)
+	fmt.Fprintf(p, "var %s = regexp.MustCompile(%s)\n", regexpVar, quote(regxp.String()))
+
+	fmt.Fprintf(p, "\n// Parse%s parses the string into %s %s, like structexp.Parse, but\n", t.Name, article(t.Name), t.Name)
+	fmt.Fprintf(p, "// without reflection. The errors are those of structexp.Parse: a structexp.NoMatch,\n")
+	fmt.Fprintf(p, "// or a structexp.FieldError wrapping the error of package strconv.\n")
+	fmt.Fprintf(p, "func Parse%s(input string) (%s, error) {\n", t.Name, t.Name)
+	fmt.Fprintf(p, "var v %s\n", t.Name)
+	fmt.Fprintf(p, "matches := %s.FindStringSubmatch(input)\n", regexpVar)
+	fmt.Fprintf(p, "if matches == nil {\nreturn v, structexp.NewNoMatch(%s, input)\n}\n", regexpVar)
+	p.imports["github.com/densestvoid/structexp"] = true
+
+	// Converted into locals, so that a failed conversion leaves the field unset
+	locals := map[string]bool{}
+	for _, f := range fields {
+		locals[f.Type] = true
+	}
+	if locals["int"] || locals["bool"] {
+		p.imports["strconv"] = true
+		p.WriteString("var err error\n")
+	}
+	if locals["int"] {
+		p.imports["errors"] = true
+		p.imports["reflect"] = true
+		p.WriteString("var n int64\n")
+	}
+	if locals["bool"] {
+		p.WriteString("var b bool\n")
+	}
+	if len(fields) > 0 {
+		p.WriteString("var s string\n")
+	}
+
+	for _, f := range fields {
+		group := 0
+		if f.Group != wholeMatch {
+			group = regxp.SubexpIndex(f.Group)
+		}
+		fmt.Fprintf(p, "\n// %s\n", f.Name)
+		fmt.Fprintf(p, "s = matches[%d]\n", group)
+		switch f.Trim {
+		case "":
+		case "true":
+			p.WriteString("s = strings.TrimSpace(s)\n")
+		default:
+			fmt.Fprintf(p, "s = strings.Trim(s, %s)\n", strconv.Quote(f.Trim))
+		}
+		// An empty optional capture keeps the zero value, unless defaulted
+		skipEmpty := f.Optional && f.Default == ""
+		if f.Default != "" {
+			fmt.Fprintf(p, "if s == \"\" {\ns = %s\n}\n", strconv.Quote(f.Default))
+		}
+		if skipEmpty {
+			p.WriteString("if s != \"\" {\n")
+		}
+		switch f.Type {
+		case "string":
+			fmt.Fprintf(p, "v.%s = s\n", f.Name)
+		case "int":
+			fmt.Fprintf(p, "if n, err = strconv.ParseInt(s, 10, strconv.IntSize); err != nil {\n")
+			fmt.Fprintf(p, "if errors.Is(err, strconv.ErrRange) {\n")
+			fmt.Fprintf(p, "err = &structexp.OutOfRange{Type: reflect.TypeOf(v.%s), Value: s, Err: err}\n}\n", f.Name)
+			p.writeFieldError(f)
+			fmt.Fprintf(p, "}\nv.%s = int(n)\n", f.Name)
+		case "bool":
+			fmt.Fprintf(p, "if b, err = strconv.ParseBool(s); err != nil {\n")
+			p.writeFieldError(f)
+			fmt.Fprintf(p, "}\nv.%s = b\n", f.Name)
+		}
+		if skipEmpty {
+			p.WriteString("}\n")
+		}
+	}
+	p.WriteString("return v, nil\n}\n")
+	return nil
+}
+
+// writeFieldError writes the return of the FieldError of the field,
+// wrapping err, the error converting its value s
+func (p *parseFuncs) writeFieldError(f structField) {
+	fmt.Fprintf(p, "return v, &structexp.FieldError{Field: %s, Group: %s, Value: s, Err: err}\n",
+		strconv.Quote(f.Name), strconv.Quote(f.Group))
+}
+
+// unexported returns the name with its first letter in lower case
+func unexported(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// article returns the indefinite article of the name
+func article(name string) string {
+	if strings.ContainsRune("AEIOU", rune(name[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+// quote returns the string as a raw string literal if possible
+func quote(s string) string {
+	if strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// importBlock returns the import declaration of regexp and strings, and the imports
+func (p *parseFuncs) importBlock() string {
+	var std []string
+	for _, path := range []string{"errors", "reflect", "regexp", "strconv", "strings"} {
+		if p.imports[path] || path == "regexp" || path == "strings" {
+			std = append(std, path)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, path := range std {
+		fmt.Fprintf(&b, "%s\n", strconv.Quote(path))
+	}
+	if p.imports["github.com/densestvoid/structexp"] {
+		b.WriteString("\n\"github.com/densestvoid/structexp\"\n")
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
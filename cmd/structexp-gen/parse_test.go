@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/densestvoid/structexp/cmd/structexp-gen/testdata/access"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateParse(t *testing.T) {
+	src, err := generate("testdata/access", []string{"Access", "Check"}, true)
+	require.NoError(t, err)
+
+	// The generated file is compiled into the access package, for TestParseFuncs
+	generated, err := os.ReadFile("testdata/access/access_structexp.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(generated), string(src))
+}
+
+func TestParseFuncs(t *testing.T) {
+	inputs := []string{
+		"10.0.0.1 GET /index.html 200",
+		"10.0.0.1 Post /login 302 true user=-alice-",
+		"::1 get / 404 FALSE",
+		"::1 get / 404 user=",
+		"::1 get / 99999999999999999999",
+		"10.0.0.1 PUT /index.html 200",
+		"",
+	}
+	for _, input := range inputs {
+		var want access.Access
+		wantErr := structexp.Parse(input, &want)
+		got, err := access.ParseAccess(input)
+		assert.Equal(t, want, got, input)
+		assert.EqualValues(t, wantErr, err, input)
+	}
+
+	for _, input := range []string{"disk: true", "disk: 0", "disk: yes"} {
+		var want access.Check
+		wantErr := structexp.Parse(input, &want)
+		got, err := access.ParseCheck(input)
+		assert.Equal(t, want, got, input)
+		assert.EqualValues(t, wantErr, err, input)
+	}
+}
+
+func TestGenerateParseErrors(t *testing.T) {
+	_, err := generate("testdata/logline", []string{"Conflict"}, true)
+	assert.EqualError(t, err, "field Conflict.String conflicts with the String method of ConflictTemplateBuilder")
+
+	_, err = generate("testdata/unsupported", []string{"NoTemplate"}, true)
+	assert.EqualError(t, err, "struct type NoTemplate has no StructExp field")
+
+	_, err = generate("testdata/unsupported", []string{"SubParser"}, true)
+	assert.EqualError(t, err, "field SubParser.Fields has the structexp.sub tag, which generated Parse functions do not support")
+
+	_, err = generate("testdata/unsupported", []string{"Timestamp"}, true)
+	assert.EqualError(t, err, "field Timestamp.At has type time.Time, which generated Parse functions do not support")
+
+	_, err = generate("testdata/unsupported", []string{"Token"}, true)
+	assert.EqualError(t, err, "placeholder {{at:rfc3339}} of Token has a token, which generated Parse functions do not support")
+
+	_, err = generate("testdata/unsupported", []string{"Duplicate"}, true)
+	assert.EqualError(t, err, "fields Duplicate.A and Duplicate.B have the same capture group name x")
+
+	_, err = generate("testdata/unsupported", []string{"Unplaced"}, true)
+	assert.EqualError(t, err, "placeholder {{missing}} of Unplaced has no field")
+}
+
+func BenchmarkParseFunc(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := access.ParseAccess("10.0.0.1 Post /login 302 true user=-alice-"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserParse(b *testing.B) {
+	parser, err := structexp.Compile[access.Access]()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Parse("10.0.0.1 Post /login 302 true user=-alice-"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
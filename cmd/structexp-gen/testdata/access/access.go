@@ -0,0 +1,24 @@
+package access
+
+import "github.com/densestvoid/structexp"
+
+//go:generate structexp-gen -parse -type Access,Check
+
+type Access struct {
+	structexp.StructExp `structexp:"^{{client}} {{method}} {{path}} {{status}}(?: {{cached}})?(?: user={{user}})?$" structexp.flags:"i"`
+	Line                string `structexp.name:"$0"`
+	Client              string `structexp.name:"client" structexp.exp:"[0-9a-f.:]+"`
+	Method              string `structexp.name:"method" structexp.exp:"get|post" structexp.ignorecase:"true"`
+	Path                string `structexp.name:"path" structexp.exp:"/\\S*"`
+	Status              int    `structexp.name:"status"`
+	Cached              bool   `structexp.name:"cached" structexp.exp:"true|false" structexp.default:"false"`
+	User                string `structexp.name:"user" structexp.exp:"[^ ]*" structexp.optional:"true" structexp.trim:"-"`
+	Rendered            string `structexp.only:"format"`
+	Unused              string
+}
+
+type Check struct {
+	structexp.StructExp `structexp:"^{{name}}: {{ok}}$"`
+	Name                string `structexp.name:"name" structexp.exp:"\\w+"`
+	OK                  bool   `structexp.name:"ok"`
+}
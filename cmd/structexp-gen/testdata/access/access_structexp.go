@@ -0,0 +1,246 @@
+// Code generated by structexp-gen -parse -type Access,Check; DO NOT EDIT.
+
+package access
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/densestvoid/structexp"
+)
+
+// AccessTemplate holds the placeholders of the capture groups of Access
+var AccessTemplate = struct {
+	Line     string
+	Client   string
+	Method   string
+	Path     string
+	Status   string
+	Cached   string
+	User     string
+	Rendered string
+	Unused   string
+}{
+	Line:     "{{$0}}",
+	Client:   "{{client}}",
+	Method:   "{{method}}",
+	Path:     "{{path}}",
+	Status:   "{{status}}",
+	Cached:   "{{cached}}",
+	User:     "{{user}}",
+	Rendered: "{{Rendered}}",
+	Unused:   "{{Unused}}",
+}
+
+// AccessTemplateBuilder builds a template of Access from its placeholders
+type AccessTemplateBuilder struct {
+	b strings.Builder
+}
+
+// Literal appends text matched literally
+func (b *AccessTemplateBuilder) Literal(s string) *AccessTemplateBuilder {
+	b.b.WriteString(regexp.QuoteMeta(s))
+	return b
+}
+
+// Exp appends a regular expression
+func (b *AccessTemplateBuilder) Exp(exp string) *AccessTemplateBuilder {
+	b.b.WriteString(exp)
+	return b
+}
+
+// Line appends the placeholder of the Line field
+func (b *AccessTemplateBuilder) Line() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Line)
+	return b
+}
+
+// Client appends the placeholder of the Client field
+func (b *AccessTemplateBuilder) Client() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Client)
+	return b
+}
+
+// Method appends the placeholder of the Method field
+func (b *AccessTemplateBuilder) Method() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Method)
+	return b
+}
+
+// Path appends the placeholder of the Path field
+func (b *AccessTemplateBuilder) Path() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Path)
+	return b
+}
+
+// Status appends the placeholder of the Status field
+func (b *AccessTemplateBuilder) Status() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Status)
+	return b
+}
+
+// Cached appends the placeholder of the Cached field
+func (b *AccessTemplateBuilder) Cached() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Cached)
+	return b
+}
+
+// User appends the placeholder of the User field
+func (b *AccessTemplateBuilder) User() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.User)
+	return b
+}
+
+// Rendered appends the placeholder of the Rendered field
+func (b *AccessTemplateBuilder) Rendered() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Rendered)
+	return b
+}
+
+// Unused appends the placeholder of the Unused field
+func (b *AccessTemplateBuilder) Unused() *AccessTemplateBuilder {
+	b.b.WriteString(AccessTemplate.Unused)
+	return b
+}
+
+// String returns the template
+func (b *AccessTemplateBuilder) String() string {
+	return b.b.String()
+}
+
+// CheckTemplate holds the placeholders of the capture groups of Check
+var CheckTemplate = struct {
+	Name string
+	OK   string
+}{
+	Name: "{{name}}",
+	OK:   "{{ok}}",
+}
+
+// CheckTemplateBuilder builds a template of Check from its placeholders
+type CheckTemplateBuilder struct {
+	b strings.Builder
+}
+
+// Literal appends text matched literally
+func (b *CheckTemplateBuilder) Literal(s string) *CheckTemplateBuilder {
+	b.b.WriteString(regexp.QuoteMeta(s))
+	return b
+}
+
+// Exp appends a regular expression
+func (b *CheckTemplateBuilder) Exp(exp string) *CheckTemplateBuilder {
+	b.b.WriteString(exp)
+	return b
+}
+
+// Name appends the placeholder of the Name field
+func (b *CheckTemplateBuilder) Name() *CheckTemplateBuilder {
+	b.b.WriteString(CheckTemplate.Name)
+	return b
+}
+
+// OK appends the placeholder of the OK field
+func (b *CheckTemplateBuilder) OK() *CheckTemplateBuilder {
+	b.b.WriteString(CheckTemplate.OK)
+	return b
+}
+
+// String returns the template
+func (b *CheckTemplateBuilder) String() string {
+	return b.b.String()
+}
+
+// accessRegexp is the regular expression of Access
+var accessRegexp = regexp.MustCompile(`(?i)^(?P<client>[0-9a-f.:]+) (?P<method>(?i:get|post)) (?P<path>/\S*) (?P<status>[[:digit:]]+)(?: (?P<cached>true|false))?(?: user=(?:(?P<user>[^ ]*))?)?$`)
+
+// ParseAccess parses the string into an Access, like structexp.Parse, but
+// without reflection. The errors are those of structexp.Parse: a structexp.NoMatch,
+// or a structexp.FieldError wrapping the error of package strconv.
+func ParseAccess(input string) (Access, error) {
+	var v Access
+	matches := accessRegexp.FindStringSubmatch(input)
+	if matches == nil {
+		return v, structexp.NewNoMatch(accessRegexp, input)
+	}
+	var err error
+	var n int64
+	var b bool
+	var s string
+
+	// Line
+	s = matches[0]
+	v.Line = s
+
+	// Client
+	s = matches[1]
+	v.Client = s
+
+	// Method
+	s = matches[2]
+	v.Method = s
+
+	// Path
+	s = matches[3]
+	v.Path = s
+
+	// Status
+	s = matches[4]
+	if n, err = strconv.ParseInt(s, 10, strconv.IntSize); err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			err = &structexp.OutOfRange{Type: reflect.TypeOf(v.Status), Value: s, Err: err}
+		}
+		return v, &structexp.FieldError{Field: "Status", Group: "status", Value: s, Err: err}
+	}
+	v.Status = int(n)
+
+	// Cached
+	s = matches[5]
+	if s == "" {
+		s = "false"
+	}
+	if b, err = strconv.ParseBool(s); err != nil {
+		return v, &structexp.FieldError{Field: "Cached", Group: "cached", Value: s, Err: err}
+	}
+	v.Cached = b
+
+	// User
+	s = matches[6]
+	s = strings.Trim(s, "-")
+	if s != "" {
+		v.User = s
+	}
+	return v, nil
+}
+
+// checkRegexp is the regular expression of Check
+var checkRegexp = regexp.MustCompile(`^(?P<name>\w+): (?P<ok>1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)$`)
+
+// ParseCheck parses the string into a Check, like structexp.Parse, but
+// without reflection. The errors are those of structexp.Parse: a structexp.NoMatch,
+// or a structexp.FieldError wrapping the error of package strconv.
+func ParseCheck(input string) (Check, error) {
+	var v Check
+	matches := checkRegexp.FindStringSubmatch(input)
+	if matches == nil {
+		return v, structexp.NewNoMatch(checkRegexp, input)
+	}
+	var err error
+	var b bool
+	var s string
+
+	// Name
+	s = matches[1]
+	v.Name = s
+
+	// OK
+	s = matches[2]
+	if b, err = strconv.ParseBool(s); err != nil {
+		return v, &structexp.FieldError{Field: "OK", Group: "ok", Value: s, Err: err}
+	}
+	v.OK = b
+	return v, nil
+}
@@ -0,0 +1,17 @@
+package logline
+
+import "github.com/densestvoid/structexp"
+
+type LogLine struct {
+	structexp.StructExp `structexp:"^{{ts}} {{status}} {{Path}}$"`
+	TS                  string `structexp.name:"ts" structexp.exp:"[0-9:T-]+"`
+	Status              int    `structexp.name:"status"`
+	Path                string
+	Flag                byte
+	Ignored             string `structexp:"-"`
+	internal            string
+}
+
+type Conflict struct {
+	String string
+}
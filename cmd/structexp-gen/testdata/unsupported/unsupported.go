@@ -0,0 +1,37 @@
+package unsupported
+
+import (
+	"time"
+
+	"github.com/densestvoid/structexp"
+)
+
+type NoTemplate struct {
+	Name string
+}
+
+type SubParser struct {
+	structexp.StructExp `structexp:"^{{fields}}$"`
+	Fields              map[string]string `structexp.name:"fields" structexp.sub:"kv"`
+}
+
+type Timestamp struct {
+	structexp.StructExp `structexp:"^{{At}}$"`
+	At                  time.Time
+}
+
+type Token struct {
+	structexp.StructExp `structexp:"^{{at:rfc3339}}$"`
+	At                  string `structexp.name:"at"`
+}
+
+type Duplicate struct {
+	structexp.StructExp `structexp:"^{{x}}$"`
+	A                   string `structexp.name:"x"`
+	B                   string `structexp.name:"x"`
+}
+
+type Unplaced struct {
+	structexp.StructExp `structexp:"^{{missing}}$"`
+	Name                string
+}
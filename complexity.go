@@ -0,0 +1,88 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"regexp"
+	"regexp/syntax"
+)
+
+// Complexity limits, as reported by TooComplex
+const (
+	ProgramSizeLimit = "program size"
+	GroupsLimit      = "capture groups"
+)
+
+// programSize is the number of instructions in the compiled program of the regular expression
+func programSize(regxp *regexp.Regexp) (int, error) {
+	exp, err := syntax.Parse(regxp.String(), syntax.Perl)
+	if err != nil {
+		return 0, err
+	}
+	prog, err := syntax.Compile(exp.Simplify())
+	if err != nil {
+		return 0, err
+	}
+	return len(prog.Inst), nil
+}
+
+// checkComplexity verifies the regular expression is within the complexity
+// limits of the options, and does not use any construct they ban
+func checkComplexity(regxp *regexp.Regexp, o *options) error {
+	if o.maxRepeat > 0 || o.requireAnchors {
+		exp, err := syntax.Parse(regxp.String(), syntax.Perl)
+		if err != nil {
+			return err
+		}
+		if o.requireAnchors && !isAnchored(exp) {
+			return &Unanchored{regxp.String()}
+		}
+		if o.maxRepeat > 0 {
+			if err := checkRepeats(exp, o.maxRepeat); err != nil {
+				return err
+			}
+		}
+	}
+
+	if o.maxGroups > 0 && regxp.NumSubexp() > o.maxGroups {
+		return &TooComplex{GroupsLimit, regxp.NumSubexp(), o.maxGroups}
+	}
+	if o.maxProgramSize > 0 {
+		size, err := programSize(regxp)
+		if err != nil {
+			return err
+		}
+		if size > o.maxProgramSize {
+			return &TooComplex{ProgramSizeLimit, size, o.maxProgramSize}
+		}
+	}
+	return nil
+}
+
+// isAnchored reports whether the expression begins and ends with text anchors
+func isAnchored(exp *syntax.Regexp) bool {
+	if exp.Op != syntax.OpConcat || len(exp.Sub) < 2 {
+		return false
+	}
+	first, last := exp.Sub[0], exp.Sub[len(exp.Sub)-1]
+	return first.Op == syntax.OpBeginText && last.Op == syntax.OpEndText
+}
+
+// checkRepeats verifies every counted repetition in the expression is at most max
+func checkRepeats(exp *syntax.Regexp, max int) error {
+	if exp.Op == syntax.OpRepeat && (exp.Min > max || exp.Max > max) {
+		return &BannedConstruct{exp.String()}
+	}
+	for _, sub := range exp.Sub {
+		if err := checkRepeats(sub, max); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkInputSize verifies the input is within the size limit of the options
+func checkInputSize(size int, o *options) error {
+	if o.maxInputSize > 0 && size > o.maxInputSize {
+		return &InputTooLarge{size, o.maxInputSize}
+	}
+	return nil
+}
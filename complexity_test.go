@@ -0,0 +1,119 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplexityLimits(t *testing.T) {
+	type TestCase struct {
+		Name    string
+		Options []Option
+		Error   error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:    "WithinLimits",
+			Options: []Option{MaxGroups(2), MaxProgramSize(100)},
+			Error:   nil,
+		},
+		{
+			Name:    "MaxGroupsError",
+			Options: []Option{MaxGroups(1)},
+			Error:   &TooComplex{GroupsLimit, 2, 1},
+		},
+		{
+			Name:    "MaxProgramSizeError",
+			Options: []Option{MaxProgramSize(5)},
+			Error:   &TooComplex{ProgramSizeLimit, 19, 5},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := Compile[ParentHeaderStruct](tc.Options...)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+type RepeatStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test" structexp.exp:"[a-z]{1,500}"`
+}
+
+func TestSandboxLimits(t *testing.T) {
+	type TestCase struct {
+		Name    string
+		Compile func(...Option) error
+		Options []Option
+		Error   error
+	}
+
+	compileInt := func(opts ...Option) error {
+		_, err := Compile[Int](opts...)
+		return err
+	}
+	compileRepeat := func(opts ...Option) error {
+		_, err := Compile[RepeatStruct](opts...)
+		return err
+	}
+
+	testCases := []TestCase{
+		{
+			Name:    "RequireAnchorsError",
+			Compile: compileInt,
+			Options: []Option{RequireAnchors()},
+			Error:   &Unanchored{`(?P<test>[[:digit:]]+)`},
+		},
+		{
+			Name:    "MaxRepeatError",
+			Compile: compileRepeat,
+			Options: []Option{MaxRepeat(100)},
+			Error:   &BannedConstruct{`[a-z]{1,500}`},
+		},
+		{
+			Name:    "MaxRepeatWithinLimit",
+			Compile: compileRepeat,
+			Options: []Option{MaxRepeat(500), RequireAnchors()},
+			Error:   nil,
+		},
+		{
+			Name:    "UntrustedTemplatesError",
+			Compile: compileRepeat,
+			Options: []Option{WithUntrustedTemplates()},
+			Error:   &BannedConstruct{`[a-z]{1,500}`},
+		},
+		{
+			Name:    "UntrustedTemplatesOverride",
+			Compile: compileRepeat,
+			Options: []Option{WithUntrustedTemplates(), MaxRepeat(500)},
+			Error:   nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.EqualValues(t, tc.Error, tc.Compile(tc.Options...))
+		})
+	}
+}
+
+func TestMaxInputSize(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct](MaxInputSize(8))
+
+	_, err := parser.Parse("[WARN: 12] 34")
+	assert.EqualValues(t, &InputTooLarge{13, 8}, err)
+	_, err = parser.ParseAll("[WARN: 12] 34")
+	assert.EqualValues(t, &InputTooLarge{13, 8}, err)
+	_, err = parser.ParseBytes([]byte("[WARN: 12] 34"))
+	assert.EqualValues(t, &InputTooLarge{13, 8}, err)
+
+	value, err := parser.Parse("[I: 1] 2")
+	assert.NoError(t, err)
+	assert.EqualValues(t, ParentHeaderStruct{Header: Header{Level: "I", Code: 1}, Code: 2}, value)
+}
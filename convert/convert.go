@@ -0,0 +1,137 @@
+// Package convert converts strings into typed values. It is the conversion
+// engine structexp sets fields with, whichever way their strings are found,
+// such as by a regular expression or as key=value pairs, and can be reused
+// by other packages extracting strings from text.
+package convert
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Parsable is implemented by types converting themselves from a string,
+// with the same method as structexp.ParsableField
+type Parsable interface {
+	Parse(string) error
+}
+
+var (
+	parsableType        = reflect.TypeOf((*Parsable)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Unsupported occurs when a value's type has no conversion from a string
+type Unsupported struct {
+	reflect.Type
+}
+
+func (err *Unsupported) Error() string {
+	return fmt.Sprintf("no conversion from a string to %v", err.Type)
+}
+
+// Unsettable occurs when the value to convert into cannot be set
+type Unsettable struct {
+	reflect.Type
+}
+
+func (err *Unsettable) Error() string {
+	return fmt.Sprintf("value of type %v cannot be set", err.Type)
+}
+
+// Implements reports whether a pointer to the type converts itself from a
+// string, by implementing Parsable or encoding.TextUnmarshaler
+func Implements(t reflect.Type) bool {
+	ptr := reflect.PtrTo(t)
+	return ptr.Implements(parsableType) || ptr.Implements(textUnmarshalerType)
+}
+
+// Supports reports whether Set can convert a string into the type
+func Supports(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if Implements(t) {
+		return true
+	}
+	// nolint:exhaustive // unnecessary
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Set converts the string into the settable value. Nil pointers are
+// allocated, and the value they point to is set. Conversions are, in order:
+//  - types implementing Parsable with a pointer receiver, with Parse
+//  - types implementing encoding.TextUnmarshaler with a pointer receiver, with UnmarshalText
+//  - bool, with strconv.ParseBool
+//  - signed and unsigned integers, with strconv.ParseInt and strconv.ParseUint
+//    in base 10, sized to the type
+//  - floats, with strconv.ParseFloat sized to the type
+//  - strings, unchanged
+//
+// Errors occur if the value is not settable, its type is unsupported,
+// or the conversion fails.
+func Set(v reflect.Value, s string) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !v.CanSet() {
+				return &Unsettable{v.Type()}
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if !v.CanSet() {
+		return &Unsettable{v.Type()}
+	}
+
+	if v.CanAddr() {
+		switch i := v.Addr().Interface().(type) {
+		case Parsable:
+			return i.Parse(s)
+		case encoding.TextUnmarshaler:
+			return i.UnmarshalText([]byte(s))
+		}
+	}
+
+	// nolint:exhaustive // unnecessary
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		v.SetString(s)
+	default:
+		return &Unsupported{v.Type()}
+	}
+	return nil
+}
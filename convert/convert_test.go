@@ -0,0 +1,84 @@
+package convert
+
+import (
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type upper string
+
+func (u *upper) Parse(s string) error {
+	*u = upper(s + "!")
+	return nil
+}
+
+func TestSet(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{Name: "Bool", String: "true", Input: new(bool), Expected: func() *bool { b := true; return &b }()},
+		{Name: "Int8", String: "-12", Input: new(int8), Expected: func() *int8 { i := int8(-12); return &i }()},
+		{Name: "Uint16", String: "65535", Input: new(uint16), Expected: func() *uint16 { u := uint16(65535); return &u }()},
+		{Name: "Float32", String: "1.5", Input: new(float32), Expected: func() *float32 { f := float32(1.5); return &f }()},
+		{Name: "String", String: "a b", Input: new(string), Expected: func() *string { s := "a b"; return &s }()},
+		{Name: "Parsable", String: "a", Input: new(upper), Expected: func() *upper { u := upper("a!"); return &u }()},
+		{
+			Name:     "TextUnmarshaler",
+			String:   "10.0.0.1",
+			Input:    new(netip.Addr),
+			Expected: func() *netip.Addr { a := netip.MustParseAddr("10.0.0.1"); return &a }(),
+		},
+		{
+			Name:     "NilPointer",
+			String:   "3",
+			Input:    new(*int),
+			Expected: func() **int { i := 3; p := &i; return &p }(),
+		},
+		{
+			Name:     "OverflowError",
+			String:   "128",
+			Input:    new(int8),
+			Expected: new(int8),
+			Error:    &strconv.NumError{Func: "ParseInt", Num: "128", Err: strconv.ErrRange},
+		},
+		{
+			Name:     "UnsupportedError",
+			String:   "a",
+			Input:    new(chan int),
+			Expected: new(chan int),
+			Error:    &Unsupported{reflect.TypeOf(make(chan int))},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Set(reflect.ValueOf(tc.Input).Elem(), tc.String)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestSetUnsettable(t *testing.T) {
+	assert.EqualValues(t, &Unsettable{reflect.TypeOf(0)}, Set(reflect.ValueOf(0), "1"))
+}
+
+func TestSupports(t *testing.T) {
+	assert.True(t, Supports(reflect.TypeOf(0)))
+	assert.True(t, Supports(reflect.TypeOf(new(float64))))
+	assert.True(t, Supports(reflect.TypeOf(upper(""))))
+	assert.True(t, Supports(reflect.TypeOf(netip.Addr{})))
+	assert.False(t, Supports(reflect.TypeOf(struct{}{})))
+	assert.False(t, Supports(reflect.TypeOf([]int{})))
+}
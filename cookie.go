@@ -0,0 +1,123 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expiry date formats accepted for the Set-Cookie Expires attribute
+var cookieTimeFormats = []string{
+	time.RFC1123,
+	"Mon, 02-Jan-2006 15:04:05 MST",
+	time.ANSIC,
+}
+
+// Cookies is a ParsableField for the value of a Cookie header,
+// such as a=1; b="two", mapping each cookie name to its value
+type Cookies map[string]string
+
+// Parse the Cookie header value into the cookie names and values
+func (c *Cookies) Parse(s string) error {
+	pairs := splitCookie(s)
+	*c = make(Cookies, len(pairs))
+	for _, pair := range pairs {
+		(*c)[pair.Key] = pair.Value
+	}
+	return nil
+}
+
+// SetCookie is a ParsableField for the value of a Set-Cookie header,
+// such as id=a3f; Path=/; Max-Age=60; Secure; HttpOnly
+type SetCookie struct {
+	Name     string
+	Value    string
+	Path     string
+	Domain   string
+	Expires  time.Time
+	MaxAge   int
+	Secure   bool
+	HTTPOnly bool
+	SameSite string
+	// Attributes holds any other attributes, by their lower case names
+	Attributes map[string]string
+}
+
+// Parse the Set-Cookie header value into the cookie and its attributes.
+// Attribute names are case insensitive.
+//
+// Errors occur if:
+//  - the header value does not start with a cookie name and value
+//  - the Expires or Max-Age attributes have invalid values
+func (c *SetCookie) Parse(s string) error {
+	pairs := splitCookie(s)
+	if len(pairs) == 0 || pairs[0].Key == "" {
+		return &InvalidCookie{s}
+	}
+
+	*c = SetCookie{Name: pairs[0].Key, Value: pairs[0].Value}
+	for _, attr := range pairs[1:] {
+		switch name := strings.ToLower(attr.Key); name {
+		case "path":
+			c.Path = attr.Value
+		case "domain":
+			c.Domain = strings.TrimPrefix(attr.Value, ".")
+		case "expires":
+			expires, err := parseCookieTime(attr.Value)
+			if err != nil {
+				return err
+			}
+			c.Expires = expires
+		case "max-age":
+			maxAge, err := strconv.Atoi(attr.Value)
+			if err != nil {
+				return err
+			}
+			c.MaxAge = maxAge
+		case "secure":
+			c.Secure = true
+		case "httponly":
+			c.HTTPOnly = true
+		case "samesite":
+			c.SameSite = attr.Value
+		default:
+			if c.Attributes == nil {
+				c.Attributes = map[string]string{}
+			}
+			c.Attributes[name] = attr.Value
+		}
+	}
+	return nil
+}
+
+// splitCookie splits the semicolon separated name=value pairs of
+// a cookie header value, removing any quotes around the values
+func splitCookie(s string) []keyValue {
+	var pairs []keyValue
+	for _, part := range strings.Split(s, ";") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		pair := keyValue{Key: part}
+		if i := strings.IndexByte(part, '='); i != -1 {
+			pair.Key = strings.TrimSpace(part[:i])
+			pair.Value = strings.TrimSpace(part[i+1:])
+		}
+		if len(pair.Value) > 1 && strings.HasPrefix(pair.Value, `"`) && strings.HasSuffix(pair.Value, `"`) {
+			pair.Value = pair.Value[1 : len(pair.Value)-1]
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+func parseCookieTime(s string) (time.Time, error) {
+	var err error
+	for _, format := range cookieTimeFormats {
+		var t time.Time
+		if t, err = time.Parse(format, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
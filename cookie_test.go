@@ -0,0 +1,62 @@
+package structexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CookieHeaders struct {
+	StructExp `structexp:"^Cookie: {{cookie}}\\nSet-Cookie: {{set}}$"`
+	Cookies   Cookies   `structexp.name:"cookie" structexp.exp:".*"`
+	SetCookie SetCookie `structexp.name:"set" structexp.exp:".*"`
+}
+
+func TestCookies(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "Cookies",
+			String: "Cookie: a=1; b=\"two\"; c=\nSet-Cookie: id=a3f; path=/; Domain=.example.com; Expires=Wed, 21 Oct 2015 07:28:00 GMT; Max-Age=60; Secure; HttpOnly; SameSite=Lax; Priority=High",
+			Expected: &CookieHeaders{
+				Cookies: Cookies{"a": "1", "b": "two", "c": ""},
+				SetCookie: SetCookie{
+					Name:       "id",
+					Value:      "a3f",
+					Path:       "/",
+					Domain:     "example.com",
+					Expires:    time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC),
+					MaxAge:     60,
+					Secure:     true,
+					HTTPOnly:   true,
+					SameSite:   "Lax",
+					Attributes: map[string]string{"priority": "High"},
+				},
+			},
+			Error: nil,
+		},
+		{
+			Name:     "InvalidCookieError",
+			String:   "Cookie: a=1\nSet-Cookie: =1",
+			Expected: &CookieHeaders{Cookies: Cookies{"a": "1"}},
+			Error:    &FieldError{"SetCookie", "set", "=1", &InvalidCookie{"=1"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var headers CookieHeaders
+			err := Parse(tc.String, &headers)
+			assert.EqualValues(t, tc.Expected, &headers)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// declaration is a struct type declared with Declare, and the options it is compiled with
+type declaration struct {
+	t       reflect.Type
+	compile func() error
+	opts    *options
+}
+
+var declarations struct {
+	mu    sync.Mutex
+	types []declaration
+}
+
+// Declare records the struct type T, and the options it is compiled with, to
+// be checked by ValidateDeclared. Declaring every parsed struct type next to
+// its definition, such as with
+//
+//  var _ = structexp.Declare[AccessLog]()
+//
+// and calling MustValidateDeclared at the start of main, or ValidateDeclared
+// in a test, ensures no bad definition reaches the first record. The checks
+// are deferred until then, since sub-parsers and factories are often
+// registered by init functions, which run after package variables are set.
+// Declare returns true, to be assigned to a package variable.
+func Declare[T any](opts ...Option) bool {
+	declarations.mu.Lock()
+	defer declarations.mu.Unlock()
+	declarations.types = append(declarations.types, declaration{
+		t: reflect.TypeOf((*T)(nil)).Elem(),
+		compile: func() error {
+			_, err := Compile[T](opts...)
+			return err
+		},
+		opts: newOptions(opts),
+	})
+	return true
+}
+
+// ValidateDeclared compiles every struct type declared with Declare with its
+// options, like Compile, and checks its definition with Validate, unless its
+// template is given by an option. All problems are returned at once, joined
+// with errors.Join, by struct type in the order declared.
+func ValidateDeclared() error {
+	declarations.mu.Lock()
+	types := append([]declaration{}, declarations.types...)
+	declarations.mu.Unlock()
+
+	var errs []error
+	for _, d := range types {
+		if err := d.compile(); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", d.t, err))
+			continue
+		}
+		if d.opts.template != "" || d.opts.variant != "" || d.opts.loader != nil {
+			continue
+		}
+		if err := Validate(d.t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MustValidateDeclared is like ValidateDeclared but panics on any problem
+func MustValidateDeclared() {
+	if err := ValidateDeclared(); err != nil {
+		panic(fmt.Sprintf("structexp: ValidateDeclared: %v", err))
+	}
+}
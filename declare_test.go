@@ -0,0 +1,39 @@
+package structexp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDeclared(t *testing.T) {
+	declarations.mu.Lock()
+	saved := declarations.types
+	declarations.types = nil
+	declarations.mu.Unlock()
+	defer func() {
+		declarations.mu.Lock()
+		declarations.types = saved
+		declarations.mu.Unlock()
+	}()
+
+	assert.True(t, Declare[Header]())
+	assert.True(t, Declare[TypoStruct](WithTemplate(`^{{Level}} {{code}} {{msg}}$`)))
+	assert.NoError(t, ValidateDeclared())
+	assert.NotPanics(t, MustValidateDeclared)
+
+	Declare[InvalidOptionalStruct]()
+	Declare[UnknownSubParserStruct]()
+	err := ValidateDeclared()
+	assert.EqualValues(t, errors.Join(
+		&InvalidDefinition{reflect.TypeOf(InvalidOptionalStruct{}), []error{
+			&InvalidTag{"Value", `structexp.name:"test" structexp.optional:"maybe"`, "invalid value of structexp.optional", &strconv.NumError{Func: "ParseBool", Num: "maybe", Err: strconv.ErrSyntax}},
+		}},
+		fmt.Errorf("%v: %w", reflect.TypeOf(UnknownSubParserStruct{}), &UnknownSubParser{"unknown"}),
+	), err)
+	assert.Panics(t, MustValidateDeclared)
+}
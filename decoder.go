@@ -0,0 +1,116 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// Decoder reads records from an input stream and parses them into structs.
+// Records are lines by default; see Decoder.SetSeparator and Decoder.Split.
+// It tracks the Position where each record starts, to report bad records.
+type Decoder struct {
+	scanner   *bufio.Scanner
+	positions *recordPositions
+	sniffJSON bool
+}
+
+// NewDecoder returns a new decoder reading records from r
+func NewDecoder(r io.Reader) *Decoder {
+	d := &Decoder{scanner: bufio.NewScanner(r), positions: newRecordPositions()}
+	d.Split(bufio.ScanLines)
+	return d
+}
+
+// SetSeparator sets the string separating records, instead of newlines.
+// It must be called before the first call to Decode.
+func (d *Decoder) SetSeparator(sep string) {
+	d.Split(separatorSplitFunc([]byte(sep)))
+}
+
+// Split sets the split function tokenizing the input stream into records.
+// It must be called before the first call to Decode.
+func (d *Decoder) Split(split bufio.SplitFunc) {
+	d.scanner.Split(d.positions.split(split))
+}
+
+// SniffJSON parses records that are JSON objects with encoding/json, like the
+// SniffJSON option. It must be called before the first call to Decode.
+func (d *Decoder) SniffJSON() {
+	d.sniffJSON = true
+}
+
+// Buffer sets the initial buffer and maximum record size of the decoder,
+// like bufio.Scanner.Buffer. It must be called before the first call to Decode.
+func (d *Decoder) Buffer(buf []byte, max int) {
+	d.scanner.Buffer(buf, max)
+}
+
+// Decode reads the next record from its input and parses it
+// into the argument, in the same way as Parse.
+//
+// Errors occur if:
+//  - there are no more records, in which case the error is io.EOF
+//  - reading the input fails
+//  - argument is not a pointer to a struct, or its struct is invalid,
+//    as with Parse, such as a NotStruct or MissingField error, in which
+//    case no record is read
+//  - record does not match or its fields fail to parse, or its JSON fails
+//    to unmarshal, as a PositionError of the record
+func (d *Decoder) Decode(i interface{}) error {
+	return d.DecodeContext(context.Background(), i)
+}
+
+// DecodeContext is like Decode, but returns the error of the context
+// instead of reading the next record once the context is done. A read
+// already blocked on the input is not interrupted by the context.
+func (d *Decoder) DecodeContext(ctx context.Context, i interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Errors of the argument and its struct are not the record's,
+	// so they are returned before reading it
+	p, err := structPtrParser(i)
+	if err != nil {
+		return err
+	}
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	if d.sniffJSON && isJSONObject(d.scanner.Text()) {
+		err = json.Unmarshal(d.scanner.Bytes(), i)
+	} else {
+		err = p.parse(reflect.ValueOf(i).Elem(), d.scanner.Text())
+	}
+	if err != nil {
+		return &PositionError{d.positions.record, err}
+	}
+	return nil
+}
+
+// Position returns the position where the last record read starts
+func (d *Decoder) Position() Position {
+	return d.positions.record
+}
+
+// separatorSplitFunc returns a split function splitting on the separator
+func separatorSplitFunc(sep []byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, sep); i >= 0 && len(sep) > 0 {
+			return i + len(sep), data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
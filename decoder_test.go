@@ -0,0 +1,115 @@
+package structexp
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+	type TestCase struct {
+		Name      string
+		Input     string
+		Separator string
+		Expected  []Int
+		Error     error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Lines",
+			Input:    "1\n2\r\n3",
+			Expected: []Int{{Value: 1}, {Value: 2}, {Value: 3}},
+			Error:    io.EOF,
+		},
+		{
+			Name:      "Separator",
+			Input:     "1;;2;;3;;",
+			Separator: ";;",
+			Expected:  []Int{{Value: 1}, {Value: 2}, {Value: 3}},
+			Error:     io.EOF,
+		},
+		{
+			Name:     "NoMatchError",
+			Input:    "1\na\n3",
+			Expected: []Int{{Value: 1}},
+			Error:    &PositionError{Position{2, 1, 2, 2}, noMatch[Int]("a")},
+		},
+		{
+			Name:      "SeparatorError",
+			Input:     "1;;2;;a;;3",
+			Separator: ";;",
+			Expected:  []Int{{Value: 1}, {Value: 2}},
+			Error:     &PositionError{Position{1, 7, 6, 3}, noMatch[Int]("a")},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			decoder := NewDecoder(strings.NewReader(tc.Input))
+			if tc.Separator != "" {
+				decoder.SetSeparator(tc.Separator)
+			}
+
+			var values []Int
+			var err error
+			for {
+				var value Int
+				if err = decoder.Decode(&value); err != nil {
+					break
+				}
+				values = append(values, value)
+			}
+			assert.EqualValues(t, tc.Expected, values)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestDecoderBuffer(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("12345\n"))
+	decoder.Buffer(make([]byte, 2), 4)
+
+	var value Int
+	err := decoder.Decode(&value)
+	require.Error(t, err)
+	assert.NotEqual(t, io.EOF, err)
+}
+
+func TestDecoderDecodeContext(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("1\n2\n"))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var value Int
+	require.NoError(t, decoder.DecodeContext(ctx, &value))
+	assert.EqualValues(t, Int{Value: 1}, value)
+
+	cancel()
+	assert.Equal(t, context.Canceled, decoder.DecodeContext(ctx, &value))
+	assert.EqualValues(t, Int{Value: 1}, value)
+	assert.EqualValues(t, Position{1, 1, 0, 1}, decoder.Position())
+}
+
+func TestDecoderDefinitionErrors(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("1\n2\na\n"))
+
+	// Errors of the argument and its struct are returned without a position,
+	// and without reading a record
+	var missing MissingFieldStruct
+	assert.EqualValues(t, &MissingField{}, decoder.Decode(&missing))
+	assert.EqualValues(t, &NotStruct{reflect.Struct}, decoder.Decode(Int{}))
+
+	var value Int
+	require.NoError(t, decoder.Decode(&value))
+	assert.EqualValues(t, Int{Value: 1}, value)
+	assert.EqualValues(t, Position{1, 1, 0, 1}, decoder.Position())
+	require.NoError(t, decoder.Decode(&value))
+	assert.EqualValues(t, Int{Value: 2}, value)
+	assert.EqualValues(t, &PositionError{Position{3, 1, 4, 3}, noMatch[Int]("a")}, decoder.Decode(&value))
+}
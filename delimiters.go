@@ -0,0 +1,29 @@
+package structexp // nolint:golint // in another file
+
+import "regexp"
+
+// WithDelimiters replaces the {{ and }} delimiters of the placeholders, and
+// include directives, of the Parser's template with the left and right
+// strings, such as << and >>, for templates matching text with literal double
+// braces, like Go templates. Placeholders become <<name>> and <<name:token>>,
+// and include directives <<> name>>. The templates of nested structs with
+// their own StructExp field keep the default delimiters.
+//
+// Compile fails with an InvalidDelimiters error if either string is empty.
+func WithDelimiters(left, right string) Option {
+	return func(o *options) {
+		o.delimiters = &[2]string{left, right}
+	}
+}
+
+// delimitedRegexps returns the regular expressions matching the
+// placeholders and include directives between the delimiters
+func delimitedRegexps(left, right string) (placeholders, includes *regexp.Regexp, err error) {
+	if left == "" || right == "" {
+		return nil, nil, &InvalidDelimiters{left, right}
+	}
+	left, right = regexp.QuoteMeta(left), regexp.QuoteMeta(right)
+	placeholders = regexp.MustCompile(left + `([^:]+?)(?::(.*?))?` + right)
+	includes = regexp.MustCompile(left + `>\s*(.*?)\s*` + right)
+	return placeholders, includes, nil
+}
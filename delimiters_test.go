@@ -0,0 +1,60 @@
+package structexp
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDelimiters(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Template string
+		Input    string
+		Value    Header
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "literal braces",
+			Template: `^{{ .Values.<<level>> }} <<code>>$`,
+			Input:    "{{ .Values.WARN }} 12",
+			Value:    Header{Level: "WARN", Code: 12},
+		},
+		{
+			Name:     "token",
+			Template: `^<<level:bracketed>> <<code>>$`,
+			Input:    "[INFO] 3",
+			Value:    Header{Level: "[INFO]", Code: 3},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := Compile[Header](WithTemplate(tc.Template), WithDelimiters("<<", ">>"))
+			require.NoError(t, err)
+
+			value, err := parser.Parse(tc.Input)
+			require.NoError(t, err)
+			assert.EqualValues(t, tc.Value, value)
+		})
+	}
+}
+
+func TestWithDelimitersInclude(t *testing.T) {
+	fsys := fstest.MapFS{"code.tmpl": {Data: []byte("{{code}} <<code>>")}}
+	parser, err := Compile[Header](WithTemplate(`^<<level>> <<> code.tmpl>>$`), WithLoader(FSLoader(fsys)), WithDelimiters("<<", ">>"))
+	require.NoError(t, err)
+
+	value, err := parser.Parse("INFO {{code}} 3")
+	require.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "INFO", Code: 3}, value)
+}
+
+func TestWithDelimitersInvalid(t *testing.T) {
+	_, err := Compile[Header](WithDelimiters("", ">>"))
+	assert.EqualValues(t, &InvalidDelimiters{"", ">>"}, err)
+}
@@ -0,0 +1,53 @@
+package structexp // nolint:golint // in another file
+
+// fallback marks a ParseAny candidate as the catch-all
+type fallback struct {
+	v interface{}
+}
+
+// Fallback designates a ParseAny candidate as the catch-all struct, which is
+// only tried after every other candidate failed to match, regardless of its
+// position in the candidate list. This lets pipelines always produce a typed
+// value, even for records that no specific struct describes.
+func Fallback(i interface{}) interface{} {
+	return fallback{i}
+}
+
+// ParseAny parses the string into the first candidate whose regular
+// expression matches it, and returns that candidate. Candidates are tried
+// in order, followed by the Fallback candidate if one was given.
+//
+// Errors occur if:
+//  - more than one Fallback candidate is given
+//  - any candidate tried is not the address of a struct or is missing a StructExp field
+//  - no candidate matches the string
+//  - a matching candidate's fields fail to parse
+func ParseAny(s string, candidates ...interface{}) (interface{}, error) {
+	var catchAll interface{}
+	ordered := make([]interface{}, 0, len(candidates))
+	for _, candidate := range candidates {
+		if fb, ok := candidate.(fallback); ok {
+			if catchAll != nil {
+				return nil, &MultipleFallbacks{}
+			}
+			catchAll = fb.v
+			continue
+		}
+		ordered = append(ordered, candidate)
+	}
+	if catchAll != nil {
+		ordered = append(ordered, catchAll)
+	}
+
+	for _, candidate := range ordered {
+		err := Parse(s, candidate)
+		if isNoMatch(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return candidate, nil
+	}
+	return nil, &NoMatch{}
+}
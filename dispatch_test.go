@@ -0,0 +1,86 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type LoginEvent struct {
+	StructExp `structexp:"^{{ts}} login {{user}}$"`
+	Timestamp int    `structexp.name:"ts"`
+	User      string `structexp.name:"user" structexp.exp:"\\w+"`
+}
+
+type RawEvent struct {
+	StructExp `structexp:"^{{ts}} {{msg}}$"`
+	Timestamp int    `structexp.name:"ts"`
+	Message   string `structexp.name:"msg"`
+}
+
+func TestParseAny(t *testing.T) {
+	type TestCase struct {
+		Name       string
+		String     string
+		Candidates func() []interface{}
+		Expected   interface{}
+		Error      error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "FirstMatch",
+			String: "10 login bob",
+			Candidates: func() []interface{} {
+				return []interface{}{&LoginEvent{}, &RawEvent{}}
+			},
+			Expected: &LoginEvent{Timestamp: 10, User: "bob"},
+			Error:    nil,
+		},
+		{
+			Name:   "FallbackTriedLast",
+			String: "10 login bob",
+			Candidates: func() []interface{} {
+				return []interface{}{Fallback(&RawEvent{}), &LoginEvent{}}
+			},
+			Expected: &LoginEvent{Timestamp: 10, User: "bob"},
+			Error:    nil,
+		},
+		{
+			Name:   "FallbackMatch",
+			String: "10 logout bob",
+			Candidates: func() []interface{} {
+				return []interface{}{Fallback(&RawEvent{}), &LoginEvent{}}
+			},
+			Expected: &RawEvent{Timestamp: 10, Message: "logout bob"},
+			Error:    nil,
+		},
+		{
+			Name:   "NoMatchError",
+			String: "logout bob",
+			Candidates: func() []interface{} {
+				return []interface{}{&LoginEvent{}, &RawEvent{}}
+			},
+			Expected: nil,
+			Error:    &NoMatch{},
+		},
+		{
+			Name:   "MultipleFallbacksError",
+			String: "10 logout bob",
+			Candidates: func() []interface{} {
+				return []interface{}{Fallback(&RawEvent{}), Fallback(&RawEvent{})}
+			},
+			Expected: nil,
+			Error:    &MultipleFallbacks{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := ParseAny(tc.String, tc.Candidates()...)
+			assert.EqualValues(t, tc.Expected, result)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package structexp // nolint:golint // in another file
+
+import "io"
+
+// Encoder formats structs into records written to an output stream, the
+// reverse of Decoder. Records are followed by a newline by default; see
+// Encoder.SetSeparator.
+type Encoder struct {
+	w   io.Writer
+	sep string
+}
+
+// NewEncoder returns a new encoder writing records to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, sep: "\n"}
+}
+
+// SetSeparator sets the string written after each record, instead of a newline
+func (e *Encoder) SetSeparator(sep string) {
+	e.sep = sep
+}
+
+// Encode formats the argument, in the same way as Format, and
+// writes it to the output stream followed by the record separator.
+//
+// Errors occur if:
+//  - writing the output fails
+//  - any of the errors from Format occur
+func (e *Encoder) Encode(i interface{}) error {
+	s, err := Format(i)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, s+e.sep)
+	return err
+}
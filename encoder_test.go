@@ -0,0 +1,80 @@
+package structexp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoder(t *testing.T) {
+	type TestCase struct {
+		Name      string
+		Inputs    []interface{}
+		Separator string
+		Expected  string
+		Error     error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Lines",
+			Inputs:   []interface{}{&Int{Value: 1}, Int{Value: 2}},
+			Expected: "1\n2\n",
+			Error:    nil,
+		},
+		{
+			Name:      "Separator",
+			Inputs:    []interface{}{&Int{Value: 1}, &Int{Value: 2}},
+			Separator: ";;",
+			Expected:  "1;;2;;",
+			Error:     nil,
+		},
+		{
+			Name:     "NotStructError",
+			Inputs:   []interface{}{&Int{Value: 1}, 2},
+			Expected: "1\n",
+			Error:    &NotStruct{reflect.Int},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var b strings.Builder
+			encoder := NewEncoder(&b)
+			if tc.Separator != "" {
+				encoder.SetSeparator(tc.Separator)
+			}
+
+			var err error
+			for _, input := range tc.Inputs {
+				if err = encoder.Encode(input); err != nil {
+					break
+				}
+			}
+			assert.Equal(t, tc.Expected, b.String())
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestEncoderDecoderRoundTrip(t *testing.T) {
+	var b strings.Builder
+	encoder := NewEncoder(&b)
+	for _, entry := range []Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}} {
+		assert.NoError(t, encoder.Encode(entry))
+	}
+
+	var entries []Entry
+	decoder := NewDecoder(strings.NewReader(b.String()))
+	for {
+		var entry Entry
+		if err := decoder.Decode(&entry); err != nil {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, []Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, entries)
+}
@@ -1,17 +1,29 @@
 package structexp // nolint:golint // in another file
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 )
 
-// InvalidType occurs when trying to set the value of an unaddreesable type
+// Sentinel errors, matched by errors.Is for every error of their type, whatever its fields
+var (
+	// ErrNoMatch is matched by every NoMatch
+	ErrNoMatch = errors.New("object regular expression has no matches for the input")
+	// ErrInputTooLarge is matched by every InputTooLarge
+	ErrInputTooLarge = errors.New("input size exceeds the limit")
+)
+
+// InvalidType occurs when trying to set the value of an unaddressable type
 type InvalidType struct {
 	reflect.Type
 }
 
 func (err InvalidType) Error() string {
-	return "value of type %T unable to be set (must be addressable)"
+	return fmt.Sprintf("value of type %v unable to be set (must be addressable)", err.Type)
 }
 
 // NotStruct occurs when anything but a pointer to a struct is passed into Parse
@@ -27,16 +39,492 @@ func (err *NotStruct) Error() string {
 	)
 }
 
-// MissingField occurs when the struct to be parsed does not have a StructExp field
+// MissingField occurs when the struct to be parsed does not have a StructExp field,
+// a Pattern method, or a pattern registered with RegisterPattern
 type MissingField struct{}
 
 func (err *MissingField) Error() string {
 	return fmt.Sprintf("object missing field with type %T", StructExp{})
 }
 
-// NoMatch occurs when the string to be parsed does not matc hthe built regular expression
-type NoMatch struct{}
+// NoMatch occurs when the string to be parsed does not match the built regular expression,
+// with the expression and the input. Its message shows roughly where the match fell apart.
+// The fields are empty if several expressions were tried, such as by ParseAny.
+type NoMatch struct {
+	// Pattern is the regular expression
+	Pattern string
+	// Input is the input, or its first bytes if it is long
+	Input string
+	// Size is the size of the whole input
+	Size int
+
+	prefixOnce sync.Once
+	prefix     int
+}
+
+// NewNoMatch returns the NoMatch error of the regular expression not matching
+// the input, keeping only the first bytes of a long input. It is for parsers
+// built on the package, such as the Parse functions of structexp-gen.
+func NewNoMatch(regxp *regexp.Regexp, input string) *NoMatch {
+	return &NoMatch{Pattern: regxp.String(), Input: input[:headSize(input)], Size: len(input)}
+}
 
 func (err *NoMatch) Error() string {
-	return "object regular expression has no matches for the input"
+	if err.Pattern == "" {
+		return ErrNoMatch.Error()
+	}
+	prefix := err.Prefix()
+	if prefix < 0 {
+		return fmt.Sprintf("object regular expression has no matches for the input %q",
+			excerpt(err.Input, err.Size, 0))
+	}
+	return fmt.Sprintf("object regular expression has no matches for the input %q, matching up to byte %d",
+		excerpt(err.Input, err.Size, prefix), prefix)
+}
+
+// Prefix returns the length of the longest prefix of the Input ending a partial
+// match of the regular expression, or -1 if it is unknown. It is found on the
+// first call, so that errors which are only counted or skipped cost no more.
+func (err *NoMatch) Prefix() int {
+	err.prefixOnce.Do(func() {
+		err.prefix = matchedPrefix(err.Pattern, err.Input)
+	})
+	return err.prefix
+}
+
+// Is reports whether the target is ErrNoMatch
+func (err *NoMatch) Is(target error) bool {
+	return target == ErrNoMatch
+}
+
+// isNoMatch reports whether the error is the NoMatch of the record itself,
+// and not one of a nested struct field wrapped in a FieldError, which
+// errors.Is also matches with ErrNoMatch, but which is a record that matched
+func isNoMatch(err error) bool {
+	_, ok := err.(*NoMatch)
+	return ok
+}
+
+// InvalidChar occurs when a byte or rune field's capture is not exactly one character
+type InvalidChar struct {
+	S string
+	K reflect.Kind
+}
+
+func (err *InvalidChar) Error() string {
+	return fmt.Sprintf("%q is not a single %v character", err.S, err.K)
+}
+
+// MultipleFallbacks occurs when more than one ParseAny candidate is designated as the Fallback
+type MultipleFallbacks struct{}
+
+func (err *MultipleFallbacks) Error() string {
+	return "only one fallback candidate may be given"
+}
+
+// UnknownToken occurs when a placeholder uses a token that does not exist
+type UnknownToken struct {
+	Token string
+}
+
+func (err *UnknownToken) Error() string {
+	return fmt.Sprintf("unknown placeholder token %q", err.Token)
+}
+
+// UnbalancedBrackets occurs when a bracketed placeholder's capture is not a single balanced region
+type UnbalancedBrackets struct {
+	S string
+}
+
+func (err *UnbalancedBrackets) Error() string {
+	return fmt.Sprintf("%q is not a single region of balanced brackets", err.S)
+}
+
+// UnknownSubParser occurs when a field's structexp.sub tag names a sub-parser that is not registered
+type UnknownSubParser struct {
+	Name string
+}
+
+func (err *UnknownSubParser) Error() string {
+	return fmt.Sprintf("unknown sub-parser %q", err.Name)
+}
+
+// ValidationError occurs when a field's value, after it is parsed,
+// fails the check of one of its validation tags, such as structexp.max
+type ValidationError struct {
+	Field string
+	Tag   string
+	Limit string
+	Value string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("field %s: %s fails %s:%q", err.Field, err.Value, err.Tag, err.Limit)
+}
+
+// UnknownTransform occurs when a field's structexp.transform tag names a transform that is not registered
+type UnknownTransform struct {
+	Name string
+}
+
+func (err *UnknownTransform) Error() string {
+	return fmt.Sprintf("unknown transform %q", err.Name)
+}
+
+// UnknownVariant occurs when no variant of the struct type is registered by the key given to WithVariant
+type UnknownVariant struct {
+	reflect.Type
+	Key string
+}
+
+func (err *UnknownVariant) Error() string {
+	return fmt.Sprintf("unknown variant %q of %v", err.Key, err.Type)
+}
+
+// UnknownGroup occurs when a variant replaces the expression of a capture group no field has
+type UnknownGroup struct {
+	Group string
+}
+
+func (err *UnknownGroup) Error() string {
+	return fmt.Sprintf("no field has capture group %q", err.Group)
+}
+
+// UnknownExplainFormat occurs when Explain is given a format that is not DOT or Mermaid
+type UnknownExplainFormat struct {
+	Format ExplainFormat
+}
+
+func (err *UnknownExplainFormat) Error() string {
+	return fmt.Sprintf("unknown explain format %d", err.Format)
+}
+
+// SubParsersFailed occurs when every sub-parser of a fallback chain fails, with each one's error
+type SubParsersFailed struct {
+	Names  []string
+	Errors []error
+}
+
+func (err *SubParsersFailed) Error() string {
+	msgs := make([]string, len(err.Errors))
+	for i, e := range err.Errors {
+		msgs[i] = fmt.Sprintf("%s: %v", err.Names[i], e)
+	}
+	return "all sub-parsers failed: " + strings.Join(msgs, "; ")
+}
+
+func (err *SubParsersFailed) Unwrap() []error {
+	return err.Errors
+}
+
+// UnknownField occurs when a field path given to Projection names no field
+type UnknownField struct {
+	Field string
+}
+
+func (err *UnknownField) Error() string {
+	return fmt.Sprintf("unknown field %s", err.Field)
+}
+
+// FieldTypeMismatch occurs when a field is accessed as a type other than its own
+type FieldTypeMismatch struct {
+	Field string
+	Type  reflect.Type
+	Want  reflect.Type
+}
+
+func (err *FieldTypeMismatch) Error() string {
+	return fmt.Sprintf("field %s is %v, not %v", err.Field, err.Type, err.Want)
+}
+
+// InvalidDelimiters occurs when a placeholder delimiter given to WithDelimiters is empty
+type InvalidDelimiters struct {
+	Left, Right string
+}
+
+func (err *InvalidDelimiters) Error() string {
+	return fmt.Sprintf("invalid placeholder delimiters %q and %q", err.Left, err.Right)
+}
+
+// UnknownDiscriminator occurs when no factory is registered for an interface field's discriminator value
+type UnknownDiscriminator struct {
+	reflect.Type
+	Value string
+}
+
+func (err *UnknownDiscriminator) Error() string {
+	return fmt.Sprintf("no factory registered for %v with discriminator %q", err.Type, err.Value)
+}
+
+// InvalidCookie occurs when a Set-Cookie header value does not start with a cookie name and value
+type InvalidCookie struct {
+	S string
+}
+
+func (err *InvalidCookie) Error() string {
+	return fmt.Sprintf("%q is not a valid Set-Cookie header value", err.S)
+}
+
+// NotSlice occurs when anything but a pointer to a slice is passed into ParseAll
+type NotSlice struct {
+	K reflect.Kind
+}
+
+func (err *NotSlice) Error() string {
+	return fmt.Sprintf(
+		"object to parse is not %v, is %v",
+		reflect.Slice,
+		err.K,
+	)
+}
+
+// LineError occurs when a line fails to parse, with its 1-based
+// line number and the 0-based byte offset where it starts
+type LineError struct {
+	Line   int
+	Offset int
+	Err    error
+}
+
+func (err *LineError) Error() string {
+	return fmt.Sprintf("line %d, offset %d: %v", err.Line, err.Offset, err.Err)
+}
+
+func (err *LineError) Unwrap() error {
+	return err.Err
+}
+
+// PositionError occurs when a record read by a Scanner or Decoder fails to parse, with the Position where it starts
+type PositionError struct {
+	Position
+	Err error
+}
+
+func (err *PositionError) Error() string {
+	return fmt.Sprintf("record %d, line %d, column %d, offset %d: %v", err.Record, err.Line, err.Column, err.Offset, err.Err)
+}
+
+func (err *PositionError) Unwrap() error {
+	return err.Err
+}
+
+// FieldError occurs when a field fails to be set from the value of its capture
+// group, with the error converting it. Fields of nested structs are named
+// by their path, such as Header.Code.
+type FieldError struct {
+	Field string
+	Group string
+	Value string
+	Err   error
+}
+
+func (err *FieldError) Error() string {
+	return fmt.Sprintf("field %s: capture group %s value %q: %v", err.Field, err.Group, err.Value, err.Err)
+}
+
+func (err *FieldError) Unwrap() error {
+	return err.Err
+}
+
+// OutOfRange occurs when a number is out of the range of its field's type,
+// with the error converting it. See WithOverflowPolicy to set it anyway.
+type OutOfRange struct {
+	reflect.Type
+	Value string
+	Err   error
+}
+
+func (err *OutOfRange) Error() string {
+	return fmt.Sprintf("%s is out of range of %v", err.Value, err.Type)
+}
+
+func (err *OutOfRange) Unwrap() error {
+	return err.Err
+}
+
+// EmptyCapture occurs with the Strict option when a field's capture group is empty or did not match
+type EmptyCapture struct {
+	Field string
+	Group string
+}
+
+func (err *EmptyCapture) Error() string {
+	return fmt.Sprintf("field %s: capture group %q is empty", err.Field, err.Group)
+}
+
+// TooComplex occurs when a regular expression exceeds a complexity limit given to Compile
+type TooComplex struct {
+	Limit string
+	Value int
+	Max   int
+}
+
+func (err *TooComplex) Error() string {
+	return fmt.Sprintf("regular expression %s %d exceeds the limit of %d", err.Limit, err.Value, err.Max)
+}
+
+// BannedConstruct occurs when a regular expression uses a construct banned by an option given to Compile
+type BannedConstruct struct {
+	Construct string
+}
+
+func (err *BannedConstruct) Error() string {
+	return fmt.Sprintf("regular expression construct %s is not allowed", err.Construct)
+}
+
+// Unanchored occurs when a regular expression must be anchored at both ends, but is not
+type Unanchored struct {
+	Exp string
+}
+
+func (err *Unanchored) Error() string {
+	return fmt.Sprintf("regular expression %s must begin with ^ and end with $", err.Exp)
+}
+
+// InputTooLarge occurs when an input exceeds the size limit given to Compile
+type InputTooLarge struct {
+	Size int
+	Max  int
+}
+
+func (err *InputTooLarge) Error() string {
+	return fmt.Sprintf("input size %d exceeds the limit of %d", err.Size, err.Max)
+}
+
+// Is reports whether the target is ErrInputTooLarge
+func (err *InputTooLarge) Is(target error) bool {
+	return target == ErrInputTooLarge
+}
+
+// QuotaExceeded occurs when adding a Parser to a tenant would exceed its quota
+type QuotaExceeded struct {
+	Tenant     string
+	MaxParsers int
+}
+
+func (err *QuotaExceeded) Error() string {
+	return fmt.Sprintf("tenant %s exceeds its quota of %d parsers", err.Tenant, err.MaxParsers)
+}
+
+// UnknownPlaceholder occurs when placeholders in a template have no corresponding field
+type UnknownPlaceholder struct {
+	Names []string
+}
+
+func (err *UnknownPlaceholder) Error() string {
+	return fmt.Sprintf("placeholders %s have no field", strings.Join(err.Names, ", "))
+}
+
+// DuplicateGroup occurs when two fields have the same capture group name,
+// such as from their structexp.name tags, so only one could be set from it
+type DuplicateGroup struct {
+	Group  string
+	Fields [2]string
+}
+
+func (err *DuplicateGroup) Error() string {
+	return fmt.Sprintf("fields %s and %s have the same capture group %s", err.Fields[0], err.Fields[1], err.Group)
+}
+
+// UnknownIndex occurs when a field's structexp.index tag is not the position of an unnamed capture group
+type UnknownIndex struct {
+	Field string
+	Index int
+}
+
+func (err *UnknownIndex) Error() string {
+	return fmt.Sprintf("field %s: no unnamed capture group %d", err.Field, err.Index)
+}
+
+// UnknownRawGroup occurs when a field's structexp.raw tag names a capture group no other field has
+type UnknownRawGroup struct {
+	Field string
+	Group string
+}
+
+func (err *UnknownRawGroup) Error() string {
+	return fmt.Sprintf("field %s: no capture group %s to keep the raw text of", err.Field, err.Group)
+}
+
+// UnplacedField occurs when a field's capture group has no placeholder in the template
+type UnplacedField struct {
+	Field string
+	Group string
+}
+
+func (err *UnplacedField) Error() string {
+	return fmt.Sprintf("field %s has no placeholder {{%s}}", err.Field, err.Group)
+}
+
+// InvalidTag occurs when a field's tag is malformed, or has an unknown structexp key,
+// with the error parsing its value, if any, such as a structexp.default value
+type InvalidTag struct {
+	Field  string
+	Tag    string
+	Reason string
+	Err    error
+}
+
+func (err *InvalidTag) Error() string {
+	if err.Err != nil {
+		return fmt.Sprintf("field %s tag `%s`: %s: %v", err.Field, err.Tag, err.Reason, err.Err)
+	}
+	return fmt.Sprintf("field %s tag `%s`: %s", err.Field, err.Tag, err.Reason)
+}
+
+func (err *InvalidTag) Unwrap() error {
+	return err.Err
+}
+
+// InvalidDefinition lists every problem Validate found in a struct definition
+type InvalidDefinition struct {
+	reflect.Type
+	Problems []error
+}
+
+func (err *InvalidDefinition) Error() string {
+	problems := make([]string, 0, len(err.Problems))
+	for _, problem := range err.Problems {
+		problems = append(problems, problem.Error())
+	}
+	return fmt.Sprintf("invalid definition of %v: %s", err.Type, strings.Join(problems, "; "))
+}
+
+// Unwrap returns the problems, for errors.Is and errors.As
+func (err *InvalidDefinition) Unwrap() []error {
+	return err.Problems
+}
+
+// MismatchedSubmatches occurs when the number of group names and submatches given to AssignSubmatches differ
+type MismatchedSubmatches struct {
+	Names   int
+	Matches int
+}
+
+func (err *MismatchedSubmatches) Error() string {
+	return fmt.Sprintf("%d group names do not match %d submatches", err.Names, err.Matches)
+}
+
+// IncludeError occurs when the template of an include directive fails to load
+type IncludeError struct {
+	Name string
+	Err  error
+}
+
+func (err *IncludeError) Error() string {
+	return fmt.Sprintf("include %s: %v", err.Name, err.Err)
+}
+
+// Unwrap returns the error loading the template
+func (err *IncludeError) Unwrap() error {
+	return err.Err
+}
+
+// IncludeCycle occurs when templates include each other, or are nested too deeply
+type IncludeCycle struct {
+	Names []string
+}
+
+func (err *IncludeCycle) Error() string {
+	return fmt.Sprintf("include cycle %s", strings.Join(err.Names, " > "))
 }
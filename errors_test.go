@@ -0,0 +1,93 @@
+package structexp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	// Matched whatever the fields, and through wrapping errors
+	var values []Int
+	err := ParseLines("1\na\n3", &values)
+	assert.ErrorIs(t, err, ErrNoMatch)
+	assert.ErrorIs(t, &NoMatch{}, ErrNoMatch)
+	assert.NotErrorIs(t, err, ErrInputTooLarge)
+
+	_, err = MustCompile[Int](MaxInputSize(2)).Parse("100")
+	assert.ErrorIs(t, err, ErrInputTooLarge)
+	assert.NotErrorIs(t, err, ErrNoMatch)
+
+	assert.NotErrorIs(t, &MissingField{}, ErrNoMatch)
+	assert.EqualError(t, &NoMatch{}, ErrNoMatch.Error())
+}
+
+func TestTypedErrors(t *testing.T) {
+	var values []ParentHeaderStruct
+	err := ParseLines("[WARN: 12] 34\n[WARN: 99999999999999999999] 34", &values)
+
+	var lineErr *LineError
+	require.ErrorAs(t, err, &lineErr)
+	assert.Equal(t, 2, lineErr.Line)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "Header.Code", fieldErr.Field)
+	var rangeErr *OutOfRange
+	require.ErrorAs(t, err, &rangeErr)
+	assert.Equal(t, reflect.TypeOf(0), rangeErr.Type)
+	assert.ErrorIs(t, err, strconv.ErrRange)
+
+	var typeErr *InvalidType
+	err = error(&InvalidType{reflect.TypeOf("")})
+	require.True(t, errors.As(err, &typeErr))
+	assert.EqualError(t, err, "value of type string unable to be set (must be addressable)")
+
+	// Values are errors too
+	var valueErr InvalidType
+	err = fmt.Errorf("wrapped: %w", InvalidType{reflect.TypeOf(0)})
+	require.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, reflect.TypeOf(0), valueErr.Type)
+	assert.EqualError(t, err, "wrapped: value of type int unable to be set (must be addressable)")
+}
+
+type LooseHeaderStruct struct {
+	StructExp `structexp:"^\\[{{Header}}\\] {{code}}$"`
+	Header    Header `structexp.exp:"[^\\]]*"`
+	Code      int    `structexp.name:"code"`
+}
+
+func TestNestedNoMatch(t *testing.T) {
+	// The record matches, but its nested struct field does not
+	nestedErr := &FieldError{"Header", "Header", "oops", noMatch[Header]("oops")}
+	assert.ErrorIs(t, nestedErr, ErrNoMatch)
+	assert.False(t, isNoMatch(nestedErr))
+
+	var values []LooseHeaderStruct
+	err := ParseLines("[WARN: 1] 2\n[oops] 3", &values, SkipNoMatch())
+	assert.EqualValues(t, &LineError{Line: 2, Offset: 12, Err: nestedErr}, err)
+
+	parser := MustCompile[LooseHeaderStruct](SkipNoMatch())
+	records, rest, err := parser.ParseConcatenated("[WARN: 1] 2[oops] 3")
+	assert.EqualValues(t, []LooseHeaderStruct{{Header: Header{Level: "WARN", Code: 1}, Code: 2}}, records)
+	assert.Equal(t, "[oops] 3", rest)
+	assert.EqualValues(t, nestedErr, err)
+
+	_, err = ParseAny("[oops] 3", &LooseHeaderStruct{}, Fallback(&Int{}))
+	assert.EqualValues(t, nestedErr, err)
+
+	scanner := parser.Scanner(strings.NewReader("[oops] 3\n[WARN: 1] 2\n"))
+	require.True(t, scanner.Scan())
+	_, err = scanner.Record()
+	assert.EqualValues(t, &PositionError{Position{1, 1, 0, 1}, nestedErr}, err)
+
+	streamed, errs := receiveStream(ParseStream[LooseHeaderStruct](context.Background(), strings.NewReader("[oops] 3\n"), SkipNoMatch()))
+	assert.Empty(t, streamed)
+	assert.EqualValues(t, []error{&LineError{Line: 1, Offset: 0, Err: nestedErr}}, errs)
+}
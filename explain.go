@@ -0,0 +1,146 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+// ExplainFormat is the graph language written by Explain
+type ExplainFormat int
+
+// Graph languages of Explain
+const (
+	// DOT is the language of Graphviz
+	DOT ExplainFormat = iota
+	// Mermaid is the language of mermaid flowcharts, rendered by many code hosts
+	Mermaid
+)
+
+// Explain writes a graph of the Parser's regular expression in the format,
+// for reviewing complex templates visually. The graph runs from a start node
+// to an end node through the segments of the expression in order, branching
+// at alternations, with each capture group labeled by the field it sets and its expression.
+func (p *Parser[T]) Explain(w io.Writer, format ExplainFormat) error {
+	if format != DOT && format != Mermaid {
+		return &UnknownExplainFormat{format}
+	}
+	re, err := syntax.Parse(p.regexp.String(), syntax.Perl)
+	if err != nil {
+		return err
+	}
+
+	g := &explainGraph{groups: map[string][]FieldGroup{}}
+	for _, group := range fieldGroups(reflect.TypeOf((*T)(nil)).Elem(), p.fields) {
+		g.groups[group.Group] = append(g.groups[group.Group], group)
+	}
+	start := g.node("start", "circle")
+	exits := g.segment(re, []int{start})
+	end := g.node("end", "circle")
+	g.connect(exits, end)
+
+	_, err = io.WriteString(w, g.format(format))
+	return err
+}
+
+type explainNode struct {
+	label string
+	shape string
+}
+
+// explainGraph is a graph of the segments of a regular expression
+type explainGraph struct {
+	groups map[string][]FieldGroup
+	nodes  []explainNode
+	edges  [][2]int
+}
+
+// node adds a node with the label and shape, returning its id
+func (g *explainGraph) node(label, shape string) int {
+	g.nodes = append(g.nodes, explainNode{label, shape})
+	return len(g.nodes) - 1
+}
+
+// connect adds an edge from every node to the node
+func (g *explainGraph) connect(from []int, to int) {
+	for _, f := range from {
+		g.edges = append(g.edges, [2]int{f, to})
+	}
+}
+
+// segment adds the nodes of the expression after the nodes,
+// returning the nodes that the segments after it follow
+func (g *explainGraph) segment(re *syntax.Regexp, prev []int) []int {
+	// nolint:exhaustive // unnecessary
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return prev
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			prev = g.segment(sub, prev)
+		}
+		return prev
+	case syntax.OpAlternate:
+		alt := g.node("|", "diamond")
+		g.connect(prev, alt)
+		var exits []int
+		for _, sub := range re.Sub {
+			exits = append(exits, g.segment(sub, []int{alt})...)
+		}
+		return exits
+	case syntax.OpCapture:
+		label := re.String()
+		if groups := g.groups[re.Name]; len(groups) > 0 {
+			fields := make([]string, 0, len(groups))
+			for _, group := range groups {
+				fields = append(fields, group.Field)
+			}
+			label = fmt.Sprintf("%s ← %s: %s", strings.Join(fields, ", "), re.Name, groups[0].Exp)
+		}
+		n := g.node(label, "box")
+		g.connect(prev, n)
+		return []int{n}
+	default:
+		n := g.node(re.String(), "plain")
+		g.connect(prev, n)
+		return []int{n}
+	}
+}
+
+// format writes the graph in the format
+func (g *explainGraph) format(format ExplainFormat) string {
+	var b strings.Builder
+	if format == DOT {
+		b.WriteString("digraph structexp {\n\trankdir=LR;\n")
+		for i, n := range g.nodes {
+			fmt.Fprintf(&b, "\tn%d [label=%s, shape=%s];\n", i, strconv.Quote(n.label), n.shape)
+		}
+		for _, e := range g.edges {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", e[0], e[1])
+		}
+		b.WriteString("}\n")
+		return b.String()
+	}
+
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.nodes {
+		label := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(n.label)
+		switch n.shape {
+		case "circle":
+			fmt.Fprintf(&b, "\tn%d((\"%s\"))\n", i, label)
+		case "diamond":
+			fmt.Fprintf(&b, "\tn%d{\"%s\"}\n", i, label)
+		case "box":
+			fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", i, label)
+		default:
+			fmt.Fprintf(&b, "\tn%d>\"%s\"]\n", i, label)
+		}
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "\tn%d --> n%d\n", e[0], e[1])
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package structexp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Format   ExplainFormat
+		Expected string
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "DOT",
+			Format: DOT,
+			Expected: `digraph structexp {
+	rankdir=LR;
+	n0 [label="start", shape=circle];
+	n1 [label="|", shape=diamond];
+	n2 [label="a", shape=plain];
+	n3 [label="Key ← key: \\w+", shape=box];
+	n4 [label="=", shape=plain];
+	n5 [label="end", shape=circle];
+	n0 -> n1;
+	n1 -> n2;
+	n1 -> n3;
+	n3 -> n4;
+	n2 -> n5;
+	n4 -> n5;
+}
+`,
+		},
+		{
+			Name:   "Mermaid",
+			Format: Mermaid,
+			Expected: `flowchart LR
+	n0(("start"))
+	n1{"|"}
+	n2>"a"]
+	n3["Key ← key: \w+"]
+	n4>"="]
+	n5(("end"))
+	n0 --> n1
+	n1 --> n2
+	n1 --> n3
+	n3 --> n4
+	n2 --> n5
+	n4 --> n5
+`,
+		},
+		{
+			Name:   "UnknownFormat",
+			Format: ExplainFormat(9),
+			Error:  &UnknownExplainFormat{ExplainFormat(9)},
+		},
+	}
+
+	parser := MustCompile[Entry](WithTemplate(`a|{{key}}=`))
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var b strings.Builder
+			err := parser.Explain(&b, tc.Format)
+			assert.EqualValues(t, tc.Error, err)
+			assert.EqualValues(t, tc.Expected, b.String())
+		})
+	}
+}
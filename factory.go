@@ -0,0 +1,73 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+const discriminatorKey = "structexp.discriminator"
+
+// Factory creates a new value of a concrete type to populate an interface field
+type Factory func() interface{}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[reflect.Type]map[string]Factory{}
+)
+
+// RegisterFactory registers the factory creating the concrete type for interface
+// fields of the interface type, when their discriminator capture has the value.
+// The interface type is given as a nil pointer to it, such as (*Payload)(nil).
+// Interface fields name their discriminator capture group with the
+// structexp.discriminator tag, and their capture is parsed into the created
+// value the same way as any other field, so the concrete type is typically a
+// pointer to a struct with its own StructExp field, or a ParsableField.
+//
+// Panics if iface is not a pointer to an interface type, or if the factory
+// creates a value that does not implement the interface. Registering an
+// existing discriminator value replaces it. Factories are shared by every
+// Parser, whatever its Registry.
+func RegisterFactory(iface interface{}, value string, factory Factory) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("structexp: RegisterFactory requires a pointer to an interface type, not %T", iface))
+	}
+	t = t.Elem()
+	if created := reflect.TypeOf(factory()); created == nil || !created.Implements(t) {
+		panic(fmt.Sprintf("structexp: factory for %q creates %v, which does not implement %v", value, created, t))
+	}
+
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if factories[t] == nil {
+		factories[t] = map[string]Factory{}
+	}
+	factories[t][value] = factory
+}
+
+func lookupFactory(t reflect.Type, value string) (Factory, error) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	factory, ok := factories[t][value]
+	if !ok {
+		return nil, &UnknownDiscriminator{t, value}
+	}
+	return factory, nil
+}
+
+// setInterface sets the interface value to a new value created by the
+// factory registered for the discriminator, parsed from the string
+func setInterface(v reflect.Value, discriminator, s string) error {
+	factory, err := lookupFactory(v.Type(), discriminator)
+	if err != nil {
+		return err
+	}
+
+	created := reflect.ValueOf(factory())
+	if err := setField(created, s); err != nil {
+		return err
+	}
+	v.Set(created)
+	return nil
+}
@@ -0,0 +1,86 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Payload interface {
+	Kind() string
+}
+
+type LoginPayload struct {
+	StructExp `structexp:"^user={{user}}$"`
+	User      string `structexp.name:"user"`
+}
+
+func (*LoginPayload) Kind() string { return "login" }
+
+type ErrorPayload struct {
+	StructExp `structexp:"^code={{code}}$"`
+	Code      int `structexp.name:"code"`
+}
+
+func (*ErrorPayload) Kind() string { return "error" }
+
+type Event struct {
+	StructExp `structexp:"^{{type}} {{payload}}$"`
+	Type      string  `structexp.name:"type" structexp.exp:"\\w+"`
+	Payload   Payload `structexp.name:"payload" structexp.discriminator:"type"`
+}
+
+func init() {
+	RegisterFactory((*Payload)(nil), "login", func() interface{} { return &LoginPayload{} })
+	RegisterFactory((*Payload)(nil), "error", func() interface{} { return &ErrorPayload{} })
+}
+
+func TestFactory(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Login",
+			String:   "login user=bob",
+			Expected: &Event{Type: "login", Payload: &LoginPayload{User: "bob"}},
+			Error:    nil,
+		},
+		{
+			Name:     "Error",
+			String:   "error code=500",
+			Expected: &Event{Type: "error", Payload: &ErrorPayload{Code: 500}},
+			Error:    nil,
+		},
+		{
+			Name:     "UnknownDiscriminatorError",
+			String:   "logout user=bob",
+			Expected: &Event{Type: "logout"},
+			Error:    &FieldError{"Payload", "payload", "user=bob", &UnknownDiscriminator{reflect.TypeOf((*Payload)(nil)).Elem(), "logout"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var event Event
+			err := Parse(tc.String, &event)
+			assert.EqualValues(t, tc.Expected, &event)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestRegisterFactoryPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterFactory(Payload(nil), "login", func() interface{} { return &LoginPayload{} })
+	})
+	assert.Panics(t, func() {
+		RegisterFactory((*Payload)(nil), "login", func() interface{} { return LoginPayload{} })
+	})
+}
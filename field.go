@@ -1,13 +1,31 @@
 package structexp // nolint:golint // in another file
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 const (
 	captureGroupNameKey = "structexp.name"
 	expKey              = "structexp.exp"
+	optionalKey         = "structexp.optional"
+	defaultKey          = "structexp.default"
+	ignoreCaseKey       = "structexp.ignorecase"
+	trimKey             = "structexp.trim"
+	onlyKey             = "structexp.only"
+	indexKey            = "structexp.index"
+	splitKey            = "structexp.split"
+	rawKey              = "structexp.raw"
+)
+
+// Values of the structexp.only tag
+const (
+	parseOnly  = "parse"
+	formatOnly = "format"
 )
 
 // Default regular expression used when parsing struct fields
@@ -15,6 +33,8 @@ const (
 	DefaultBoolRegexp   = `1|t|T|TRUE|true|True|0|f|F|FALSE|false|False`
 	DefaultIntRegexp    = `[[:digit:]]+`
 	DefaultStringRegexp = `[[:print:]]+`
+	DefaultByteRegexp   = `[[:ascii:]]`
+	DefaultRuneRegexp   = `.`
 )
 
 func kindExp(k reflect.Kind) string {
@@ -26,35 +46,323 @@ func kindExp(k reflect.Kind) string {
 		return DefaultIntRegexp
 	case reflect.String:
 		return DefaultStringRegexp
+	case reflect.Uint8:
+		return DefaultByteRegexp
+	case reflect.Int32:
+		return DefaultRuneRegexp
 	default:
 		return ""
 	}
 }
 
 type field struct {
-	Value            reflect.Value
+	Index            []int
 	CaptureGroupName string
 	Exp              string
+	Transform        func(string) (string, error)
+	// Finds the end of the capture by scanning the input, as of a token
+	Scan func(s string, start int) int
+	// Named transforms applied in order after the token's transform
+	Transforms []Transform
+	// Trims the capture before anything else
+	Trim          func(string) string
+	Escape        func(string) string
+	SubParser     SubParser
+	SubParserName string
+	Discriminator string
+	Nested        *nested
+	// The capture group may be absent, leaving the field unset
+	Optional bool
+	// The value to parse when the capture is absent or empty
+	Default string
+	// The expression matches case-insensitively
+	IgnoreCase bool
+	// How numbers out of range are set
+	Overflow OverflowPolicy
+	// An empty capture is an error, unless optional or defaulted
+	Strict bool
+	// An error setting the field does not stop the fields after it being set
+	JoinErrors bool
+	// The field is not rendered by Format
+	ParseOnly bool
+	// The field is not set by parsing
+	FormatOnly bool
+	// The separator of the elements of a slice field's capture
+	Split string
+	// The position of the unnamed capture group the field is bound to, if not 0
+	GroupIndex int
+	// The capture group of another field, whose unparsed submatch the field is set to
+	Raw string
+	// The kind whose default expression is the field's expression, or
+	// that of the elements of a split slice, if it has no structexp.exp tag
+	DefaultKind reflect.Kind
+	// Names of the capture groups in the expression of a SubmatchParsableField
+	Submatches []string
+	// The validation tags checked after the field is set
+	Checks []check
+	// Sets the field, or the elements of a split slice, from a capture
+	Converter converter
+	// The index of the capture group, planned for the regular expression
+	// whose capture group names are Planned, or nil if it is not planned
+	Group   int
+	Planned []string
+}
+
+// nested is the compiled regular expression of a field's struct type,
+// or slice element struct type, and the fields set from it
+type nested struct {
+	Regexp *regexp.Regexp
+	Fields []*field
+	Slice  bool
 }
 
-func newField(value reflect.Value, reflectField *reflect.StructField) *field {
+func newField(index []int, reflectField *reflect.StructField) *field {
 	f := &field{
-		Value:            value,
+		Index:            index,
 		CaptureGroupName: reflectField.Name,
 		Exp:              kindExp(reflectField.Type.Kind()),
 	}
+	if f.Exp != "" {
+		f.DefaultKind = reflectField.Type.Kind()
+	}
 
 	if captureGroupName := reflectField.Tag.Get(captureGroupNameKey); captureGroupName != "" {
 		f.CaptureGroupName = captureGroupName
 	}
 
+	if split := reflectField.Tag.Get(splitKey); split != "" && reflectField.Type.Kind() == reflect.Slice {
+		f.Split = split
+		f.DefaultKind = reflectField.Type.Elem().Kind()
+		if kindExp(f.DefaultKind) == "" {
+			f.DefaultKind = reflect.String
+		}
+		f.Exp = splitExp(kindExp(f.DefaultKind), split)
+	}
+
+	if discriminator, ok := reflectField.Tag.Lookup(discriminatorKey); ok {
+		f.Discriminator = discriminator
+		f.Exp = DefaultStringRegexp
+		f.DefaultKind = reflect.String
+	}
+
 	if exp := reflectField.Tag.Get(expKey); exp != "" {
 		f.Exp = exp
+		f.DefaultKind = reflect.Invalid
+	}
+
+	// An invalid value is reported by Validate
+	f.Optional, _ = strconv.ParseBool(reflectField.Tag.Get(optionalKey))
+	f.Default = reflectField.Tag.Get(defaultKey)
+	f.IgnoreCase, _ = strconv.ParseBool(reflectField.Tag.Get(ignoreCaseKey))
+	f.GroupIndex, _ = strconv.Atoi(reflectField.Tag.Get(indexKey))
+	f.Raw = reflectField.Tag.Get(rawKey)
+	f.Checks, _ = newChecks(reflectField.Type, reflectField.Tag)
+	f.ParseOnly = reflectField.Tag.Get(onlyKey) == parseOnly
+	f.FormatOnly = reflectField.Tag.Get(onlyKey) == formatOnly
+	// Any value but true or false is a cutset, which may be 0 or 1
+	switch trim := reflectField.Tag.Get(trimKey); trim {
+	case "", "false":
+	case "true":
+		f.Trim = strings.TrimSpace
+	default:
+		f.Trim = func(s string) string { return strings.Trim(s, trim) }
+	}
+
+	if f.Split != "" {
+		f.Converter = newConverter(reflectField.Type.Elem())
+	} else {
+		f.Converter = newConverter(reflectField.Type)
+	}
+
+	if reflect.PtrTo(reflectField.Type).Implements(reflect.TypeOf((*SubmatchParsableField)(nil)).Elem()) {
+		// An invalid expression fails when the whole regular expression is compiled
+		if regxp, err := regexp.Compile(f.Exp); err == nil {
+			f.Submatches = regxp.SubexpNames()[1:]
+		}
 	}
 
 	return f
 }
 
+// splitExp returns the expression of a slice capture of
+// elements matching the element expression between the separator
+func splitExp(elemExp, split string) string {
+	return fmt.Sprintf("(?:%s)(?:%s(?:%s))*", elemExp, regexp.QuoteMeta(split), elemExp)
+}
+
+// newStructExpField creates the field for a struct, or slice of structs,
+// with its own StructExp field, defaulting to the struct's expression.
+// The struct's regular expression is compiled once, to parse the field's capture.
+func newStructExpField(index []int, reflectField *reflect.StructField, reg *Registry) (*field, error) {
+	isSlice := reflectField.Type.Kind() == reflect.Slice
+	structType := derefType(reflectField.Type)
+	if isSlice {
+		structType = derefType(reflectField.Type.Elem())
+	}
+
+	base, err := regexpBase(structType)
+	if err != nil {
+		return nil, err
+	}
+	regxp, fields, err := compileTemplate(structType, base, reg)
+	if err != nil {
+		return nil, err
+	}
+
+	f := newField(index, reflectField)
+	if err := f.lookupTransforms(reflectField.Tag, reg); err != nil {
+		return nil, err
+	}
+	f.Nested = &nested{Regexp: regxp, Fields: fields, Slice: isSlice}
+	if _, ok := reflectField.Tag.Lookup(expKey); !ok {
+		if isSlice {
+			f.Exp, err = repeatedExp(regxp)
+		} else {
+			f.Exp, err = splicedExp(regxp)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if isSlice {
+		// Anchors would prevent finding more than one match
+		if f.Nested.Regexp, err = unanchored(regxp); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// newSubParserField creates the field parsed by the sub-parser
+// named by its tag in the registry, defaulting to the string expression.
+// A comma separated list of names is a fallback chain of sub-parsers.
+func newSubParserField(index []int, reflectField *reflect.StructField, reg *Registry) (*field, error) {
+	names := strings.Split(reflectField.Tag.Get(subParserKey), ",")
+	subParsers := make([]SubParser, len(names))
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		subParser, err := reg.lookupSubParser(names[i])
+		if err != nil {
+			return nil, err
+		}
+		subParsers[i] = subParser
+	}
+
+	f := newField(index, reflectField)
+	if err := f.lookupTransforms(reflectField.Tag, reg); err != nil {
+		return nil, err
+	}
+	f.SubParser = subParsers[0]
+	if len(subParsers) > 1 {
+		f.SubParser = fallbackSubParser(names, subParsers)
+	}
+	// Formatting renders the value in the format of the first sub-parser
+	f.SubParserName = names[0]
+	if _, ok := reflectField.Tag.Lookup(expKey); !ok {
+		f.Exp = DefaultStringRegexp
+		f.DefaultKind = reflect.String
+	}
+	return f, nil
+}
+
+// SetSubmatches sets the SubmatchParsableField from its capture, and the
+// matches of the groups in its expression, which follow its capture group
+func (f field) SetSubmatches(v reflect.Value, s string, matches []string) error {
+	submatches := make(map[string]string, len(f.Submatches))
+	for i, name := range f.Submatches {
+		if name != "" {
+			submatches[name] = matches[i]
+		}
+	}
+	val := underlyingValue(fieldByIndex(v, f.Index))
+	return val.Addr().Interface().(SubmatchParsableField).ParseSubmatches(s, submatches)
+}
+
+// SetDefault sets the field within the struct value from its default,
+// which is parsed like a capture, without the field's transforms
+func (f field) SetDefault(v reflect.Value, group func(name string) string) error {
+	f.Transform, f.Transforms = nil, nil
+	return f.Set(v, f.Default, group)
+}
+
 func (f field) NamedCaptureGroup() string {
-	return fmt.Sprintf("(?P<%s>%s)", f.CaptureGroupName, f.Exp)
+	exp := f.Exp
+	if f.IgnoreCase {
+		exp = "(?i:" + exp + ")"
+	}
+	if f.Optional {
+		return fmt.Sprintf("(?:(?P<%s>%s))?", f.CaptureGroupName, exp)
+	}
+	return fmt.Sprintf("(?P<%s>%s)", f.CaptureGroupName, exp)
+}
+
+// Set the field within the struct value from its capture,
+// looking up other capture groups by name if needed
+func (f field) Set(v reflect.Value, s string, group func(name string) string) error {
+	if f.Transform != nil {
+		var err error
+		if s, err = f.Transform(s); err != nil {
+			return err
+		}
+	}
+	for _, transform := range f.Transforms {
+		var err error
+		if s, err = transform(s); err != nil {
+			return err
+		}
+	}
+
+	val := fieldByIndex(v, f.Index)
+	if f.SubParser != nil {
+		return f.SubParser(s, val.Addr().Interface())
+	}
+	if f.Discriminator != "" {
+		return setInterface(val, group(f.Discriminator), s)
+	}
+	if f.Nested != nil {
+		if f.Nested.Slice {
+			return setStructSliceMatches(context.Background(), val, f.Nested.Regexp, f.Nested.Fields, s)
+		}
+		return setStructMatch(allocate(val), f.Nested.Regexp, f.Nested.Fields, s)
+	}
+	if f.Split != "" {
+		return f.setSplit(val, s)
+	}
+	return f.setValue(val, s)
+}
+
+// setValue sets the value from the string, applying the field's overflow policy
+func (f field) setValue(val reflect.Value, s string) error {
+	set := setField
+	if f.Converter.Type == val.Type() {
+		set = f.Converter.Set
+	}
+	err := set(val, s)
+	if err == nil {
+		return nil
+	}
+	if overflow(val, err, f.Overflow) {
+		return nil
+	}
+	if _, ok := rangeError(err); ok {
+		return &OutOfRange{val.Type(), s, err}
+	}
+	return err
+}
+
+// setSplit sets the slice value to the elements of the
+// string between the field's separator, each parsed like a capture
+func (f field) setSplit(val reflect.Value, s string) error {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, f.Split)
+	elems := reflect.MakeSlice(val.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := f.setValue(elems.Index(i), part); err != nil {
+			return err
+		}
+	}
+	val.Set(elems)
+	return nil
 }
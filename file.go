@@ -0,0 +1,48 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+)
+
+// ParseFile opens the named file of the file system, and parses every line
+// into a new element of the slice argument, like ParseLines. The file is read
+// line by line, rather than all at once, and is closed before returning. Lines
+// are limited to bufio.MaxScanTokenSize bytes, unless the MaxInputSize option
+// allows longer lines.
+//
+// Errors occur if:
+//  - the file fails to open or read, in which case the error is an *fs.PathError
+//  - any of the errors from ParseLines occur, wrapped in an *fs.PathError
+func ParseFile(fsys fs.FS, name string, slicePtr interface{}, opts ...Option) error {
+	return ParseFileContext(context.Background(), fsys, name, slicePtr, opts...)
+}
+
+// ParseFileContext is like ParseFile, but stops parsing lines when the
+// context is done, returning its error. The context is checked between lines.
+func ParseFileContext(ctx context.Context, fsys fs.FS, name string, slicePtr interface{}, opts ...Option) (err error) {
+	o := newOptions(opts)
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	if o.maxInputSize > bufio.MaxScanTokenSize {
+		scanner.Buffer(nil, o.maxInputSize+1)
+	}
+	if err := scanLines(ctx, scanner, slicePtr, opts); err != nil {
+		if _, ok := err.(*fs.PathError); ok || err == ctx.Err() {
+			return err
+		}
+		return &fs.PathError{Op: "parse", Path: name, Err: err}
+	}
+	return nil
+}
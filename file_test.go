@@ -0,0 +1,46 @@
+package structexp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"headers.log": {Data: []byte("WARN: 12\r\nINFO: 3\n")},
+		"mixed.log":   {Data: []byte("WARN: 12\nnot a header\n")},
+		"long.log":    {Data: []byte("INFO: " + strings.Repeat("1", bufio.MaxScanTokenSize) + "\n")},
+	}
+
+	var values []Header
+	require.NoError(t, ParseFile(fsys, "headers.log", &values))
+	assert.EqualValues(t, []Header{{Level: "WARN", Code: 12}, {Level: "INFO", Code: 3}}, values)
+
+	err := ParseFile(fsys, "mixed.log", &values)
+	assert.EqualValues(t, &fs.PathError{Op: "parse", Path: "mixed.log", Err: &LineError{Line: 2, Offset: 9, Err: noMatch[Header]("not a header")}}, err)
+
+	require.NoError(t, ParseFile(fsys, "mixed.log", &values, SkipNoMatch()))
+	assert.EqualValues(t, []Header{{Level: "WARN", Code: 12}}, values)
+
+	err = ParseFile(fsys, "missing.log", &values)
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	err = ParseFile(fsys, "long.log", &values)
+	assert.True(t, errors.Is(err, bufio.ErrTooLong))
+	err = ParseFile(fsys, "long.log", &values, MaxInputSize(2*bufio.MaxScanTokenSize))
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr), "long line should be read and overflow the int, got %v", err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, ParseFileContext(ctx, fsys, "headers.log", &values))
+}
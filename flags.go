@@ -0,0 +1,71 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"strings"
+)
+
+// flagsKey is the tag key of the flags of the template of a StructExp field,
+// such as structexp.flags:"ms" for records spanning several lines
+const flagsKey = "structexp.flags"
+
+// Multiline compiles the whole regular expression with the (?m) flag, so that
+// ^ and $ match at the beginning and end of every line, instead of the text.
+// To set the flag of a single struct's template, tag its StructExp field with
+// structexp.flags:"m" instead.
+func Multiline() Option {
+	return func(o *options) {
+		o.multiline = true
+	}
+}
+
+// DotAll compiles the whole regular expression with the (?s) flag, so that .
+// also matches newlines, such as in the frames of a stack trace. To set the
+// flag of a single struct's template, tag its StructExp field with
+// structexp.flags:"s" instead.
+func DotAll() Option {
+	return func(o *options) {
+		o.dotAll = true
+	}
+}
+
+// optionFlags returns the flag group of the flags set by the options, if any
+func (o *options) optionFlags() string {
+	var flags string
+	if o.ignoreCase {
+		flags += "i"
+	}
+	if o.multiline {
+		flags += "m"
+	}
+	if o.dotAll {
+		flags += "s"
+	}
+	if flags == "" {
+		return ""
+	}
+	return "(?" + flags + ")"
+}
+
+// validFlags reports whether the structexp.flags value is made of the flags
+// of the regexp syntax: i, m, s, and U
+func validFlags(flags string) bool {
+	if flags == "" {
+		return false
+	}
+	for _, flag := range flags {
+		if !strings.ContainsRune("imsU", flag) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldTemplate returns the template of the StructExp field,
+// prefixed with the flag group of its structexp.flags tag
+func fieldTemplate(field reflect.StructField) string {
+	if flags := field.Tag.Get(flagsKey); flags != "" {
+		return "(?" + flags + ")" + field.Tag.Get(tagKey)
+	}
+	return field.Tag.Get(tagKey)
+}
@@ -0,0 +1,123 @@
+package structexp
+
+import (
+	"reflect"
+	"regexp/syntax"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type StackTrace struct {
+	StructExp `structexp:"^panic: {{message}}\\n\\n{{frames}}$" structexp.flags:"s"`
+	Message   string `structexp.name:"message" structexp.exp:"[^\\n]+"`
+	Frames    string `structexp.name:"frames" structexp.exp:".+"`
+}
+
+type ConfigLine struct {
+	StructExp `structexp:"^{{key}} = {{value}}$" structexp.flags:"m"`
+	Key       string `structexp.name:"key" structexp.exp:"[a-z]+"`
+	Value     string `structexp.name:"value" structexp.exp:"[^\\n]*"`
+}
+
+type InvalidFlagsStruct struct {
+	StructExp `structexp:"^{{test}}$" structexp.flags:"x"`
+	Value     string `structexp.name:"test"`
+}
+
+func TestFlags(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "dot all",
+			String: "panic: oops\n\ngoroutine 1 [running]:\nmain.main()\n\t/tmp/main.go:4 +0x18",
+			Input:  &StackTrace{},
+			Expected: &StackTrace{
+				Message: "oops",
+				Frames:  "goroutine 1 [running]:\nmain.main()\n\t/tmp/main.go:4 +0x18",
+			},
+			Error: nil,
+		},
+		{
+			Name:     "multiline",
+			String:   "# settings\nname = structexp\n",
+			Input:    &ConfigLine{},
+			Expected: &ConfigLine{Key: "name", Value: "structexp"},
+			Error:    nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestFlagsParseAll(t *testing.T) {
+	values, err := ParseAllAs[ConfigLine]("name = structexp\n# comment\nversion = 1\n")
+	require.NoError(t, err)
+	assert.EqualValues(t, []ConfigLine{{Key: "name", Value: "structexp"}, {Key: "version", Value: "1"}}, values)
+}
+
+func TestFlagOptions(t *testing.T) {
+	type TestCase struct {
+		Name    string
+		Options []Option
+		Value   Header
+		Error   error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:    "multiline",
+			Options: []Option{Multiline()},
+			Value:   Header{Level: "WARN", Code: 12},
+			Error:   nil,
+		},
+		{
+			Name:    "no flags",
+			Options: nil,
+			Value:   Header{},
+			Error:   noMatch[Header]("starting\nWARN: 12\ndone"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := Compile[Header](tc.Options...)
+			require.NoError(t, err)
+
+			value, err := parser.Parse("starting\nWARN: 12\ndone")
+			assert.EqualValues(t, tc.Value, value)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+
+	// The flags of the tag are not those of another template
+	trace, err := Compile[StackTrace](WithTemplate("^panic: {{message}}\n\n{{frames}}$"), DotAll())
+	require.NoError(t, err)
+	value, err := trace.Parse("panic: oops\n\nmain.main()\n\t/tmp/main.go:4")
+	require.NoError(t, err)
+	assert.EqualValues(t, StackTrace{Message: "oops", Frames: "main.main()\n\t/tmp/main.go:4"}, value)
+}
+
+func TestValidateFlags(t *testing.T) {
+	assert.NoError(t, Validate(StackTrace{}))
+	assert.EqualValues(t, &InvalidDefinition{reflect.TypeOf(InvalidFlagsStruct{}), []error{
+		&InvalidTag{"StructExp", `structexp:"^{{test}}$" structexp.flags:"x"`, "invalid value of structexp.flags", nil},
+		&syntax.Error{Code: syntax.ErrInvalidPerlOp, Expr: "(?x"},
+	}}, Validate(InvalidFlagsStruct{}))
+}
@@ -0,0 +1,269 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+// Format renders the struct argument back into a string using its regular
+// expression, the reverse of Parse: each field's capture group is replaced
+// with the field's value, and the literal text of the expression is kept.
+//
+// Parts of the expression that are not literal text are rendered as simply
+// as possible: optional and repeated parts are rendered once if they contain
+// a capture group for a field with a non-zero value, and not at all otherwise;
+// alternations render their first branch with such a capture group, or their
+// first branch if none do; and character classes render a space if they
+// match one, or their first character otherwise.
+//
+// Field values are rendered as their decimal or literal text, or with
+// encoding.TextMarshaler, fmt.Stringer, or fmt's default format for other
+// types. Nested structs with their own StructExp field are formatted with
+// their own expression, and fields parsed by the json, kv, and query
+// sub-parsers are rendered in those formats. Fields tagged parse-only with
+// structexp.only are not rendered, and their capture groups are rendered like
+// the rest of the expression.
+//
+// Errors occur if:
+//  - argument is not a struct or the address of a struct
+//  - struct is missing a StructExp field
+//  - a field's value fails to render
+func Format(i interface{}) (string, error) {
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if kind := v.Kind(); kind != reflect.Struct {
+		return "", &NotStruct{kind}
+	}
+
+	regxp, fields, err := compile(v.Type())
+	if err != nil {
+		return "", err
+	}
+	return format(v, regxp, fields)
+}
+
+// format renders the struct value with its regular expression and fields
+func format(v reflect.Value, regxp *regexp.Regexp, fields []*field) (string, error) {
+	exp, err := syntax.Parse(regxp.String(), syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+
+	f := &formatter{v: v, fields: make(map[string]*field, len(fields))}
+	for _, field := range fields {
+		// The whole match, and raw text, are rendered by the other fields
+		if field.ParseOnly || field.CaptureGroupName == WholeMatch || field.Raw != "" {
+			continue
+		}
+		if _, ok := f.fields[field.CaptureGroupName]; !ok {
+			f.fields[field.CaptureGroupName] = field
+		}
+	}
+	if err := f.format(exp); err != nil {
+		return "", err
+	}
+	return f.b.String(), nil
+}
+
+// formatter renders a struct value into a string, walking its parsed expression
+type formatter struct {
+	b      strings.Builder
+	v      reflect.Value
+	fields map[string]*field
+}
+
+func (f *formatter) format(exp *syntax.Regexp) error {
+	// nolint:exhaustive // unnecessary
+	switch exp.Op {
+	case syntax.OpLiteral:
+		f.b.WriteString(string(exp.Rune))
+	case syntax.OpCharClass:
+		f.b.WriteRune(classRune(exp.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		f.b.WriteByte(' ')
+	case syntax.OpCapture:
+		if field, ok := f.fields[exp.Name]; ok {
+			return f.formatField(field)
+		}
+		return f.format(exp.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range exp.Sub {
+			if err := f.format(sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpStar, syntax.OpQuest:
+		if f.hasValue(exp.Sub[0]) {
+			return f.format(exp.Sub[0])
+		}
+	case syntax.OpPlus:
+		return f.format(exp.Sub[0])
+	case syntax.OpRepeat:
+		count := exp.Min
+		if count == 0 && f.hasValue(exp.Sub[0]) {
+			count = 1
+		}
+		for n := 0; n < count; n++ {
+			if err := f.format(exp.Sub[0]); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		for _, sub := range exp.Sub {
+			if f.hasValue(sub) {
+				return f.format(sub)
+			}
+		}
+		return f.format(exp.Sub[0])
+	}
+	return nil
+}
+
+// hasValue reports whether the expression has a capture
+// group for a field with a non-zero value
+func (f *formatter) hasValue(exp *syntax.Regexp) bool {
+	if exp.Op == syntax.OpCapture {
+		if field, ok := f.fields[exp.Name]; ok {
+			val, ok := lookupField(f.v, field.Index)
+			return ok && !val.IsZero()
+		}
+	}
+	for _, sub := range exp.Sub {
+		if f.hasValue(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *formatter) formatField(field *field) error {
+	val, ok := lookupField(f.v, field.Index)
+	if !ok {
+		return nil
+	}
+
+	var s string
+	var err error
+	switch {
+	case field.Nested != nil && field.Nested.Slice:
+		for n := 0; n < val.Len(); n++ {
+			var elem string
+			if elem, err = Format(val.Index(n).Interface()); err != nil {
+				return err
+			}
+			s += elem
+		}
+	case field.Nested != nil:
+		s, err = Format(val.Interface())
+	case field.Split != "":
+		elems := make([]string, val.Len())
+		for n := range elems {
+			if elems[n], err = formatValue(val.Index(n)); err != nil {
+				return err
+			}
+		}
+		s = strings.Join(elems, field.Split)
+	case field.SubParserName == JSONSubParser:
+		var b []byte
+		b, err = json.Marshal(val.Interface())
+		s = string(b)
+	case field.SubParserName == KeyValueSubParser:
+		s, err = formatKeyValues(val)
+	case field.SubParserName == QuerySubParser:
+		s, err = formatQuery(val)
+	default:
+		s, err = formatValue(val)
+	}
+	if err != nil {
+		return err
+	}
+
+	if field.Escape != nil {
+		s = field.Escape(s)
+	}
+	f.b.WriteString(s)
+	return nil
+}
+
+// formatValue renders the value of a field as the text it would be parsed from
+func formatValue(val reflect.Value) (string, error) {
+	for val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", nil
+		}
+		if hasStructExp(val.Elem().Type()) || hasStructExp(derefType(val.Elem().Type())) {
+			return Format(val.Interface())
+		}
+		val = val.Elem()
+	}
+
+	if val.CanAddr() {
+		if marshaler, ok := val.Addr().Interface().(encoding.TextMarshaler); ok {
+			b, err := marshaler.MarshalText()
+			return string(b), err
+		}
+	}
+	if marshaler, ok := val.Interface().(encoding.TextMarshaler); ok {
+		b, err := marshaler.MarshalText()
+		return string(b), err
+	}
+	if stringer, ok := val.Interface().(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+
+	// nolint:exhaustive // unnecessary
+	switch val.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), nil
+	case reflect.Int:
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Uint8:
+		return string([]byte{byte(val.Uint())}), nil
+	case reflect.Int32:
+		return string(rune(val.Int())), nil
+	case reflect.Struct:
+		if hasStructExp(val.Type()) {
+			return Format(val.Interface())
+		}
+	}
+	return fmt.Sprint(val.Interface()), nil
+}
+
+// lookupField gets the nested field by its index, without allocating
+// nil struct pointers, reporting false if one is found along the way
+func lookupField(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+// classRune picks the character to render for a character class:
+// a space if the class matches one, or its first character otherwise
+func classRune(ranges []rune) rune {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] <= ' ' && ' ' <= ranges[i+1] {
+			return ' '
+		}
+	}
+	if len(ranges) == 0 {
+		return ' '
+	}
+	return ranges[0]
+}
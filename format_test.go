@@ -0,0 +1,120 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type OptionalReason struct {
+	StructExp `structexp:"^{{code}}(?: {{reason}})?\\s+(?:ok|fail)$"`
+	Code      int    `structexp.name:"code"`
+	Reason    string `structexp.name:"reason"`
+}
+
+func TestFormat(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Input    interface{}
+		Expected string
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Basic",
+			Input:    &Bool{Value: true},
+			Expected: "true",
+			Error:    nil,
+		},
+		{
+			Name:     "Value",
+			Input:    Int{Value: 100},
+			Expected: "100",
+			Error:    nil,
+		},
+		{
+			Name:     "Rune",
+			Input:    &Rune{Value: 'é'},
+			Expected: "é",
+			Error:    nil,
+		},
+		{
+			Name:     "NestedStructExp",
+			Input:    &ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}, Code: 34},
+			Expected: "[WARN: 12] 34",
+			Error:    nil,
+		},
+		{
+			Name:     "NestedStructExpPointer",
+			Input:    &ParentHeaderPointerStruct{Header: &Header{Level: "WARN", Code: 12}},
+			Expected: "[WARN: 12]",
+			Error:    nil,
+		},
+		{
+			Name:     "StructSlice",
+			Input:    &EntriesStruct{Entries: []Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}},
+			Expected: "entries: a=1;b=2;",
+			Error:    nil,
+		},
+		{
+			Name:     "QuotedToken",
+			Input:    &Quoted{Value: `say "hi"`},
+			Expected: `msg="say \"hi\""`,
+			Error:    nil,
+		},
+		{
+			Name:     "OptionalPresent",
+			Input:    &OptionalReason{Code: 404, Reason: "missing"},
+			Expected: "404 missing ok",
+			Error:    nil,
+		},
+		{
+			Name:     "OptionalAbsent",
+			Input:    &OptionalReason{Code: 200},
+			Expected: "200 ok",
+			Error:    nil,
+		},
+		{
+			Name:     "Interface",
+			Input:    &Event{Type: "login", Payload: &LoginPayload{User: "bob"}},
+			Expected: "login user=bob",
+			Error:    nil,
+		},
+		{
+			Name:     "NotStructError",
+			Input:    0,
+			Expected: "",
+			Error:    &NotStruct{reflect.Int},
+		},
+		{
+			Name:     "MissingFieldError",
+			Input:    &MissingFieldStruct{},
+			Expected: "",
+			Error:    &MissingField{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			s, err := Format(tc.Input)
+			assert.Equal(t, tc.Expected, s)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	input := `{"a":1} | b=2 c="d e" | INFO: 3`
+	var value SubParsedStruct
+	assert.NoError(t, Parse(input, &value))
+
+	s, err := Format(&value)
+	assert.NoError(t, err)
+
+	var parsed SubParsedStruct
+	assert.NoError(t, Parse(s, &parsed))
+	assert.Equal(t, value, parsed)
+}
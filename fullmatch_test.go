@@ -0,0 +1,68 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullMatch(t *testing.T) {
+	type TestCase struct {
+		Name  string
+		Input string
+		Value RawHeader
+		Error error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:  "whole input",
+			Input: "WARN: 12",
+			Value: RawHeader{Raw: "WARN: 12", Level: "WARN", Code: 12},
+			Error: nil,
+		},
+		{
+			Name:  "text before",
+			Input: "at 10:00 WARN: 12",
+			Value: RawHeader{},
+			Error: noMatch[RawHeader]("at 10:00 WARN: 12", FullMatch(), RequireAnchors(), Multiline()),
+		},
+		{
+			Name:  "text after",
+			Input: "WARN: 12 ms",
+			Value: RawHeader{},
+			Error: noMatch[RawHeader]("WARN: 12 ms", FullMatch(), RequireAnchors(), Multiline()),
+		},
+		{
+			Name:  "trailing newline",
+			Input: "WARN: 12\n",
+			Value: RawHeader{},
+			Error: noMatch[RawHeader]("WARN: 12\n", FullMatch(), RequireAnchors(), Multiline()),
+		},
+	}
+
+	parser, err := Compile[RawHeader](FullMatch(), RequireAnchors(), Multiline())
+	require.NoError(t, err)
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			value, err := parser.Parse(tc.Input)
+			assert.EqualValues(t, tc.Value, value)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestFullMatchParseAll(t *testing.T) {
+	parser, err := Compile[RawHeader](FullMatch())
+	require.NoError(t, err)
+
+	values, err := parser.ParseAll("WARN: 12, INFO: 3")
+	require.NoError(t, err)
+	assert.EqualValues(t, []RawHeader{
+		{Raw: "WARN: 12", Level: "WARN", Code: 12},
+		{Raw: "INFO: 3", Level: "INFO", Code: 3},
+	}, values)
+}
@@ -0,0 +1,56 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type IgnoreCaseStruct struct {
+	StructExp `structexp:"^level={{level}} method={{method}}$"`
+	Level     string `structexp.name:"level" structexp.exp:"info|warn"`
+	Method    string `structexp.name:"method" structexp.exp:"GET|POST" structexp.ignorecase:"true"`
+}
+
+func TestIgnoreCase(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		String   string
+		Expected IgnoreCaseStruct
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "FieldTag",
+			String:   "level=info method=Get",
+			Expected: IgnoreCaseStruct{Level: "info", Method: "Get"},
+		},
+		{
+			Name:   "FieldTagOnly",
+			String: "LEVEL=INFO method=get",
+			Error:  noMatch[IgnoreCaseStruct]("LEVEL=INFO method=get"),
+		},
+		{
+			Name:     "Option",
+			Options:  []Option{IgnoreCase()},
+			String:   "LEVEL=INFO Method=get",
+			Expected: IgnoreCaseStruct{Level: "INFO", Method: "get"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := MustCompile[IgnoreCaseStruct](tc.Options...).Parse(tc.String)
+			assert.EqualValues(t, tc.Expected, v)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestIgnoreCaseAnchored(t *testing.T) {
+	_, err := Compile[IgnoreCaseStruct](IgnoreCase(), RequireAnchors())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,89 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// maxIncludeDepth limits the nesting of includes, beyond any cycle
+const maxIncludeDepth = 32
+
+// includeRegexp matches the include directive {{> name}}
+var includeRegexp = regexp.MustCompile(`{{>\s*([^{}]*?)\s*}}`)
+
+// Loader loads templates by name, for the include directive {{> name}}.
+// Templates can then live in versioned files instead of struct tags.
+type Loader interface {
+	Load(name string) (string, error)
+}
+
+// LoaderFunc is a function loading templates by name
+type LoaderFunc func(name string) (string, error)
+
+// Load calls the function
+func (f LoaderFunc) Load(name string) (string, error) {
+	return f(name)
+}
+
+// FSLoader loads templates from the files of the file system, such as an
+// embed.FS, by their path. The trailing newline of a file is removed.
+func FSLoader(fsys fs.FS) Loader {
+	return LoaderFunc(func(name string) (string, error) {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	})
+}
+
+// WithLoader replaces every include directive {{> name}} in the Parser's
+// template with the template the loader loads by name, in which includes
+// are also replaced, by names relative to the included name's directory. The template may be the struct's tag or one given by
+// WithTemplate, and can consist of only an include, such as
+// `structexp:"{{> access.tmpl}}"`. Templates of nested structs are not loaded.
+func WithLoader(loader Loader) Option {
+	return func(o *options) {
+		o.loader = loader
+	}
+}
+
+// expandIncludes replaces the include directives in the template
+// with their loaded templates, given the names being included
+func expandIncludes(template string, loader Loader, includes *regexp.Regexp, including []string) (string, error) {
+	var err error
+	expanded := includes.ReplaceAllStringFunc(template, func(directive string) string {
+		if err != nil {
+			return directive
+		}
+		name := includes.FindStringSubmatch(directive)[1]
+		if len(including) > 0 {
+			// Names are relative to the including template
+			name = path.Join(path.Dir(including[len(including)-1]), name)
+		}
+		for _, parent := range including {
+			if parent == name {
+				err = &IncludeCycle{append(including, name)}
+				return directive
+			}
+		}
+		if len(including) >= maxIncludeDepth {
+			err = &IncludeCycle{append(including, name)}
+			return directive
+		}
+
+		included, loadErr := loader.Load(name)
+		if loadErr != nil {
+			err = &IncludeError{name, loadErr}
+			return directive
+		}
+		included, err = expandIncludes(included, loader, includes, append(including, name))
+		return included
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
@@ -0,0 +1,61 @@
+package structexp
+
+import (
+	"embed"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/templates
+var templates embed.FS
+
+type IncludeStruct struct {
+	StructExp `structexp:"{{> testdata/templates/header.tmpl}}"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code      int    `structexp.name:"code"`
+}
+
+func TestWithLoader(t *testing.T) {
+	parser, err := Compile[IncludeStruct](WithLoader(FSLoader(templates)))
+	require.NoError(t, err)
+	assert.Equal(t, `^(?P<level>[A-Z]+): (?P<code>[[:digit:]]+)$`, parser.Regexp().String())
+
+	value, err := parser.Parse("WARN: 12")
+	require.NoError(t, err)
+	assert.EqualValues(t, IncludeStruct{Level: "WARN", Code: 12}, value)
+}
+
+func TestWithLoaderTemplate(t *testing.T) {
+	fsys := fstest.MapFS{"code.tmpl": {Data: []byte("{{code}}")}}
+	parser, err := Compile[Header](WithTemplate(`^{{level}} {{> code.tmpl}}$`), WithLoader(FSLoader(fsys)))
+	require.NoError(t, err)
+
+	value, err := parser.Parse("INFO 3")
+	require.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "INFO", Code: 3}, value)
+}
+
+func TestWithLoaderErrors(t *testing.T) {
+	loader := FSLoader(templates)
+
+	_, err := Compile[Header](WithTemplate(`{{> missing.tmpl}}`), WithLoader(loader))
+	var includeErr *IncludeError
+	require.ErrorAs(t, err, &includeErr)
+	assert.Equal(t, "missing.tmpl", includeErr.Name)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = Compile[Header](WithTemplate(`{{> testdata/templates/cycle.tmpl}}`), WithLoader(loader))
+	assert.EqualValues(t, &IncludeCycle{[]string{"testdata/templates/cycle.tmpl", "testdata/templates/cycle.tmpl"}}, err)
+
+	var loads int
+	_, err = Compile[Header](WithTemplate(`{{> a}}`), WithLoader(LoaderFunc(func(name string) (string, error) {
+		loads++
+		return "{{> " + name + "a}}", nil
+	})))
+	assert.IsType(t, &IncludeCycle{}, err)
+	assert.Equal(t, maxIncludeDepth, loads)
+}
@@ -0,0 +1,81 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// nameIndexedGroups names the unnamed capture groups bound to fields by
+// position with the structexp.index tag, after their capture group names,
+// so the rest of the package finds them by name like any other
+func nameIndexedGroups(t reflect.Type, regxp *regexp.Regexp, fields []*field) (*regexp.Regexp, error) {
+	indexed := map[int]*field{}
+	names := regxp.SubexpNames()
+	for _, f := range fields {
+		if f.GroupIndex == 0 {
+			continue
+		}
+		if f.GroupIndex >= len(names) || names[f.GroupIndex] != "" {
+			return nil, &UnknownIndex{fieldPath(t, f.Index), f.GroupIndex}
+		}
+		indexed[f.GroupIndex] = f
+	}
+	if len(indexed) == 0 {
+		return regxp, nil
+	}
+
+	exp := regxp.String()
+	var b strings.Builder
+	last := 0
+	for i, offset := range captureOffsets(exp) {
+		if f, ok := indexed[i+1]; ok {
+			b.WriteString(exp[last : offset+1])
+			b.WriteString("?P<" + f.CaptureGroupName + ">")
+			last = offset + 1
+		}
+	}
+	b.WriteString(exp[last:])
+	return regexp.Compile(b.String())
+}
+
+// captureOffsets returns the offsets of the opening parentheses
+// of the capture groups in the expression, in order
+func captureOffsets(exp string) []int {
+	var offsets []int
+	for i := 0; i < len(exp); i++ {
+		switch exp[i] {
+		case '\\':
+			if strings.HasPrefix(exp[i:], `\Q`) {
+				// Quoted text is literal up to \E
+				end := strings.Index(exp[i:], `\E`)
+				if end == -1 {
+					return offsets
+				}
+				i += end + 1
+				continue
+			}
+			i++
+		case '[':
+			// Parentheses in character classes are literal
+			i++
+			if i < len(exp) && exp[i] == '^' {
+				i++
+			}
+			if i < len(exp) && exp[i] == ']' {
+				i++
+			}
+			for ; i < len(exp) && exp[i] != ']'; i++ {
+				if exp[i] == '\\' {
+					i++
+				}
+			}
+		case '(':
+			rest := exp[i+1:]
+			if !strings.HasPrefix(rest, "?") || strings.HasPrefix(rest, "?P<") || strings.HasPrefix(rest, "?<") {
+				offsets = append(offsets, i)
+			}
+		}
+	}
+	return offsets
+}
@@ -0,0 +1,69 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type IndexStruct struct {
+	StructExp `structexp:"^([0-9]{4})-([0-9]{2})-[0-9]{2} ([A-Z]+) {{msg}}$"`
+	Year      int    `structexp.index:"1"`
+	Month     int    `structexp.index:"2"`
+	Level     string `structexp.name:"level" structexp.index:"3"`
+	Message   string `structexp.name:"msg"`
+}
+
+type LegacyStruct struct {
+	Year  int    `structexp.index:"1"`
+	Level string `structexp.index:"2"`
+}
+
+func TestIndex(t *testing.T) {
+	var value IndexStruct
+	require.NoError(t, Parse("2024-03-09 WARN disk full", &value))
+	assert.EqualValues(t, IndexStruct{Year: 2024, Month: 3, Level: "WARN", Message: "disk full"}, value)
+	assert.NoError(t, Validate(value))
+
+	parser, err := Compile[LegacyStruct](WithTemplate(`^(\d+):(?:x|y) (\w+)$`))
+	require.NoError(t, err)
+	assert.Equal(t, `^(?P<Year>\d+):(?:x|y) (?P<Level>\w+)$`, parser.Regexp().String())
+
+	legacy, err := parser.Parse("12:x INFO")
+	require.NoError(t, err)
+	assert.EqualValues(t, LegacyStruct{Year: 12, Level: "INFO"}, legacy)
+
+	s, err := parser.Format(LegacyStruct{Year: 7, Level: "DEBUG"})
+	require.NoError(t, err)
+	assert.Equal(t, "7:x DEBUG", s)
+}
+
+func TestIndexErrors(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Template string
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "OutOfRange",
+			Template: `^(\d+)$`,
+			Error:    &UnknownIndex{"Level", 2},
+		},
+		{
+			Name:     "NamedGroup",
+			Template: `^(\d+)(?P<level>\w+)$`,
+			Error:    &UnknownIndex{"Level", 2},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := Compile[LegacyStruct](WithTemplate(tc.Template))
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
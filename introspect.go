@@ -0,0 +1,71 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// FieldGroup names the capture group a struct field is parsed from
+type FieldGroup struct {
+	// Field is the path of the field from the struct, such as Header.Level
+	Field string
+	// Group is the name of the capture group in the regular expression
+	Group string
+	// Exp is the regular expression of the capture group
+	Exp string
+}
+
+// RegexpFor returns the regular expression assembled for the struct type of
+// the argument, which may be a struct, a pointer to one, or its reflect.Type,
+// and the capture group of each field in the order of the struct. It allows
+// logging or debugging the regular expression without parsing a string.
+//
+// Errors occur in the same cases as building the regular expression in Parse.
+func RegexpFor(i interface{}) (*regexp.Regexp, []FieldGroup, error) {
+	t, ok := i.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(i)
+	}
+	if t == nil {
+		return nil, nil, &NotStruct{reflect.Invalid}
+	}
+	if t = derefType(t); t.Kind() != reflect.Struct {
+		return nil, nil, &NotStruct{t.Kind()}
+	}
+
+	regxp, fields, err := compile(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	return regxp, fieldGroups(t, fields), nil
+}
+
+// Groups returns the capture group of each field of T, like RegexpFor
+func (p *Parser[T]) Groups() []FieldGroup {
+	return fieldGroups(reflect.TypeOf((*T)(nil)).Elem(), p.fields)
+}
+
+func fieldGroups(t reflect.Type, fields []*field) []FieldGroup {
+	groups := make([]FieldGroup, 0, len(fields))
+	for _, f := range fields {
+		groups = append(groups, FieldGroup{
+			Field: fieldPath(t, f.Index),
+			Group: f.CaptureGroupName,
+			Exp:   f.Exp,
+		})
+	}
+	return groups
+}
+
+// fieldPath joins the names of the fields along the index,
+// following struct pointers like fieldByIndex
+func fieldPath(t reflect.Type, index []int) string {
+	names := make([]string, 0, len(index))
+	for _, i := range index {
+		f := derefType(t).Field(i)
+		names = append(names, f.Name)
+		t = f.Type
+	}
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,92 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexpFor(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Input    interface{}
+		Expected string
+		Groups   []FieldGroup
+		Error    error
+	}
+
+	headerGroups := []FieldGroup{
+		{Field: "Level", Group: "level", Exp: "[A-Z]+"},
+		{Field: "Code", Group: "code", Exp: DefaultIntRegexp},
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Struct",
+			Input:    Header{},
+			Expected: `^(?P<level>[A-Z]+): (?P<code>[[:digit:]]+)$`,
+			Groups:   headerGroups,
+			Error:    nil,
+		},
+		{
+			Name:     "Pointer",
+			Input:    &Header{},
+			Expected: `^(?P<level>[A-Z]+): (?P<code>[[:digit:]]+)$`,
+			Groups:   headerGroups,
+			Error:    nil,
+		},
+		{
+			Name:     "Type",
+			Input:    reflect.TypeOf(Header{}),
+			Expected: `^(?P<level>[A-Z]+): (?P<code>[[:digit:]]+)$`,
+			Groups:   headerGroups,
+			Error:    nil,
+		},
+		{
+			Name:     "NestedPointer",
+			Input:    &ParentNestedPointerStruct{},
+			Expected: `(?P<test>[[:print:]]+)`,
+			Groups:   []FieldGroup{{Field: "Nested.Value", Group: "test", Exp: DefaultStringRegexp}},
+			Error:    nil,
+		},
+		{
+			Name:  "NotStructError",
+			Input: new(int),
+			Error: &NotStruct{reflect.Int},
+		},
+		{
+			Name:  "NilError",
+			Input: nil,
+			Error: &NotStruct{reflect.Invalid},
+		},
+		{
+			Name:  "MissingFieldError",
+			Input: MissingFieldStruct{},
+			Error: &MissingField{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			regxp, groups, err := RegexpFor(tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+			if tc.Error != nil {
+				return
+			}
+			assert.Equal(t, tc.Expected, regxp.String())
+			assert.EqualValues(t, tc.Groups, groups)
+		})
+	}
+}
+
+func TestParserGroups(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct]()
+	groups := parser.Groups()
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Header", groups[0].Field)
+	assert.Equal(t, "Header", groups[0].Group)
+	assert.EqualValues(t, FieldGroup{Field: "Code", Group: "code", Exp: DefaultIntRegexp}, groups[1])
+}
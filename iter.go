@@ -0,0 +1,97 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// All returns an iterator over the values of the struct type T parsed from
+// every match in the string, like ParseAllAs. Each match is found and parsed
+// only when the loop reaches it, so breaking out of the loop early skips the
+// rest of the string.
+//
+// A match whose fields fail to parse yields its error, and iteration continues
+// with the next match. If T cannot be compiled, its error is yielded once.
+func All[T any](s string) iter.Seq2[T, error] {
+	p, err := Compile[T]()
+	if err != nil {
+		return yieldError[T](err)
+	}
+	return p.All(s)
+}
+
+// AllReader returns an iterator over the values of the struct type T parsed
+// from every line read from the reader, like Decoder. Lines are read only when
+// the loop reaches them.
+//
+// A line that fails to parse yields its error as a LineError, and iteration
+// continues with the next line. A read error is yielded last. If T cannot be
+// compiled, its error is yielded once.
+func AllReader[T any](r io.Reader) iter.Seq2[T, error] {
+	p, err := Compile[T]()
+	if err != nil {
+		return yieldError[T](err)
+	}
+	return p.AllReader(r)
+}
+
+// All returns an iterator over the values parsed from every match in the string, like All
+func (p *Parser[T]) All(s string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if err := checkInputSize(len(s), p.opts); err != nil {
+			var v T
+			yield(v, err)
+			return
+		}
+
+		// Follows the search of regexp's FindAll methods from each match's end
+		for pos, prevEnd := 0, -1; pos <= len(s); {
+			loc := p.search.findScanned(p.scanned, s, pos)
+			if loc == nil {
+				return
+			}
+			var accept bool
+			if pos, prevEnd, accept = nextSearch(s, pos, prevEnd, loc); !accept {
+				continue
+			}
+
+			var v T
+			err := setFields(reflect.ValueOf(&v).Elem(), p.all, p.fields, submatches(s, loc))
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
+
+// AllReader returns an iterator over the values parsed from every line read from the reader, like AllReader
+func (p *Parser[T]) AllReader(r io.Reader) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		scanner := bufio.NewScanner(r)
+		positions := newRecordPositions()
+		scanner.Split(positions.split(bufio.ScanLines))
+		for scanner.Scan() {
+			v, err := p.Parse(scanner.Text())
+			if err != nil {
+				err = &LineError{Line: positions.record.Line, Offset: positions.record.Offset, Err: err}
+			}
+			if !yield(v, err) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			var v T
+			yield(v, err)
+		}
+	}
+}
+
+// yieldError returns an iterator yielding only the error
+func yieldError[T any](err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var v T
+		yield(v, err)
+	}
+}
@@ -0,0 +1,125 @@
+package structexp
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAll(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected []Entry
+		Errors   []error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Matches",
+			String:   "a=b; c=d; e=f;",
+			Expected: []Entry{{Key: "a", Value: "b"}, {Key: "c", Value: "d"}, {Key: "e", Value: "f"}},
+			Errors:   []error{nil, nil, nil},
+		},
+		{
+			Name:     "NoMatches",
+			String:   "a b c",
+			Expected: nil,
+			Errors:   nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var values []Entry
+			var errs []error
+			for v, err := range All[Entry](tc.String) {
+				values = append(values, v)
+				errs = append(errs, err)
+			}
+			assert.EqualValues(t, tc.Expected, values)
+			assert.EqualValues(t, tc.Errors, errs)
+		})
+	}
+}
+
+func TestAllEmptyMatches(t *testing.T) {
+	parser := MustCompile[Entry](WithTemplate(`{{key}}?`))
+	var keys []string
+	for v, err := range parser.All("ab;;c") {
+		assert.NoError(t, err)
+		keys = append(keys, v.Key)
+	}
+	// Like FindAllString, empty matches directly after a match are skipped
+	assert.EqualValues(t, parser.Regexp().FindAllString("ab;;c", -1), keys)
+	assert.EqualValues(t, []string{"ab", "", "c"}, keys)
+}
+
+func TestAllWordBoundary(t *testing.T) {
+	type Letter struct {
+		StructExp `structexp:"\\b{{w}}"`
+		W         string `structexp.name:"w" structexp.exp:"[a-z]"`
+	}
+
+	// The text before each search is the context of \b, as with FindAllString
+	var letters []string
+	for v, err := range MustCompile[Letter]().All("ab cd") {
+		assert.NoError(t, err)
+		letters = append(letters, v.W)
+	}
+	assert.EqualValues(t, regexp.MustCompile(`\b[a-z]`).FindAllString("ab cd", -1), letters)
+	assert.EqualValues(t, []string{"a", "c"}, letters)
+
+	var all []Letter
+	assert.NoError(t, ParseAll("ab cd", &all))
+	assert.EqualValues(t, []Letter{{W: "a"}, {W: "c"}}, all)
+}
+
+func TestAllBreak(t *testing.T) {
+	var values []Entry
+	for v := range All[Entry]("a=b; c=d; e=f;") {
+		values = append(values, v)
+		if len(values) == 2 {
+			break
+		}
+	}
+	assert.EqualValues(t, []Entry{{Key: "a", Value: "b"}, {Key: "c", Value: "d"}}, values)
+}
+
+func TestAllErrors(t *testing.T) {
+	for _, err := range All[MissingFieldStruct]("a") {
+		assert.EqualValues(t, &MissingField{}, err)
+	}
+
+	var errs []error
+	for _, err := range All[Header]("WARN: 99999999999999999999 INFO: 1") {
+		errs = append(errs, err)
+	}
+	assert.Len(t, errs, 2)
+	assert.IsType(t, &FieldError{}, errs[0])
+	assert.ErrorAs(t, errs[0], new(*OutOfRange))
+	assert.NoError(t, errs[1])
+}
+
+func TestAllReader(t *testing.T) {
+	var values []Int
+	var errs []error
+	for v, err := range AllReader[Int](strings.NewReader("1\na\n3\n")) {
+		values = append(values, v)
+		errs = append(errs, err)
+	}
+	assert.EqualValues(t, []Int{{Value: 1}, {}, {Value: 3}}, values)
+	assert.EqualValues(t, []error{nil, &LineError{Line: 2, Offset: 2, Err: noMatch[Int]("a")}, nil}, errs)
+
+	readErr := errors.New("read failed")
+	errs = nil
+	for _, err := range AllReader[Int](iotest.ErrReader(readErr)) {
+		errs = append(errs, err)
+	}
+	assert.EqualValues(t, []error{readErr}, errs)
+}
@@ -0,0 +1,24 @@
+package structexp // nolint:golint // in another file
+
+// JoinFieldErrors continues setting the fields after a field fails to parse,
+// instead of stopping at the first, and returns the errors of every failing
+// field joined with errors.Join, for cleaning dirty data in a single pass.
+// The fields that fail to convert are left unset, and the others are set as
+// usual. A field that fails validation, such as with structexp.max, keeps its
+// converted value, as it does without the option.
+// Each error, such as a FieldError, is found with errors.As.
+func JoinFieldErrors() Option {
+	return func(o *options) {
+		o.joinFieldErrors = true
+	}
+}
+
+// setJoinErrors marks the errors of the fields, and of the fields of nested structs, to be joined
+func setJoinErrors(fields []*field) {
+	for _, f := range fields {
+		f.JoinErrors = true
+		if f.Nested != nil {
+			setJoinErrors(f.Nested.Fields)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package structexp
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DirtyStruct struct {
+	StructExp `structexp:"^{{name}} {{count}} {{ok}} {{size}}$"`
+	Name      string `structexp.name:"name" structexp.exp:"[a-z]+"`
+	Count     int    `structexp.name:"count" structexp.exp:"\\w+"`
+	OK        bool   `structexp.name:"ok" structexp.exp:"\\w+"`
+	Size      int    `structexp.name:"size" structexp.exp:"\\w+" structexp.max:"10"`
+}
+
+func TestJoinFieldErrors(t *testing.T) {
+	countErr := &FieldError{"Count", "count", "many", &strconv.NumError{Func: "ParseInt", Num: "many", Err: strconv.ErrSyntax}}
+	okErr := &FieldError{"OK", "ok", "yes", &strconv.NumError{Func: "ParseBool", Num: "yes", Err: strconv.ErrSyntax}}
+	sizeErr := &ValidationError{"Size", "structexp.max", "10", "12"}
+
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		Input    string
+		Expected DirtyStruct
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "FirstError",
+			Input:    "disk many yes 12",
+			Expected: DirtyStruct{Name: "disk"},
+			Error:    countErr,
+		},
+		{
+			// Count and OK fail to convert and are left unset, while Size fails
+			// validation after it is converted, and keeps its value
+			Name:     "Joined",
+			Options:  []Option{JoinFieldErrors()},
+			Input:    "disk many yes 12",
+			Expected: DirtyStruct{Name: "disk", Size: 12},
+			Error:    errors.Join(countErr, okErr, sizeErr),
+		},
+		{
+			Name:     "JoinedValidation",
+			Options:  []Option{JoinFieldErrors()},
+			Input:    "disk 2 true 12",
+			Expected: DirtyStruct{Name: "disk", Count: 2, OK: true, Size: 12},
+			Error:    errors.Join(sizeErr),
+		},
+		{
+			Name:     "JoinedOne",
+			Options:  []Option{JoinFieldErrors()},
+			Input:    "disk many true 3",
+			Expected: DirtyStruct{Name: "disk", OK: true, Size: 3},
+			Error:    errors.Join(countErr),
+		},
+		{
+			Name:     "NoErrors",
+			Options:  []Option{JoinFieldErrors()},
+			Input:    "disk 2 true 3",
+			Expected: DirtyStruct{Name: "disk", Count: 2, OK: true, Size: 3},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			parser := MustCompile[DirtyStruct](tc.Options...)
+			value, err := parser.Parse(tc.Input)
+			assert.EqualValues(t, tc.Expected, value)
+			assert.EqualValues(t, tc.Error, err)
+
+			// Byte slices are set the same
+			var fromBytes DirtyStruct
+			err = parser.ParseBytesInto([]byte(tc.Input), &fromBytes)
+			assert.EqualValues(t, tc.Expected, fromBytes)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestJoinFieldErrorsNested(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct](JoinFieldErrors())
+	value, err := parser.Parse("[WARN: 99999999999999999999] 99999999999999999999")
+	assert.EqualValues(t, ParentHeaderStruct{Header: Header{Level: "WARN"}}, value)
+
+	rangeErr := func(path, group string) error {
+		return &FieldError{path, group, "99999999999999999999", &OutOfRange{reflect.TypeOf(0), "99999999999999999999",
+			&strconv.NumError{Func: "ParseInt", Num: "99999999999999999999", Err: strconv.ErrRange}}}
+	}
+	assert.EqualValues(t, errors.Join(rangeErr("Header.Code", "code"), rangeErr("Code", "code")), err)
+
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "Header.Code", fieldErr.Field)
+}
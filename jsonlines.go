@@ -0,0 +1,40 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SniffJSON parses records that are JSON objects with encoding/json, honoring
+// the struct's json tags, and all other records with the struct's regular
+// expression. It suits inputs mixing JSON lines with unstructured lines,
+// such as logs of services migrating to structured logging.
+func SniffJSON() Option {
+	return func(o *options) {
+		o.sniffJSON = true
+	}
+}
+
+// isJSONObject reports whether the record is a JSON object
+func isJSONObject(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && json.Valid([]byte(s))
+}
+
+// isJSONObjectBytes reports whether the byte slice is a JSON object, like isJSONObject
+func isJSONObjectBytes(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return bytes.HasPrefix(b, []byte("{")) && bytes.HasSuffix(b, []byte("}")) && json.Valid(b)
+}
+
+// setRecord sets the struct's fields from the record, with encoding/json
+// if the options sniff JSON and it is a JSON object, or else from the
+// match of the regular expressions of the typeParser's templates in the record
+func setRecord(v reflect.Value, p *typeParser, s string, o *options) error {
+	if o.sniffJSON && isJSONObject(s) {
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return p.parse(v, s)
+}
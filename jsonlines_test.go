@@ -0,0 +1,65 @@
+package structexp
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type JSONHeader struct {
+	StructExp `structexp:"^{{level}}: {{code}}$" json:"-"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]+" json:"level"`
+	Code      int    `structexp.name:"code" json:"code"`
+}
+
+func TestSniffJSON(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected JSONHeader
+		Error    bool
+	}
+
+	testCases := []TestCase{
+		{Name: "Template", String: "WARN: 12", Expected: JSONHeader{Level: "WARN", Code: 12}},
+		{Name: "JSON", String: ` {"level": "INFO", "code": 3} `, Expected: JSONHeader{Level: "INFO", Code: 3}},
+		{Name: "JSONTypeError", String: `{"level": "INFO", "code": "3"}`, Expected: JSONHeader{Level: "INFO"}, Error: true},
+		{Name: "InvalidJSON", String: `{"level": INFO}`, Error: true},
+	}
+
+	parser := MustCompile[JSONHeader](SniffJSON())
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			value, err := parser.Parse(tc.String)
+			assert.Equal(t, tc.Error, err != nil)
+			assert.EqualValues(t, tc.Expected, value)
+		})
+	}
+
+	_, err := MustCompile[JSONHeader]().Parse(`{"level": "INFO", "code": 3}`)
+	assert.EqualValues(t, noMatch[JSONHeader](`{"level": "INFO", "code": 3}`), err)
+}
+
+func TestParseLinesSniffJSON(t *testing.T) {
+	var values []JSONHeader
+	err := ParseLines("WARN: 12\n{\"level\": \"INFO\", \"code\": 3}\n", &values, SniffJSON())
+	require.NoError(t, err)
+	assert.EqualValues(t, []JSONHeader{{Level: "WARN", Code: 12}, {Level: "INFO", Code: 3}}, values)
+}
+
+func TestDecoderSniffJSON(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("{\"level\": \"INFO\", \"code\": 3}\nWARN: 12\n"))
+	decoder.SniffJSON()
+
+	var value JSONHeader
+	require.NoError(t, decoder.Decode(&value))
+	assert.EqualValues(t, JSONHeader{Level: "INFO", Code: 3}, value)
+	value = JSONHeader{}
+	require.NoError(t, decoder.Decode(&value))
+	assert.EqualValues(t, JSONHeader{Level: "WARN", Code: 12}, value)
+	assert.Equal(t, io.EOF, decoder.Decode(&value))
+}
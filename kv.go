@@ -0,0 +1,178 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// keyValue is a single key=value pair
+type keyValue struct {
+	Key   string
+	Value string
+}
+
+// ParseKeyValues parses whitespace separated key=value pairs into the
+// argument, which must be the address of a map[string]string or a struct.
+// Values may be double quoted, with escaped quotes, to contain whitespace.
+// Struct fields are matched to keys by their structexp.name tag, or their
+// name, and parsed the same way as captures; unknown keys are ignored.
+//
+// Errors occur if:
+//  - argument is not the address of a struct or map[string]string
+//  - a key's value fails to parse into its field
+func ParseKeyValues(s string, i interface{}) error {
+	return assignKeyValues(splitKeyValues(s), i)
+}
+
+// splitKeyValues splits whitespace separated key=value pairs.
+// Keys without a value are given an empty value.
+func splitKeyValues(s string) []keyValue {
+	var pairs []keyValue
+	for s = strings.TrimLeft(s, " \t"); s != ""; s = strings.TrimLeft(s, " \t") {
+		end := strings.IndexAny(s, " \t=")
+		if end == -1 {
+			end = len(s)
+		}
+		pair := keyValue{Key: s[:end]}
+		s = s[end:]
+
+		if strings.HasPrefix(s, "=") {
+			s = s[1:]
+			end = valueEnd(s)
+			pair.Value = s[:end]
+			if strings.HasPrefix(pair.Value, `"`) {
+				pair.Value, _ = unquote(pair.Value)
+			}
+			s = s[end:]
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// valueEnd finds the end of the value at the start of the string,
+// which is either a double quoted string or ends at whitespace
+func valueEnd(s string) int {
+	if strings.HasPrefix(s, `"`) {
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return len(s)
+	}
+	if end := strings.IndexAny(s, " \t"); end != -1 {
+		return end
+	}
+	return len(s)
+}
+
+// assignKeyValues sets the pairs into the map or struct argument
+func assignKeyValues(pairs []keyValue, i interface{}) error {
+	v := reflect.ValueOf(i)
+	if kind := v.Kind(); kind != reflect.Ptr {
+		return &NotStruct{kind}
+	}
+	v = v.Elem()
+
+	// nolint:exhaustive // unnecessary
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type() != reflect.TypeOf(map[string]string{}) {
+			return &NotStruct{v.Kind()}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(pairs)))
+		}
+		for _, pair := range pairs {
+			v.SetMapIndex(reflect.ValueOf(pair.Key), reflect.ValueOf(pair.Value))
+		}
+	case reflect.Struct:
+		fields, err := listFields(v.Type(), nil, nil, defaultRegistry)
+		if err != nil {
+			return err
+		}
+		group := func(name string) string {
+			for _, pair := range pairs {
+				if pair.Key == name {
+					return pair.Value
+				}
+			}
+			return ""
+		}
+		for _, pair := range pairs {
+			for _, field := range fields {
+				if field.CaptureGroupName != pair.Key || field.FormatOnly {
+					continue
+				}
+				if err := field.Set(v, pair.Value, group); err != nil {
+					return field.fieldError(v.Type(), pair.Value, err)
+				}
+			}
+		}
+	default:
+		return &NotStruct{v.Kind()}
+	}
+	return nil
+}
+
+// formatKeyValues renders the map[string]string or struct value as whitespace
+// separated key=value pairs, quoting values that contain whitespace or quotes;
+// the reverse of ParseKeyValues. Map keys are sorted.
+func formatKeyValues(v reflect.Value) (string, error) {
+	pairs, err := listKeyValues(v)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, len(pairs))
+	for i, pair := range pairs {
+		if pair.Value == "" || strings.ContainsAny(pair.Value, " \t\"") {
+			pair.Value = quote(pair.Value)
+		}
+		parts[i] = pair.Key + "=" + pair.Value
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// listKeyValues lists the pairs of the map[string]string
+// or struct value, in sorted key or field order
+func listKeyValues(v reflect.Value) ([]keyValue, error) {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	var pairs []keyValue
+	// nolint:exhaustive // unnecessary
+	switch v.Kind() {
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			pairs = append(pairs, keyValue{key.String(), v.MapIndex(key).String()})
+		}
+	case reflect.Struct:
+		fields, err := listFields(v.Type(), nil, nil, defaultRegistry)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			val, ok := lookupField(v, field.Index)
+			if !ok || field.CaptureGroupName == WholeMatch || field.Raw != "" {
+				continue
+			}
+			s, err := formatValue(val)
+			if err != nil {
+				return nil, err
+			}
+			pairs = append(pairs, keyValue{field.CaptureGroupName, s})
+		}
+	default:
+		return nil, &NotStruct{v.Kind()}
+	}
+	return pairs, nil
+}
@@ -0,0 +1,58 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type KeyValueStruct struct {
+	Level   string `structexp.name:"level"`
+	Code    int    `structexp.name:"code"`
+	Message string `structexp.name:"msg"`
+	Verbose bool
+}
+
+func TestParseKeyValues(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Map",
+			String:   `a=1  b="two \"2\"" c`,
+			Input:    &map[string]string{},
+			Expected: &map[string]string{"a": "1", "b": `two "2"`, "c": ""},
+			Error:    nil,
+		},
+		{
+			Name:     "Struct",
+			String:   `level=warn code=12 msg="disk full" Verbose=true other=x`,
+			Input:    &KeyValueStruct{},
+			Expected: &KeyValueStruct{Level: "warn", Code: 12, Message: "disk full", Verbose: true},
+			Error:    nil,
+		},
+		{
+			Name:     "NotStructError",
+			String:   "a=1",
+			Input:    KeyValueStruct{},
+			Expected: KeyValueStruct{},
+			Error:    &NotStruct{reflect.Struct},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ParseKeyValues(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// LazyResult holds the match of a record, converting each field of the
+// value only when it is first accessed with Field or LazyField, or all of
+// them with Value, to skip the conversion of fields never read.
+//
+// A LazyResult is not safe for concurrent use by multiple goroutines.
+type LazyResult[T any] struct {
+	parser  *Parser[T]
+	matches []string
+	value   T
+	// Errors of the fields already converted, by index in the Parser's fields
+	converted map[int]error
+}
+
+// ParseLazy matches the string like Parse, returning a LazyResult converting
+// the fields of the new T on first access. Records that are JSON objects,
+// with the SniffJSON option, are decoded at once.
+//
+// Errors occur if the string exceeds the MaxInputSize option, does not match,
+// or its JSON fails to unmarshal.
+func (p *Parser[T]) ParseLazy(s string) (*LazyResult[T], error) {
+	s = p.input(s)
+	if err := checkInputSize(len(s), p.opts); err != nil {
+		return nil, err
+	}
+	r := &LazyResult[T]{parser: p, converted: make(map[int]error, len(p.fields))}
+	if p.opts.sniffJSON && isJSONObject(s) {
+		if err := json.Unmarshal([]byte(s), &r.value); err != nil {
+			return nil, err
+		}
+		for i := range p.fields {
+			r.converted[i] = nil
+		}
+		return r, nil
+	}
+	loc := findSubmatchIndex(p.regexp, p.scanned, s)
+	if loc == nil {
+		return nil, newNoMatch(p.regexp, s)
+	}
+	r.matches = submatches(s, loc)
+	return r, nil
+}
+
+// Field converts the field with the path, such as Level or Header.Code,
+// if it has not been already, and returns its value.
+//
+// Errors occur if the path names no field, or the field fails to convert.
+func (r *LazyResult[T]) Field(path string) (interface{}, error) {
+	t := reflect.TypeOf(r.value)
+	for i, f := range r.parser.fields {
+		if fieldPath(t, f.Index) == path {
+			if err := r.convert(i); err != nil {
+				return nil, err
+			}
+			return fieldByIndex(reflect.ValueOf(&r.value).Elem(), f.Index).Interface(), nil
+		}
+	}
+	return nil, &UnknownField{path}
+}
+
+// LazyField returns the value of the field with the path of
+// the LazyResult, converted on first access, as the type F
+//
+// Errors occur if the path names no field, the field fails
+// to convert, or the field's type is not F.
+func LazyField[F any, T any](r *LazyResult[T], path string) (F, error) {
+	var value F
+	i, err := r.Field(path)
+	if err != nil {
+		return value, err
+	}
+	value, ok := i.(F)
+	if !ok {
+		return value, &FieldTypeMismatch{path, reflect.TypeOf(i), reflect.TypeOf((*F)(nil)).Elem()}
+	}
+	return value, nil
+}
+
+// Value converts the fields not already converted, and returns the value,
+// like the result of Parse. The error is that of the first field to fail.
+func (r *LazyResult[T]) Value() (T, error) {
+	for i := range r.parser.fields {
+		if err := r.convert(i); err != nil {
+			return r.value, err
+		}
+	}
+	return r.value, nil
+}
+
+// convert sets the field with the index from its submatch, once
+func (r *LazyResult[T]) convert(i int) error {
+	if err, ok := r.converted[i]; ok {
+		return err
+	}
+	v := reflect.ValueOf(&r.value).Elem()
+	err := setFields(v, r.parser.regexp, r.parser.fields[i:i+1], r.matches)
+	r.converted[i] = err
+	return err
+}
@@ -0,0 +1,86 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLazy(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct]()
+	r, err := parser.ParseLazy("[WARN: 12] 99999999999999999999")
+	require.NoError(t, err)
+
+	header, err := LazyField[Header](r, "Header")
+	assert.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "WARN", Code: 12}, header)
+
+	// The failing field is only converted when accessed
+	_, err = r.Field("Code")
+	assert.IsType(t, &FieldError{}, err)
+	assert.ErrorAs(t, err, new(*OutOfRange))
+	_, err = LazyField[int](r, "Code")
+	assert.IsType(t, &FieldError{}, err)
+	assert.ErrorAs(t, err, new(*OutOfRange))
+
+	_, err = LazyField[string](r, "Header")
+	assert.EqualValues(t, &FieldTypeMismatch{"Header", reflect.TypeOf(Header{}), reflect.TypeOf("")}, err)
+	_, err = r.Field("Message")
+	assert.EqualValues(t, &UnknownField{"Message"}, err)
+
+	v, err := r.Value()
+	assert.IsType(t, &FieldError{}, err)
+	assert.ErrorAs(t, err, new(*OutOfRange))
+	assert.EqualValues(t, ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}}, v)
+}
+
+func TestParseLazyValue(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		String   string
+		Expected Header
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Match",
+			String:   "INFO: 3",
+			Expected: Header{Level: "INFO", Code: 3},
+		},
+		{
+			Name:     "JSON",
+			Options:  []Option{SniffJSON()},
+			String:   `{"Level": "WARN", "Code": 1}`,
+			Expected: Header{Level: "WARN", Code: 1},
+		},
+		{
+			Name:   "NoMatch",
+			String: "INFO",
+			Error:  noMatch[Header]("INFO"),
+		},
+		{
+			Name:    "InputTooLarge",
+			Options: []Option{MaxInputSize(3)},
+			String:  "INFO: 3",
+			Error:   &InputTooLarge{7, 3},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			r, err := MustCompile[Header](tc.Options...).ParseLazy(tc.String)
+			assert.EqualValues(t, tc.Error, err)
+			if err != nil {
+				return
+			}
+			v, err := r.Value()
+			assert.NoError(t, err)
+			assert.EqualValues(t, tc.Expected, v)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"context"
+	"reflect"
+	"strings"
+)
+
+// ParseLines parses every line of the string into a new element of the slice
+// argument, replacing its contents. Lines end with \n or \r\n, and a final
+// empty line is ignored. By default a line that does not match returns a
+// LineError; with the SkipNoMatch option, it is skipped instead. The other
+// options apply as they do to the Parser of Compile, such as SniffJSON parsing
+// lines that are JSON objects with encoding/json. Lines are parsed in order,
+// whatever the Workers option.
+//
+// Errors occur if:
+//  - argument is not the address of a slice of structs, or pointers to structs
+//  - struct is missing a StructExp field
+//  - a line does not match, unless SkipNoMatch is given
+//  - a line's fields fail to parse, or its JSON fails to unmarshal
+func ParseLines(s string, slicePtr interface{}, opts ...Option) error {
+	return ParseLinesContext(context.Background(), s, slicePtr, opts...)
+}
+
+// ParseLinesContext is like ParseLines, but stops parsing lines when the
+// context is done, returning its error. The context is checked between lines.
+func ParseLinesContext(ctx context.Context, s string, slicePtr interface{}, opts ...Option) error {
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	// The string is already in memory, so any line length is allowed
+	scanner.Buffer(nil, len(s)+1)
+	return scanLines(ctx, scanner, slicePtr, opts)
+}
+
+// scanLines parses every line of the scanner into a new element
+// of the slice argument, replacing its contents, like ParseLines
+func scanLines(ctx context.Context, scanner *bufio.Scanner, slicePtr interface{}, opts []Option) error {
+	v := reflect.ValueOf(slicePtr)
+	if kind := v.Kind(); kind != reflect.Ptr {
+		return &NotSlice{kind}
+	}
+	v = v.Elem()
+	if kind := v.Kind(); kind != reflect.Slice {
+		return &NotSlice{kind}
+	}
+	structType := derefType(v.Type().Elem())
+	if kind := structType.Kind(); kind != reflect.Struct {
+		return &NotStruct{kind}
+	}
+
+	o := newOptions(opts)
+	p, err := linesParser(structType, o)
+	if err != nil {
+		return err
+	}
+
+	positions := newRecordPositions()
+	scanner.Split(positions.split(bufio.ScanLines))
+	elems := reflect.MakeSlice(v.Type(), 0, 0)
+	elem := reflect.New(v.Type().Elem()).Elem()
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		elem.Set(reflect.Zero(elem.Type()))
+		if err := setRecord(allocate(elem), p, scanner.Text(), o); err != nil {
+			if o.skipNoMatch && isNoMatch(err) {
+				continue
+			}
+			return &LineError{Line: positions.record.Line, Offset: positions.record.Offset, Err: err}
+		}
+		elems = reflect.Append(elems, elem)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	v.Set(elems)
+	return nil
+}
+
+// linesParser returns the typeParser of the struct type compiled with the
+// options, or its cached typeParser if they only set how records are handled
+func linesParser(t reflect.Type, o *options) (*typeParser, error) {
+	if o.recordOnly() {
+		return cachedParser(t)
+	}
+	return compileParser(t, o)
+}
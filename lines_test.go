@@ -0,0 +1,119 @@
+package structexp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLines(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Options  []Option
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Structs",
+			String:   "1\r\n2\n3\n",
+			Input:    &[]Int{},
+			Expected: &[]Int{{Value: 1}, {Value: 2}, {Value: 3}},
+			Error:    nil,
+		},
+		{
+			Name:     "StructPointers",
+			String:   "1\n2",
+			Input:    &[]*Int{},
+			Expected: &[]*Int{{Value: 1}, {Value: 2}},
+			Error:    nil,
+		},
+		{
+			Name:     "SkipNoMatch",
+			String:   "1\na\n\n3",
+			Input:    &[]Int{},
+			Options:  []Option{SkipNoMatch()},
+			Expected: &[]Int{{Value: 1}, {Value: 3}},
+			Error:    nil,
+		},
+		{
+			Name:     "NoMatchError",
+			String:   "1\na\n3",
+			Input:    &[]Int{},
+			Expected: &[]Int{},
+			Error:    &LineError{Line: 2, Offset: 2, Err: noMatch[Int]("a")},
+		},
+		{
+			Name:     "WithTemplate",
+			String:   "#1\n#2",
+			Input:    &[]Int{},
+			Options:  []Option{WithTemplate("^#{{test}}$")},
+			Expected: &[]Int{{Value: 1}, {Value: 2}},
+			Error:    nil,
+		},
+		{
+			Name:     "IgnoreCase",
+			String:   "WARN: 1\nwarn: 2",
+			Input:    &[]Header{},
+			Options:  []Option{IgnoreCase(), SkipNoMatch()},
+			Expected: &[]Header{{Level: "WARN", Code: 1}, {Level: "warn", Code: 2}},
+			Error:    nil,
+		},
+		{
+			Name:     "MaxInputSizeError",
+			String:   "1\n1234",
+			Input:    &[]Int{},
+			Options:  []Option{MaxInputSize(3)},
+			Expected: &[]Int{},
+			Error:    &LineError{Line: 2, Offset: 2, Err: &InputTooLarge{4, 3}},
+		},
+		{
+			Name:     "NotSliceError",
+			String:   "1",
+			Input:    &Int{},
+			Expected: &Int{},
+			Error:    &NotSlice{reflect.Struct},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ParseLines(tc.String, tc.Input, tc.Options...)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestParseLinesContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var values []Int
+	err := ParseLinesContext(ctx, "1\n2\n", &values)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, values)
+}
+
+func TestLinesParser(t *testing.T) {
+	typ := reflect.TypeOf(Int{})
+	cached, err := cachedParser(typ)
+	require.NoError(t, err)
+
+	// Options setting how records are handled parse with the cached typeParser
+	p, err := linesParser(typ, newOptions([]Option{SkipNoMatch(), SniffJSON()}))
+	require.NoError(t, err)
+	assert.Same(t, cached, p)
+
+	p, err = linesParser(typ, newOptions([]Option{SkipNoMatch(), IgnoreCase()}))
+	require.NoError(t, err)
+	assert.NotSame(t, cached, p)
+	assert.True(t, p.opts.ignoreCase)
+}
@@ -0,0 +1,69 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ListStruct struct {
+	StructExp `structexp:"^ports={{ports}} tags={{tags}}(?: ids={{ids}})?$"`
+	Ports     []int    `structexp.name:"ports" structexp.split:","`
+	Tags      []string `structexp.name:"tags" structexp.split:"|" structexp.exp:"[a-z|]+"`
+	IDs       []uint16 `structexp.name:"ids" structexp.split:";" structexp.exp:"[0-9;]+"`
+}
+
+func TestSplit(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Input    string
+		Expected ListStruct
+		Error    bool
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Lists",
+			Input:    "ports=80,443,8080 tags=web|tls ids=1;2",
+			Expected: ListStruct{Ports: []int{80, 443, 8080}, Tags: []string{"web", "tls"}, IDs: []uint16{1, 2}},
+		},
+		{
+			Name:     "SingleElements",
+			Input:    "ports=22 tags=ssh",
+			Expected: ListStruct{Ports: []int{22}, Tags: []string{"ssh"}},
+		},
+		{
+			Name:     "ElementError",
+			Input:    "ports=1 tags=a ids=1;99999",
+			Expected: ListStruct{Ports: []int{1}, Tags: []string{"a"}},
+			Error:    true,
+		},
+	}
+
+	parser, err := Compile[ListStruct]()
+	require.NoError(t, err)
+	assert.Equal(t, `^ports=(?P<ports>(?:[[:digit:]]+)(?:,(?:[[:digit:]]+))*) tags=(?P<tags>[a-z|]+)(?: ids=(?P<ids>[0-9;]+))?$`, parser.Regexp().String())
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			value, err := parser.Parse(tc.Input)
+			if tc.Error {
+				assert.IsType(t, &FieldError{}, err)
+				assert.ErrorAs(t, err, new(*OutOfRange))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.EqualValues(t, tc.Expected, value)
+		})
+	}
+}
+
+func TestSplitFormat(t *testing.T) {
+	s, err := Format(ListStruct{Ports: []int{80, 443}, Tags: []string{"web"}, IDs: []uint16{7, 8}})
+	require.NoError(t, err)
+	assert.Equal(t, "ports=80,443 tags=web ids=7;8", s)
+
+	assert.NoError(t, Validate(ListStruct{}))
+}
@@ -0,0 +1,220 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Quota limits the resources of each tenant of a Manager. Zero values are unlimited.
+type Quota struct {
+	// MaxParsers is the number of Parsers a tenant can hold
+	MaxParsers int
+}
+
+// Manager holds the Parsers of many tenants, such as the customers of a log
+// processing service, isolated from each other. Each tenant has its own
+// Registry of sub-parsers, Quota, and metrics.
+//
+// Patterns, variants, and factories, registered with RegisterPattern,
+// RegisterVariant, and RegisterFactory, are not held by a Registry: they
+// are shared by the Parsers of every tenant, so they are for the service
+// to register, not its tenants.
+//
+// A Manager is safe for concurrent use by multiple goroutines.
+type Manager struct {
+	quota Quota
+	opts  []Option
+
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+}
+
+// NewManager returns a Manager limiting every tenant to the quota,
+// and compiling every tenant's Parsers with the options
+func NewManager(quota Quota, opts ...Option) *Manager {
+	return &Manager{
+		quota:   quota,
+		opts:    opts,
+		tenants: map[string]*Tenant{},
+	}
+}
+
+// Tenant returns the tenant with the id, adding it if it does not exist
+func (m *Manager) Tenant(id string) *Tenant {
+	m.mu.RLock()
+	tenant, ok := m.tenants[id]
+	m.mu.RUnlock()
+	if ok {
+		return tenant
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tenant, ok := m.tenants[id]; ok {
+		return tenant
+	}
+	tenant = &Tenant{
+		id:       id,
+		registry: NewRegistry(),
+		quota:    m.quota,
+		opts:     m.opts,
+		parsers:  map[string]interface{}{},
+	}
+	m.tenants[id] = tenant
+	return tenant
+}
+
+// RemoveTenant removes the tenant with the id and all of its Parsers
+func (m *Manager) RemoveTenant(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tenants, id)
+}
+
+// Tenants returns the ids of the tenants in sorted order
+func (m *Manager) Tenants() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]string, 0, len(m.tenants))
+	for id := range m.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Tenant holds the named Parsers of one tenant of a Manager.
+// Parsers are added with AddParser and retrieved with TenantParser.
+type Tenant struct {
+	id       string
+	registry *Registry
+	quota    Quota
+	opts     []Option
+
+	mu      sync.RWMutex
+	parsers map[string]interface{}
+
+	// Accessed atomically
+	parses int64
+	errors int64
+	total  int64
+}
+
+// TenantMetrics describes the parses by all Parsers of a tenant
+type TenantMetrics struct {
+	Parsers        int
+	Parses         int64
+	Errors         int64
+	TotalParseTime time.Duration
+}
+
+// ID returns the id of the tenant
+func (t *Tenant) ID() string {
+	return t.id
+}
+
+// Registry returns the tenant's own Registry, used by all of its Parsers.
+// It holds sub-parsers and transforms only, not patterns, variants, or factories.
+func (t *Tenant) Registry() *Registry {
+	return t.registry
+}
+
+// Metrics returns the metrics of the tenant. It is safe to call while parsing.
+func (t *Tenant) Metrics() TenantMetrics {
+	t.mu.RLock()
+	parsers := len(t.parsers)
+	t.mu.RUnlock()
+	return TenantMetrics{
+		Parsers:        parsers,
+		Parses:         atomic.LoadInt64(&t.parses),
+		Errors:         atomic.LoadInt64(&t.errors),
+		TotalParseTime: time.Duration(atomic.LoadInt64(&t.total)),
+	}
+}
+
+// Stats returns the Stats of each of the tenant's Parsers by name
+func (t *Tenant) Stats() map[string]Stats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stats := make(map[string]Stats, len(t.parsers))
+	for name, p := range t.parsers {
+		stats[name] = p.(interface{ Stats() Stats }).Stats()
+	}
+	return stats
+}
+
+// RemoveParser removes the tenant's Parser with the name
+func (t *Tenant) RemoveParser(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.parsers, name)
+}
+
+// observe records a parse in the tenant's metrics
+func (t *Tenant) observe(d time.Duration, err error) {
+	atomic.AddInt64(&t.parses, 1)
+	atomic.AddInt64(&t.total, int64(d))
+	if err != nil {
+		atomic.AddInt64(&t.errors, 1)
+	}
+}
+
+// AddParser compiles a Parser for the struct type T with the tenant's
+// Registry, the Manager's options, and then the options given, and adds it
+// to the tenant by name, replacing any Parser with the same name.
+// Any MetricsHook option is called in addition to the tenant's metrics.
+//
+// Errors occur if:
+//  - adding the Parser would exceed the tenant's MaxParsers quota, which is
+//    checked before compiling it
+//  - the Parser fails to compile, like Compile
+func AddParser[T any](t *Tenant, name string, opts ...Option) (*Parser[T], error) {
+	t.mu.RLock()
+	err := t.exceedsQuota(name)
+	t.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(append(append([]Option{}, t.opts...), opts...), WithRegistry(t.registry))
+	hook := newOptions(opts).metricsHook
+	opts = append(opts, WithMetricsHook(func(d time.Duration, err error) {
+		t.observe(d, err)
+		if hook != nil {
+			hook(d, err)
+		}
+	}))
+
+	p, err := Compile[T](opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checked again, for Parsers added while compiling
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.exceedsQuota(name); err != nil {
+		return nil, err
+	}
+	t.parsers[name] = p
+	return p, nil
+}
+
+// exceedsQuota returns a QuotaExceeded error if adding a Parser by name
+// would exceed the tenant's MaxParsers quota. The tenant must be locked.
+func (t *Tenant) exceedsQuota(name string) error {
+	if _, ok := t.parsers[name]; !ok && t.quota.MaxParsers > 0 && len(t.parsers) >= t.quota.MaxParsers {
+		return &QuotaExceeded{t.id, t.quota.MaxParsers}
+	}
+	return nil
+}
+
+// TenantParser returns the tenant's Parser with the name, if it is a Parser for the struct type T
+func TenantParser[T any](t *Tenant, name string) (*Parser[T], bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	p, ok := t.parsers[name].(*Parser[T])
+	return p, ok
+}
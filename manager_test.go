@@ -0,0 +1,105 @@
+package structexp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type UpperStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test" structexp.sub:"upper"`
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterSubParser("upper", func(s string, i interface{}) error {
+		*i.(*string) = strings.ToUpper(s)
+		return nil
+	})
+
+	_, err := Compile[UpperStruct]()
+	assert.EqualValues(t, &UnknownSubParser{"upper"}, err)
+
+	parser, err := Compile[UpperStruct](WithRegistry(reg))
+	require.NoError(t, err)
+	value, err := parser.Parse("abc")
+	require.NoError(t, err)
+	assert.EqualValues(t, UpperStruct{Value: "ABC"}, value)
+
+	// Names not in the registry fall back to the default registry
+	_, err = Compile[SubParsedStruct](WithRegistry(reg))
+	assert.NoError(t, err)
+}
+
+func TestManager(t *testing.T) {
+	manager := NewManager(Quota{MaxParsers: 2}, MaxInputSize(16))
+	a, b := manager.Tenant("a"), manager.Tenant("b")
+	assert.Same(t, a, manager.Tenant("a"))
+	assert.Equal(t, []string{"a", "b"}, manager.Tenants())
+
+	a.Registry().RegisterSubParser("upper", func(s string, i interface{}) error {
+		*i.(*string) = strings.ToUpper(s)
+		return nil
+	})
+
+	// Registries are isolated between tenants
+	_, err := AddParser[UpperStruct](b, "upper")
+	assert.EqualValues(t, &UnknownSubParser{"upper"}, err)
+
+	var hooked int
+	upper, err := AddParser[UpperStruct](a, "upper", WithMetricsHook(func(time.Duration, error) { hooked++ }))
+	require.NoError(t, err)
+	_, err = AddParser[Header](a, "header")
+	require.NoError(t, err)
+	_, err = AddParser[Header](a, "header")
+	require.NoError(t, err)
+	_, err = AddParser[Int](a, "int")
+	assert.EqualValues(t, &QuotaExceeded{"a", 2}, err)
+	// The quota is checked before compiling
+	_, err = AddParser[MissingFieldStruct](a, "missing")
+	assert.EqualValues(t, &QuotaExceeded{"a", 2}, err)
+
+	found, ok := TenantParser[UpperStruct](a, "upper")
+	assert.True(t, ok)
+	assert.Same(t, upper, found)
+	_, ok = TenantParser[Header](a, "upper")
+	assert.False(t, ok)
+
+	_, err = upper.Parse("abc")
+	require.NoError(t, err)
+	_, err = upper.Parse("abcdefghijklmnopqrstuvwxyz")
+	assert.EqualValues(t, &InputTooLarge{26, 16}, err)
+	_, err = upper.Parse("")
+	assert.EqualValues(t, NewNoMatch(upper.Regexp(), ""), err)
+
+	metrics := a.Metrics()
+	assert.Equal(t, 2, metrics.Parsers)
+	assert.EqualValues(t, 2, metrics.Parses)
+	assert.EqualValues(t, 1, metrics.Errors)
+	assert.Equal(t, 2, hooked)
+	assert.EqualValues(t, 2, a.Stats()["upper"].Parses)
+	assert.Equal(t, TenantMetrics{}, b.Metrics())
+
+	a.RemoveParser("header")
+	_, err = AddParser[Int](a, "int")
+	assert.NoError(t, err)
+
+	manager.RemoveTenant("a")
+	assert.Equal(t, []string{"b"}, manager.Tenants())
+}
+
+func TestManagerSharedRegistrations(t *testing.T) {
+	manager := NewManager(Quota{})
+	a, b := manager.Tenant("a"), manager.Tenant("b")
+
+	// Variants are not held by a tenant's Registry, so every tenant sees them
+	RegisterVariant[VariantStruct]("tenant=a", Variant{Exps: map[string]string{"status": `\d{3}`}})
+	_, err := AddParser[VariantStruct](a, "variant", WithVariant("tenant=a"))
+	require.NoError(t, err)
+	_, err = AddParser[VariantStruct](b, "variant", WithVariant("tenant=a"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,163 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"regexp/syntax"
+)
+
+// hasStructExp reports whether the type is a struct
+// that defines its own StructExp expression
+func hasStructExp(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, err := regexpBase(t)
+	return err == nil
+}
+
+// isStructExpSlice reports whether the type is a slice of structs, or
+// pointers to structs, that define their own StructExp expression
+func isStructExpSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && hasStructExp(derefType(t.Elem()))
+}
+
+// derefType returns the type pointed to if the type is a pointer
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// splicedExp builds the expression of a struct's regular expression to splice
+// into another expression, without any of its capture groups or the anchors
+// at its beginning and end
+func splicedExp(regxp *regexp.Regexp) (string, error) {
+	exp, err := syntax.Parse(regxp.String(), syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	return removeAnchors(removeCaptures(exp)).String(), nil
+}
+
+// repeatedExp builds the expression matching any number of
+// consecutive matches of a struct's regular expression
+func repeatedExp(regxp *regexp.Regexp) (string, error) {
+	exp, err := splicedExp(regxp)
+	if err != nil {
+		return "", err
+	}
+	return "(?:" + exp + ")*", nil
+}
+
+// removeCaptures replaces every capture group in the parsed
+// expression with its non-capturing contents
+func removeCaptures(exp *syntax.Regexp) *syntax.Regexp {
+	for exp.Op == syntax.OpCapture {
+		exp = exp.Sub[0]
+	}
+	for i, sub := range exp.Sub {
+		exp.Sub[i] = removeCaptures(sub)
+	}
+	return exp
+}
+
+// removeAnchors removes the beginning and end anchors
+// from the start and end of the parsed expression
+func removeAnchors(exp *syntax.Regexp) *syntax.Regexp {
+	if exp.Op != syntax.OpConcat {
+		return exp
+	}
+	for len(exp.Sub) > 0 && (exp.Sub[0].Op == syntax.OpBeginText || exp.Sub[0].Op == syntax.OpBeginLine) {
+		exp.Sub = exp.Sub[1:]
+	}
+	for last := len(exp.Sub) - 1; last >= 0 && (exp.Sub[last].Op == syntax.OpEndText || exp.Sub[last].Op == syntax.OpEndLine); last-- {
+		exp.Sub = exp.Sub[:last]
+	}
+	return exp
+}
+
+// unanchored removes the beginning and end anchors from the start
+// and end of the regular expression, keeping its capture groups
+func unanchored(regxp *regexp.Regexp) (*regexp.Regexp, error) {
+	exp, err := syntax.Parse(regxp.String(), syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(removeAnchors(exp).String())
+}
+
+// setStructExp sets the struct's fields from the match
+// of its own expression in the string
+func setStructExp(v reflect.Value, s string) error {
+	regxp, fields, err := compile(v.Type())
+	if err != nil {
+		return err
+	}
+	return setStructMatch(v, regxp, fields, s)
+}
+
+// setStructMatch sets the struct's fields from the
+// match of the regular expression in the string
+func setStructMatch(v reflect.Value, regxp *regexp.Regexp, fields []*field, s string) error {
+	loc := findSubmatchIndex(regxp, scannedFields(fields), s)
+	if loc == nil {
+		return newNoMatch(regxp, s)
+	}
+	return setFields(v, regxp, fields, submatches(s, loc))
+}
+
+// setStructMatchIndex sets the struct's fields from the match of the regular
+// expression in the string at the location, as from FindStringSubmatchIndex
+func setStructMatchIndex(v reflect.Value, regxp *regexp.Regexp, fields []*field, s string, loc []int) error {
+	if loc == nil {
+		return newNoMatch(regxp, s)
+	}
+	return setFields(v, regxp, fields, submatches(s, loc))
+}
+
+// submatches returns the submatches of the string at the
+// location, like FindStringSubmatch, without matching again
+func submatches(s string, loc []int) []string {
+	matches := make([]string, len(loc)/2)
+	for i := range matches {
+		if loc[2*i] >= 0 {
+			matches[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return matches
+}
+
+// setStructExpSlice fills the slice with a new element for every
+// match of the element struct's expression in the string
+func setStructExpSlice(ctx context.Context, slice reflect.Value, s string) error {
+	regxp, fields, err := compile(derefType(slice.Type().Elem()))
+	if err != nil {
+		return err
+	}
+	// Anchors would prevent finding more than one match
+	if regxp, err = unanchored(regxp); err != nil {
+		return err
+	}
+	return setStructSliceMatches(ctx, slice, regxp, fields, s)
+}
+
+// setStructSliceMatches fills the slice with a new element for
+// every match of the unanchored regular expression in the string,
+// until the context is done
+func setStructSliceMatches(ctx context.Context, slice reflect.Value, regxp *regexp.Regexp, fields []*field, s string) error {
+	allLocs := findAllSubmatchIndex(regxp, scannedFields(fields), s)
+	elems := reflect.MakeSlice(slice.Type(), len(allLocs), len(allLocs))
+	for i, loc := range allLocs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := setFields(elems.Index(i), regxp, fields, submatches(s, loc)); err != nil {
+			return err
+		}
+	}
+	slice.Set(elems)
+	return nil
+}
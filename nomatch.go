@@ -0,0 +1,174 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// Bytes of the input kept in a NoMatch error
+const noMatchInput = 256
+
+// Bytes of the input shown in a NoMatch error, around where the match fell apart
+const noMatchExcerpt = 64
+
+// Patterns whose partial regular expressions are kept; those of other
+// patterns are compiled for each NoMatch error finding its prefix
+const partialRegexpsLimit = 64
+
+// Regular expressions of the partial matches of the patterns, by pattern,
+// or nil if a pattern's could not be compiled, and how many there are
+var (
+	partialRegexps      sync.Map
+	partialRegexpsCount atomic.Int64
+)
+
+// newNoMatch returns the NoMatch error of the regular expression not matching the string
+func newNoMatch(regxp *regexp.Regexp, s string) *NoMatch {
+	return NewNoMatch(regxp, s)
+}
+
+// newNoMatchBytes returns the NoMatch error of the regular expression not
+// matching the byte slice, copying only the bytes the error keeps
+func newNoMatchBytes(regxp *regexp.Regexp, b []byte) *NoMatch {
+	return &NoMatch{Pattern: regxp.String(), Input: string(b[:headSize(b)]), Size: len(b)}
+}
+
+// headSize returns the size of the first bytes of the input kept in a
+// NoMatch error, at most noMatchInput, without cutting a rune
+func headSize[S string | []byte](s S) int {
+	if len(s) <= noMatchInput {
+		return len(s)
+	}
+	n := noMatchInput
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// matchedPrefix returns the length of the longest prefix of the string ending
+// a partial match of the pattern, or -1 if it cannot be found
+func matchedPrefix(pattern, s string) int {
+	partial := partialRegexp(pattern)
+	if partial == nil {
+		return -1
+	}
+	prefix := 0
+	for _, loc := range partial.FindAllStringIndex(s, -1) {
+		// Partial matches are empty wherever an unanchored expression could start
+		if loc[1] > loc[0] && loc[1] > prefix {
+			prefix = loc[1]
+		}
+	}
+	return prefix
+}
+
+// partialRegexp returns the regular expression matching the
+// partial matches of the pattern, the prefixes of its matches
+func partialRegexp(pattern string) *regexp.Regexp {
+	if partial, ok := partialRegexps.Load(pattern); ok {
+		return partial.(*regexp.Regexp)
+	}
+	var partial *regexp.Regexp
+	if re, err := syntax.Parse(pattern, syntax.Perl); err == nil {
+		if partial, err = regexp.Compile(partialExp(re.Simplify()).String()); err == nil {
+			partial.Longest()
+		} else {
+			partial = nil
+		}
+	}
+	// The first patterns are kept, rather than dropping them for others
+	if partialRegexpsCount.Load() < partialRegexpsLimit {
+		if _, loaded := partialRegexps.LoadOrStore(pattern, partial); !loaded {
+			partialRegexpsCount.Add(1)
+		}
+	}
+	return partial
+}
+
+// partialExp returns the expression matching the prefixes of the expression's matches
+func partialExp(re *syntax.Regexp) *syntax.Regexp {
+	// nolint:exhaustive // unnecessary
+	switch re.Op {
+	case syntax.OpLiteral:
+		// Each prefix of the literal, nested as a(?:b(?:c)?)?
+		var partial *syntax.Regexp
+		for i := len(re.Rune) - 1; i >= 0; i-- {
+			r := &syntax.Regexp{Op: syntax.OpLiteral, Flags: re.Flags, Rune: re.Rune[i : i+1]}
+			if partial != nil {
+				r = concatExp(r, partial)
+			}
+			partial = &syntax.Regexp{Op: syntax.OpQuest, Flags: re.Flags, Sub: []*syntax.Regexp{r}}
+		}
+		return partial
+	case syntax.OpCharClass, syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return &syntax.Regexp{Op: syntax.OpQuest, Flags: re.Flags, Sub: []*syntax.Regexp{re}}
+	case syntax.OpCapture, syntax.OpQuest:
+		return partialExp(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		// Any matches, then a partial match
+		star := &syntax.Regexp{Op: syntax.OpStar, Flags: re.Flags, Sub: re.Sub[:1]}
+		return concatExp(star, partialExp(re.Sub[0]))
+	case syntax.OpConcat:
+		// A partial match of the first, or a match of it then a partial match of the rest
+		partial := partialExp(re.Sub[len(re.Sub)-1])
+		for i := len(re.Sub) - 2; i >= 0; i-- {
+			partial = &syntax.Regexp{Op: syntax.OpAlternate, Sub: []*syntax.Regexp{
+				partialExp(re.Sub[i]),
+				concatExp(re.Sub[i], partial),
+			}}
+		}
+		return partial
+	case syntax.OpAlternate:
+		subs := make([]*syntax.Regexp, len(re.Sub))
+		for i, sub := range re.Sub {
+			subs[i] = partialExp(sub)
+		}
+		return &syntax.Regexp{Op: syntax.OpAlternate, Sub: subs}
+	default:
+		// Empty matches and assertions are their own partial matches
+		return re
+	}
+}
+
+// concatExp returns the concatenation of the expressions
+func concatExp(a, b *syntax.Regexp) *syntax.Regexp {
+	return &syntax.Regexp{Op: syntax.OpConcat, Sub: []*syntax.Regexp{a, b}}
+}
+
+// excerpt returns the string, the first bytes of an input of the size, or the
+// part around the offset if it is long, marking the cut ends with ...
+func excerpt(s string, size, offset int) string {
+	if len(s) <= noMatchExcerpt {
+		if size > len(s) {
+			return s + "..."
+		}
+		return s
+	}
+	start := offset - noMatchExcerpt/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + noMatchExcerpt
+	if end > len(s) {
+		end = len(s)
+		start = end - noMatchExcerpt
+	}
+	for start > 0 && !utf8.RuneStart(s[start]) {
+		start++
+	}
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	cut := s[start:end]
+	if start > 0 {
+		cut = "..." + cut
+	}
+	if end < len(s) || size > len(s) {
+		cut += "..."
+	}
+	return cut
+}
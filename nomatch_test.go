@@ -0,0 +1,103 @@
+package structexp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchedPrefix(t *testing.T) {
+	type TestCase struct {
+		Name    string
+		Pattern string
+		Input   string
+		Prefix  int
+	}
+
+	testCases := []TestCase{
+		{Name: "Literal", Pattern: `^abc$`, Input: "abx", Prefix: 2},
+		{Name: "MissingEnd", Pattern: `^(?P<level>[A-Z]+): (?P<code>\d+)$`, Input: "WARN: 12 ms", Prefix: 8},
+		{Name: "MissingRest", Pattern: `^(?P<level>[A-Z]+): (?P<code>\d+)$`, Input: "WARN:", Prefix: 5},
+		{Name: "FirstByte", Pattern: `^\[(?P<level>[A-Z]+)\]$`, Input: "WARN", Prefix: 0},
+		{Name: "Alternation", Pattern: `^(?:GET|POST) /$`, Input: "POS /", Prefix: 3},
+		{Name: "Repeat", Pattern: `^a{2,3}b$`, Input: "aaaac", Prefix: 3},
+		{Name: "Optional", Pattern: `^a(?: b)? c$`, Input: "a b d", Prefix: 4},
+		{Name: "IgnoreCase", Pattern: `^(?i)level=info$`, Input: "LEVEL=WARN", Prefix: 6},
+		{Name: "Unanchored", Pattern: `\d+ ms`, Input: "took 12 s", Prefix: 8},
+		{Name: "UnanchoredNone", Pattern: `\d+`, Input: "abc", Prefix: 0},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			regxp := regexp.MustCompile(tc.Pattern)
+			assert.False(t, regxp.MatchString(tc.Input))
+			assert.Equal(t, tc.Prefix, (&NoMatch{Pattern: tc.Pattern, Input: tc.Input}).Prefix())
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, "short", excerpt("short", 5, 3))
+
+	long := strings.Repeat("a", 50) + "|" + strings.Repeat("b", 50)
+	assert.Equal(t, strings.Repeat("a", 64)+"...", excerpt(strings.Repeat("a", 100), 100, 0))
+	assert.Equal(t, "..."+strings.Repeat("a", 32)+"|"+strings.Repeat("b", 31)+"...", excerpt(long, 101, 50))
+	assert.Equal(t, "..."+strings.Repeat("a", 13)+"|"+strings.Repeat("b", 50), excerpt(long, 101, 101))
+
+	// Runes are not cut
+	assert.Equal(t, "..."+strings.Repeat("é", 31)+"...", excerpt(strings.Repeat("é", 40), 80, 41))
+
+	// The first bytes of a longer input are marked as cut
+	assert.Equal(t, "short...", excerpt("short", 500, 3))
+	assert.Equal(t, "..."+strings.Repeat("a", 13)+"|"+strings.Repeat("b", 50)+"...", excerpt(long, 500, 101))
+}
+
+func TestNoMatchError(t *testing.T) {
+	assert.EqualError(t, &NoMatch{}, "object regular expression has no matches for the input")
+	assert.EqualError(t, &NoMatch{Pattern: "(", Input: "b", Size: 1}, `object regular expression has no matches for the input "b"`)
+
+	var header Header
+	err := Parse("WARN: x", &header)
+	assert.EqualValues(t, noMatch[Header]("WARN: x"), err)
+	assert.EqualError(t, err, `object regular expression has no matches for the input "WARN: x", matching up to byte 6`)
+
+	// The input is cut around the prefix for the message
+	long := strings.Repeat("a", 100)
+	err = Parse(long, &header)
+	assert.EqualValues(t, noMatch[Header](long), err)
+	assert.EqualError(t, err, `object regular expression has no matches for the input "`+strings.Repeat("a", 64)+`...", matching up to byte 0`)
+
+	// Only the first bytes of a long input are kept, from strings and byte slices
+	huge := "WARN: " + strings.Repeat("é", 1000)
+	err = Parse(huge, &header)
+	assert.EqualValues(t, err, ParseBytes([]byte(huge), &header))
+	var noMatchErr *NoMatch
+	require.ErrorAs(t, err, &noMatchErr)
+	assert.Equal(t, huge[:noMatchInput], noMatchErr.Input)
+	assert.Equal(t, len(huge), noMatchErr.Size)
+	assert.Equal(t, 6, noMatchErr.Prefix())
+	assert.EqualError(t, err, `object regular expression has no matches for the input "WARN: `+strings.Repeat("é", 29)+`...", matching up to byte 6`)
+
+	// A rune is not cut
+	odd := "WARN:" + strings.Repeat("é", 1000)
+	require.ErrorAs(t, Parse(odd, &header), &noMatchErr)
+	assert.Equal(t, odd[:noMatchInput-1], noMatchErr.Input)
+}
+
+func TestPartialRegexpsLimit(t *testing.T) {
+	for i := 0; i <= partialRegexpsLimit; i++ {
+		assert.NotNil(t, partialRegexp(fmt.Sprintf("^a{%d}$", i)))
+	}
+	count := 0
+	partialRegexps.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	assert.LessOrEqual(t, count, partialRegexpsLimit)
+	assert.EqualValues(t, count, partialRegexpsCount.Load())
+}
@@ -0,0 +1,110 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// FieldOffset is the location of a field's submatch in the parsed input
+type FieldOffset struct {
+	// Field is the path of the field from the struct, such as Header.Level,
+	// with the index of slice elements, such as Entries[1].Key
+	Field string
+	// Group is the name of the field's capture group
+	Group string
+	// Start and End are the byte offsets of the submatch in the input
+	Start, End int
+}
+
+// ParseWithOffsets parses the string into the struct argument like Parse,
+// and returns the byte offsets of each field's submatch in the string, such
+// as for editors highlighting where each value came from. The fields of
+// nested structs with their own StructExp field, and of their slices, are
+// located within the nested field's submatch. Fields whose group did not
+// participate in the match are left out.
+//
+// Errors occur in the same cases as Parse.
+func ParseWithOffsets(s string, i interface{}) ([]FieldOffset, error) {
+	if err := Parse(s, i); err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(i).Elem()
+	regxp, fields, err := compile(t)
+	if err != nil {
+		return nil, err
+	}
+	return structOffsets(t, regxp, fields, s, 0, ""), nil
+}
+
+// ParseWithOffsets returns a new T parsed from the string, like Parse,
+// and the byte offsets of each field's submatch, like ParseWithOffsets
+func (p *Parser[T]) ParseWithOffsets(s string) (T, []FieldOffset, error) {
+	s = p.input(s)
+	v, err := p.Parse(s)
+	if err != nil {
+		return v, nil, err
+	}
+	return v, structOffsets(reflect.TypeOf(v), p.regexp, p.fields, s, 0, ""), nil
+}
+
+// structOffsets locates the fields in the match of the regular
+// expression in the string, which starts at the base offset
+func structOffsets(t reflect.Type, regxp *regexp.Regexp, fields []*field, s string, base int, prefix string) []FieldOffset {
+	return matchOffsets(t, regxp, fields, s, findSubmatchIndex(regxp, scannedFields(fields), s), base, prefix)
+}
+
+// matchOffsets locates the fields in the submatch indexes of the
+// regular expression in the string, which starts at the base offset
+func matchOffsets(t reflect.Type, regxp *regexp.Regexp, fields []*field, s string, loc []int, base int, prefix string) []FieldOffset {
+	if loc == nil {
+		return nil
+	}
+
+	var offsets []FieldOffset
+	for _, f := range fields {
+		idx := subexpIndex(regxp.SubexpNames(), f.group())
+		if idx == -1 || loc[2*idx] < 0 {
+			continue
+		}
+		start, end := loc[2*idx], loc[2*idx+1]
+		path := prefix + fieldPath(t, f.Index)
+		offsets = append(offsets, FieldOffset{path, f.group(), base + start, base + end})
+
+		if f.Nested == nil || f.Raw != "" {
+			continue
+		}
+		capture := s[start:end]
+		fieldType := fieldType(t, f.Index)
+		if !f.Nested.Slice {
+			offsets = append(offsets, structOffsets(derefType(fieldType), f.Nested.Regexp, f.Nested.Fields, capture, base+start, path+".")...)
+			continue
+		}
+		elemType := derefType(fieldType.Elem())
+		for i, elemLoc := range f.Nested.Regexp.FindAllStringSubmatchIndex(capture, -1) {
+			elemPrefix := fmt.Sprintf("%s[%d].", path, i)
+			offsets = append(offsets, matchOffsets(elemType, f.Nested.Regexp, f.Nested.Fields, capture, elemLoc, base+start, elemPrefix)...)
+		}
+	}
+	return offsets
+}
+
+// structField returns the field along the index,
+// following struct pointers like fieldByIndex
+func structField(t reflect.Type, index []int) reflect.StructField {
+	var f reflect.StructField
+	for _, i := range index {
+		f = derefType(t).Field(i)
+		t = f.Type
+	}
+	return f
+}
+
+// fieldType returns the type of the field along the index,
+// following struct pointers like fieldByIndex
+func fieldType(t reflect.Type, index []int) reflect.Type {
+	for _, i := range index {
+		t = derefType(t).Field(i).Type
+	}
+	return t
+}
@@ -0,0 +1,82 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithOffsets(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected []FieldOffset
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "Fields",
+			String: "WARN: 12",
+			Input:  &Header{},
+			Expected: []FieldOffset{
+				{Field: "Level", Group: "level", Start: 0, End: 4},
+				{Field: "Code", Group: "code", Start: 6, End: 8},
+			},
+			Error: nil,
+		},
+		{
+			Name:   "NestedStructExp",
+			String: "[WARN: 12] 34",
+			Input:  &ParentHeaderStruct{},
+			Expected: []FieldOffset{
+				{Field: "Header", Group: "Header", Start: 1, End: 9},
+				{Field: "Header.Level", Group: "level", Start: 1, End: 5},
+				{Field: "Header.Code", Group: "code", Start: 7, End: 9},
+				{Field: "Code", Group: "code", Start: 11, End: 13},
+			},
+			Error: nil,
+		},
+		{
+			Name:   "StructExpSlice",
+			String: "entries: a=b;cd=e;",
+			Input:  &EntriesStruct{},
+			Expected: []FieldOffset{
+				{Field: "Entries", Group: "test", Start: 9, End: 18},
+				{Field: "Entries[0].Key", Group: "key", Start: 9, End: 10},
+				{Field: "Entries[0].Value", Group: "value", Start: 11, End: 12},
+				{Field: "Entries[1].Key", Group: "key", Start: 13, End: 15},
+				{Field: "Entries[1].Value", Group: "value", Start: 16, End: 17},
+			},
+			Error: nil,
+		},
+		{
+			Name:     "NoMatchError",
+			String:   "a",
+			Input:    &Int{},
+			Expected: nil,
+			Error:    noMatch[Int]("a"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			offsets, err := ParseWithOffsets(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, offsets)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestParserParseWithOffsets(t *testing.T) {
+	value, offsets, err := MustCompile[Header]().ParseWithOffsets("INFO: 3")
+	require.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "INFO", Code: 3}, value)
+	assert.EqualValues(t, []FieldOffset{
+		{Field: "Level", Group: "level", Start: 0, End: 4},
+		{Field: "Code", Group: "code", Start: 6, End: 7},
+	}, offsets)
+}
@@ -0,0 +1,55 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ReadingStruct struct {
+	StructExp `structexp:"^{{Celsius}}C {{Checksum}}(?: \\({{Display}}\\))?$"`
+	Celsius   int
+	Checksum  string `structexp.exp:"[0-9a-f]{4}" structexp.only:"parse"`
+	Display   string `structexp.exp:"[a-z]+" structexp.only:"format"`
+}
+
+func TestOnlyParse(t *testing.T) {
+	var value ReadingStruct
+	require.NoError(t, Parse("21C 9f3a (warm)", &value))
+	assert.EqualValues(t, ReadingStruct{Celsius: 21, Checksum: "9f3a"}, value)
+
+	records, err := ParseAllAs[ReadingStruct]("21C 9f3a (warm)")
+	require.NoError(t, err)
+	assert.EqualValues(t, []ReadingStruct{{Celsius: 21, Checksum: "9f3a"}}, records)
+}
+
+func TestOnlyFormat(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Input    ReadingStruct
+		Expected string
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Computed",
+			Input:    ReadingStruct{Celsius: 21, Checksum: "9f3a", Display: "warm"},
+			Expected: "21C 0000 (warm)",
+		},
+		{
+			Name:     "ComputedAbsent",
+			Input:    ReadingStruct{Celsius: 3, Checksum: "9f3a"},
+			Expected: "3C 0000",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			s, err := Format(tc.Input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, s)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package structexp // nolint:golint // in another file
+
+import "reflect"
+
+// Option configures how strings are parsed
+type Option func(*options)
+
+type options struct {
+	skipNoMatch     bool
+	maxProgramSize  int
+	maxGroups       int
+	maxRepeat       int
+	maxInputSize    int
+	requireAnchors  bool
+	fullMatch       bool
+	metricsHook     MetricsHook
+	template        string
+	registry        *Registry
+	sniffJSON       bool
+	loader          Loader
+	variant         string
+	warningHook     WarningHook
+	overflow        OverflowPolicy
+	strict          bool
+	ignoreCase      bool
+	projection      []string
+	delimiters      *[2]string
+	stripANSI       bool
+	multiline       bool
+	dotAll          bool
+	unicodeDefaults bool
+	workers         int
+	joinFieldErrors bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{registry: defaultRegistry}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// recordOnly reports whether the options only differ from the defaults in how
+// records are handled, with SkipNoMatch and SniffJSON, so that the typeParser
+// compiled without options parses the records like one compiled with them
+func (o *options) recordOnly() bool {
+	compiled := *o
+	compiled.skipNoMatch, compiled.sniffJSON = false, false
+	return reflect.DeepEqual(compiled, *newOptions(nil))
+}
+
+// SkipNoMatch skips the records that do not match, instead of returning
+// a NoMatch error, when parsing multiple records such as with ParseLines
+func SkipNoMatch() Option {
+	return func(o *options) {
+		o.skipNoMatch = true
+	}
+}
+
+// IgnoreCase matches the whole regular expression case-insensitively, by
+// compiling it with the (?i) flag. Captures are converted as they appear
+// in the input. To ignore the case of some fields only, tag them with
+// structexp.ignorecase instead.
+func IgnoreCase() Option {
+	return func(o *options) {
+		o.ignoreCase = true
+	}
+}
+
+// WithTemplate compiles the Parser from the template, instead of the tag of
+// the StructExp field, so the template can be loaded at runtime such as from
+// configuration. The placeholders of the template are filled the same way.
+func WithTemplate(template string) Option {
+	return func(o *options) {
+		o.template = template
+	}
+}
+
+// MaxProgramSize rejects regular expressions whose compiled program has more
+// than n instructions, returning a TooComplex error from Compile. This protects
+// services compiling user-supplied templates from pathologically large ones.
+func MaxProgramSize(n int) Option {
+	return func(o *options) {
+		o.maxProgramSize = n
+	}
+}
+
+// MaxGroups rejects regular expressions with more than n capture
+// groups, returning a TooComplex error from Compile
+func MaxGroups(n int) Option {
+	return func(o *options) {
+		o.maxGroups = n
+	}
+}
+
+// MaxRepeat rejects regular expressions with counted repetitions, such as
+// a{2,1000}, above n, returning a BannedConstruct error from Compile
+func MaxRepeat(n int) Option {
+	return func(o *options) {
+		o.maxRepeat = n
+	}
+}
+
+// MaxInputSize rejects inputs longer than n bytes before matching,
+// returning an InputTooLarge error from the Parser's parse methods
+func MaxInputSize(n int) Option {
+	return func(o *options) {
+		o.maxInputSize = n
+	}
+}
+
+// RequireAnchors rejects regular expressions that do not both begin with
+// a ^ or \A anchor and end with a $ or \z anchor, returning an Unanchored
+// error from Compile, so that they must match the whole input
+func RequireAnchors() Option {
+	return func(o *options) {
+		o.requireAnchors = true
+	}
+}
+
+// FullMatch anchors the regular expression at the beginning and end of the
+// input with \A and \z, whatever the anchors of its template, so that text
+// around a match is rejected with a NoMatch error, such as when validating
+// input rather than extracting from it. Methods finding every match in the
+// input, like ParseAll, ignore the anchors.
+func FullMatch() Option {
+	return func(o *options) {
+		o.fullMatch = true
+	}
+}
+
+// Limits applied by WithUntrustedTemplates
+const (
+	UntrustedMaxProgramSize = 2000
+	UntrustedMaxGroups      = 64
+	UntrustedMaxRepeat      = 100
+	UntrustedMaxInputSize   = 64 << 10
+)
+
+// WithUntrustedTemplates hardens a Parser compiled from a template that is
+// not trusted, such as one supplied by a user of a multi-tenant service, by
+// combining MaxProgramSize, MaxGroups, MaxRepeat, MaxInputSize, and
+// RequireAnchors with the Untrusted limits. Options given after it override
+// its limits.
+func WithUntrustedTemplates() Option {
+	return func(o *options) {
+		MaxProgramSize(UntrustedMaxProgramSize)(o)
+		MaxGroups(UntrustedMaxGroups)(o)
+		MaxRepeat(UntrustedMaxRepeat)(o)
+		MaxInputSize(UntrustedMaxInputSize)(o)
+		RequireAnchors()(o)
+	}
+}
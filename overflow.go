@@ -0,0 +1,127 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// OverflowPolicy decides how numbers out of the range of their field are set
+type OverflowPolicy int
+
+// Overflow policies
+const (
+	// OverflowError fails the parse with an OutOfRange error
+	OverflowError OverflowPolicy = iota
+	// OverflowSaturate sets the field's minimum or maximum value, with a Warning
+	OverflowSaturate
+	// OverflowWrap sets the number modulo the range of the field's size, as
+	// integer conversions do, with a Warning. Floats saturate to infinity.
+	OverflowWrap
+)
+
+func (policy OverflowPolicy) String() string {
+	switch policy {
+	case OverflowError:
+		return "error"
+	case OverflowSaturate:
+		return "saturate"
+	case OverflowWrap:
+		return "wrap"
+	default:
+		return "OverflowPolicy(" + strconv.Itoa(int(policy)) + ")"
+	}
+}
+
+// WithOverflowPolicy sets numbers out of the range of their field, such as
+// a capture of 300 for an int8 field or an element of an []int8 split field,
+// by the policy. Fields of uint8 are set from a byte, and never out of range.
+// By default they fail the parse with an OutOfRange error.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(o *options) {
+		o.overflow = policy
+	}
+}
+
+// LenientConversions saturates numbers out of the range of their field at the
+// field's minimum or maximum value, with a Warning, instead of failing the parse.
+// It is the same as WithOverflowPolicy(OverflowSaturate).
+func LenientConversions() Option {
+	return WithOverflowPolicy(OverflowSaturate)
+}
+
+// setOverflow sets the overflow policy of the fields, and of the fields of nested structs
+func setOverflow(fields []*field, policy OverflowPolicy) {
+	for _, f := range fields {
+		f.Overflow = policy
+		if f.Nested != nil {
+			setOverflow(f.Nested.Fields, policy)
+		}
+	}
+}
+
+// rangeError returns the strconv error if the error is that of a number out of range
+func rangeError(err error) (*strconv.NumError, bool) {
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || !errors.Is(numErr.Err, strconv.ErrRange) {
+		return nil, false
+	}
+	return numErr, true
+}
+
+// overflow sets the number value by the policy, from the range error of
+// converting it, reporting whether it did. The range error holds the
+// minimum or maximum value of the number's size, for saturating it.
+func overflow(val reflect.Value, err error, policy OverflowPolicy) bool {
+	numErr, ok := rangeError(err)
+	if !ok || policy == OverflowError {
+		return false
+	}
+	val = underlyingValue(val)
+	bits := val.Type().Bits()
+
+	// nolint:exhaustive // unnecessary
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if policy == OverflowWrap {
+			n, ok := wrap(numErr.Num, bits)
+			if !ok {
+				return false
+			}
+			if limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1)); n.Cmp(limit) >= 0 {
+				n.Sub(n, new(big.Int).Lsh(limit, 1))
+			}
+			val.SetInt(n.Int64())
+			return true
+		}
+		i, _ := strconv.ParseInt(numErr.Num, 10, bits)
+		val.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if policy == OverflowWrap {
+			n, ok := wrap(numErr.Num, bits)
+			if !ok {
+				return false
+			}
+			val.SetUint(n.Uint64())
+			return true
+		}
+		u, _ := strconv.ParseUint(numErr.Num, 10, bits)
+		val.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, _ := strconv.ParseFloat(numErr.Num, bits)
+		val.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
+
+// wrap returns the decimal integer modulo 2 to the power of the bits
+func wrap(s string, bits int) (*big.Int, bool) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, false
+	}
+	return n.Mod(n, new(big.Int).Lsh(big.NewInt(1), uint(bits))), true
+}
@@ -0,0 +1,129 @@
+package structexp
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverflow(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Policy   OverflowPolicy
+		Input    interface{}
+		String   string
+		Expected interface{}
+		Set      bool
+	}
+
+	testCases := []TestCase{
+		{Name: "Error", Policy: OverflowError, Input: new(int8), String: "300", Expected: int8(0), Set: false},
+		{Name: "SaturateInt", Policy: OverflowSaturate, Input: new(int8), String: "300", Expected: int8(127), Set: true},
+		{Name: "SaturateNegativeInt", Policy: OverflowSaturate, Input: new(int8), String: "-300", Expected: int8(-128), Set: true},
+		{Name: "SaturateUint", Policy: OverflowSaturate, Input: new(uint16), String: "70000", Expected: uint16(65535), Set: true},
+		{Name: "SaturateFloat", Policy: OverflowSaturate, Input: new(float32), String: "1e39", Expected: float32(math.Inf(1)), Set: true},
+		{Name: "WrapInt", Policy: OverflowWrap, Input: new(int8), String: "200", Expected: int8(-56), Set: true},
+		{Name: "WrapNegativeInt", Policy: OverflowWrap, Input: new(int8), String: "-130", Expected: int8(126), Set: true},
+		{Name: "WrapUint", Policy: OverflowWrap, Input: new(uint16), String: "70000", Expected: uint16(4464), Set: true},
+		{Name: "WrapFloat", Policy: OverflowWrap, Input: new(float32), String: "-1e39", Expected: float32(math.Inf(-1)), Set: true},
+		{Name: "NotRange", Policy: OverflowSaturate, Input: new(int8), String: "a", Expected: int8(0), Set: false},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			val := reflect.ValueOf(tc.Input).Elem()
+			err := setField(val, tc.String)
+			assert.Error(t, err)
+			assert.EqualValues(t, tc.Set, overflow(val, err, tc.Policy))
+			assert.EqualValues(t, tc.Expected, val.Interface())
+		})
+	}
+}
+
+func TestWithOverflowPolicy(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		Expected Int
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name: "Error",
+			Error: &FieldError{"Value", "test", "18446744073709551617",
+				&OutOfRange{reflect.TypeOf(0), "18446744073709551617", &strconv.NumError{Func: "ParseInt", Num: "18446744073709551617", Err: strconv.ErrRange}}},
+		},
+		{
+			Name:     "Saturate",
+			Options:  []Option{WithOverflowPolicy(OverflowSaturate)},
+			Expected: Int{Value: math.MaxInt},
+		},
+		{
+			Name:     "Wrap",
+			Options:  []Option{WithOverflowPolicy(OverflowWrap)},
+			Expected: Int{Value: 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := MustCompile[Int](tc.Options...).Parse("18446744073709551617")
+			assert.EqualValues(t, tc.Expected, v)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestWithOverflowPolicySplit(t *testing.T) {
+	type Int8Samples struct {
+		StructExp `structexp:"^{{samples}}$"`
+		Samples   []int8 `structexp.name:"samples" structexp.split:","`
+	}
+
+	type TestCase struct {
+		Name     string
+		Policy   OverflowPolicy
+		Expected []int8
+		Warnings []error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Saturate",
+			Policy:   OverflowSaturate,
+			Expected: []int8{1, 127, -128},
+			Warnings: []error{
+				&Warning{"Samples", "samples", "300 is out of range, saturated to 127"},
+				&Warning{"Samples", "samples", "-130 is out of range, saturated to -128"},
+			},
+		},
+		{
+			Name:     "Wrap",
+			Policy:   OverflowWrap,
+			Expected: []int8{1, 44, 126},
+			Warnings: []error{
+				&Warning{"Samples", "samples", "300 is out of range, wrapped to 44"},
+				&Warning{"Samples", "samples", "-130 is out of range, wrapped to 126"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			r := MustCompile[Int8Samples](WithOverflowPolicy(tc.Policy)).ParseResult("1,300,-130")
+			assert.NoError(t, r.Err)
+			assert.EqualValues(t, tc.Expected, r.Value.Samples)
+			assert.EqualValues(t, tc.Warnings, r.Warnings)
+		})
+	}
+
+	_, err := MustCompile[Int8Samples]().Parse("1,300,-130")
+	assert.ErrorAs(t, err, new(*OutOfRange))
+}
@@ -0,0 +1,138 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Sizes of the chunks of the input parsed by the workers of ParseAll
+const (
+	// Chunks per worker, so that workers finishing early take more
+	chunksPerWorker = 4
+	// Minimum bytes per chunk, below which ParseAll is not worth splitting
+	minChunkSize = 16 << 10
+)
+
+// Workers parses the input of the Parser's ParseAll and ParseAllContext on n
+// goroutines, for big batch inputs whose matching and conversion would
+// otherwise run on one. The input is split into chunks at line boundaries,
+// parsed independently, and their values are joined in input order. Matches
+// must not span lines, as they would be cut at the boundary of a chunk.
+// If parsing fails, the error of the first failing match in the input is
+// returned, as without workers. Inputs too small to split, or any input if
+// n is less than 2, are parsed on the calling goroutine.
+func Workers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// parseAllParallel parses every match in the string on the Parser's workers, like ParseAllContext
+func (p *Parser[T]) parseAllParallel(ctx context.Context, s string) ([]T, error) {
+	n := p.opts.workers * chunksPerWorker
+	if max := len(s) / minChunkSize; n > max {
+		n = max
+	}
+	chunks := splitLines(s, n)
+	if len(chunks) < 2 {
+		return p.parseChunk(ctx, s)
+	}
+
+	results := make([][]T, len(chunks))
+	errs := make([]error, len(chunks))
+	// Chunks after a failed chunk are not parsed, their values being discarded
+	var mu sync.Mutex
+	failed := len(chunks)
+	skip := func(i int) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return i > failed
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < p.opts.workers && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if skip(i) {
+					continue
+				}
+				if results[i], errs[i] = p.parseChunk(ctx, chunks[i]); errs[i] != nil {
+					mu.Lock()
+					if i < failed {
+						failed = i
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+feed:
+	for i := range chunks {
+		if skip(i) {
+			break
+		}
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	total := 0
+	for _, values := range results {
+		total += len(values)
+	}
+	v := make([]T, 0, total)
+	for _, values := range results {
+		v = append(v, values...)
+	}
+	return v, nil
+}
+
+// parseChunk parses every match in the chunk of the input
+func (p *Parser[T]) parseChunk(ctx context.Context, s string) ([]T, error) {
+	var v []T
+	err := p.parseAll(ctx, reflect.ValueOf(&v).Elem(), s)
+	return v, err
+}
+
+// splitLines splits the string into at most n chunks of about the same size,
+// each ending with a newline, except the last. A line longer than a chunk
+// is not split, leaving fewer chunks.
+func splitLines(s string, n int) []string {
+	if n < 2 {
+		return []string{s}
+	}
+	size := len(s) / n
+	chunks := make([]string, 0, n)
+	for len(s) > 0 {
+		if len(s) <= size {
+			chunks = append(chunks, s)
+			break
+		}
+		end := strings.IndexByte(s[size:], '\n')
+		if end == -1 {
+			chunks = append(chunks, s)
+			break
+		}
+		end += size + 1
+		chunks = append(chunks, s[:end])
+		s = s[end:]
+	}
+	return chunks
+}
@@ -0,0 +1,83 @@
+package structexp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CounterStruct struct {
+	StructExp `structexp:"^{{name}}={{count}}$"`
+	Name      string `structexp.name:"name" structexp.exp:"[a-z]+[0-9]*"`
+	Count     int    `structexp.name:"count" structexp.exp:"[0-9]+"`
+}
+
+// counterLines returns n lines of counters, the one at overflow exceeding an int
+func counterLines(n, overflow int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i == overflow {
+			fmt.Fprintf(&b, "c%d=99999999999999999999\n", i)
+			continue
+		}
+		fmt.Fprintf(&b, "c%d=%d\n", i, i)
+	}
+	return b.String()
+}
+
+func TestWorkers(t *testing.T) {
+	serial := MustCompile[CounterStruct]()
+	parallel := MustCompile[CounterStruct](Workers(4))
+
+	// Split into chunks, the values are in input order
+	input := counterLines(20000, -1)
+	want, err := serial.ParseAll(input)
+	require.NoError(t, err)
+	require.Len(t, want, 20000)
+	values, err := parallel.ParseAll(input)
+	require.NoError(t, err)
+	assert.Equal(t, want, values)
+
+	// Too small to split
+	values, err = parallel.ParseAll("a=1\nb=2\n")
+	require.NoError(t, err)
+	assert.Equal(t, []CounterStruct{{Name: "a", Count: 1}, {Name: "b", Count: 2}}, values)
+
+	// The error is that of the first failing match
+	input = counterLines(20000, 12345) + "c=99999999999999999999\n"
+	_, wantErr := serial.ParseAll(input)
+	require.Error(t, wantErr)
+	values, err = parallel.ParseAll(input)
+	assert.Nil(t, values)
+	assert.EqualValues(t, wantErr, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = parallel.ParseAllContext(ctx, counterLines(20000, -1))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSplitLines(t *testing.T) {
+	assert.Equal(t, []string{"a\nb\n"}, splitLines("a\nb\n", 1))
+	assert.Equal(t, []string{"a\n", "b\n", "c"}, splitLines("a\nb\nc", 3))
+	assert.Equal(t, []string{"aaaa\n", "b\nc\n"}, splitLines("aaaa\nb\nc\n", 3))
+	assert.Equal(t, []string{"aaaaaaaa"}, splitLines("aaaaaaaa", 4))
+}
+
+func BenchmarkParserParseAllWorkers(b *testing.B) {
+	input := counterLines(100000, -1)
+	for _, workers := range []int{1, 4} {
+		parser := MustCompile[CounterStruct](Workers(workers))
+		b.Run(fmt.Sprint(workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := parser.ParseAll(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Parser parses strings into the struct type T. The reflection over T and
+// the compilation of its regular expression are done once by Compile, with
+// its options. Parse and ParseAll also compile each struct type once, caching
+// it until a registration, such as of a sub-parser, but take no options.
+//
+// A Parser is safe for concurrent use by multiple goroutines.
+type Parser[T any] struct {
+	*typeParser
+}
+
+// typeParser parses strings into a struct type known at runtime. It is the
+// Parser of a type, without the type, for the package functions taking the
+// address of a struct, which share the typeParser compiled without options.
+type typeParser struct {
+	regexp   *regexp.Regexp
+	all      *regexp.Regexp
+	search   *searcher
+	prefix   *regexp.Regexp
+	fields   []*field
+	warnings []error
+	opts     *options
+	stats    *parserStats
+
+	// The fields whose capture is scanned, of all fields, even if projected
+	scanned []*field
+
+	// Parsers of the other templates of the type, tried in order
+	alternates []*typeParser
+}
+
+// Compile builds the regular expression for the struct type T
+// and returns a Parser reusing it for every call.
+//
+// T may have several templates, such as for the versions of a format, in
+// several StructExp fields or registered with RegisterPattern. Parse, ParseAll,
+// and the methods parsing one record at a time with them, try them in order,
+// while the other methods, such as All and Format, only use the first template.
+//
+// Errors occur if:
+//  - T is not a struct
+//  - T is missing a StructExp field, without the WithTemplate option
+//  - no variant is registered by the key given to WithVariant, or it replaces an unknown capture group
+//  - two fields have the same capture group name
+//  - a delimiter given to WithDelimiters is empty
+//  - an include fails to load, with the WithLoader option
+//  - a placeholder in the template has no field
+//  - the regular expression fails to compile
+//  - a field's structexp.index tag is not the position of an unnamed capture group
+//  - a field has no capture group in the regular expression, with the Strict option
+//  - a path given to Projection names no field
+//  - the regular expression exceeds a complexity limit option, such as MaxProgramSize
+func Compile[T any](opts ...Option) (*Parser[T], error) {
+	p, err := compileParser(reflect.TypeOf((*T)(nil)).Elem(), newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	return &Parser[T]{p}, nil
+}
+
+// compileParser compiles the typeParser of the struct type with
+// the options, with an alternate for each of its other templates
+func compileParser(t reflect.Type, o *options) (*typeParser, error) {
+	if kind := t.Kind(); kind != reflect.Struct {
+		return nil, &NotStruct{kind}
+	}
+
+	var variant Variant
+	if o.variant != "" {
+		var err error
+		if variant, err = lookupVariant(t, o.variant); err != nil {
+			return nil, err
+		}
+	}
+
+	templates := []string{o.template}
+	if o.template == "" && variant.Template != "" {
+		templates = []string{variant.Template}
+	}
+	if templates[0] == "" {
+		bases, err := regexpBases(t)
+		if err != nil {
+			return nil, err
+		}
+		templates = bases
+	}
+	placeholders, includes := placeholderRegexp, includeRegexp
+	if o.delimiters != nil {
+		var err error
+		if placeholders, includes, err = delimitedRegexps(o.delimiters[0], o.delimiters[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	var p *typeParser
+	for _, template := range templates {
+		candidate, err := compileCandidate(t, template, variant, placeholders, includes, o)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			p = candidate
+			continue
+		}
+		candidate.stats = p.stats
+		p.alternates = append(p.alternates, candidate)
+	}
+	p.warn(p.warnings)
+	return p, nil
+}
+
+// compileCandidate compiles the typeParser of one of the templates of the struct type
+func compileCandidate(t reflect.Type, template string, variant Variant, placeholders, includes *regexp.Regexp, o *options) (*typeParser, error) {
+	if o.loader != nil {
+		expanded, err := expandIncludes(template, o.loader, includes, nil)
+		if err != nil {
+			return nil, err
+		}
+		template = expanded
+	}
+	template = o.optionFlags() + template
+	if o.fullMatch {
+		template = `\A(?:` + template + `)\z`
+	}
+	fields, err := listFields(t, nil, nil, o.registry)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDuplicateGroups(t, fields); err != nil {
+		return nil, err
+	}
+	if err := checkRawGroups(t, fields); err != nil {
+		return nil, err
+	}
+	if err := variant.apply(fields); err != nil {
+		return nil, err
+	}
+	if o.overflow != OverflowError {
+		setOverflow(fields, o.overflow)
+	}
+	if o.strict {
+		setStrict(fields)
+	}
+	if o.joinFieldErrors {
+		setJoinErrors(fields)
+	}
+	if o.unicodeDefaults {
+		setUnicodeDefaults(fields)
+	}
+	regxp, err := fillRegexp(template, fields, placeholders)
+	if err != nil {
+		return nil, err
+	}
+	if regxp, err = nameIndexedGroups(t, regxp, fields); err != nil {
+		return nil, err
+	}
+	planFields(regxp, fields)
+	if o.strict {
+		if err := checkPlaced(t, regxp, fields); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkComplexity(regxp, o); err != nil {
+		return nil, err
+	}
+	size, err := programSize(regxp)
+	if err != nil {
+		return nil, err
+	}
+	// Anchors would prevent finding more than one match
+	all, err := unanchored(regxp)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := prefixRegexp(all)
+	if err != nil {
+		return nil, err
+	}
+
+	scanned := scannedFields(fields)
+	if o.projection != nil {
+		if fields, err = projectFields(t, fields, o.projection); err != nil {
+			return nil, err
+		}
+	}
+
+	return &typeParser{
+		regexp:   regxp,
+		all:      all,
+		search:   newSearcher(all),
+		prefix:   prefix,
+		fields:   fields,
+		warnings: definitionWarnings(t, fields),
+		scanned:  scanned,
+		opts:     o,
+		stats:    &parserStats{hook: o.metricsHook, programSize: size},
+	}, nil
+}
+
+// MustCompile is like Compile but panics if the Parser cannot be compiled.
+// It simplifies safe initialization of global variables holding Parsers,
+// so that struct definition errors surface at startup.
+func MustCompile[T any](opts ...Option) *Parser[T] {
+	p, err := Compile[T](opts...)
+	if err != nil {
+		panic(fmt.Sprintf("structexp: Compile[%v]: %v", reflect.TypeOf((*T)(nil)).Elem(), err))
+	}
+	return p
+}
+
+// Regexp returns the regular expression built for T
+func (p *Parser[T]) Regexp() *regexp.Regexp {
+	return p.regexp
+}
+
+// Match reports whether the string matches the regular expression of any
+// template of T, like Match. Strings exceeding the MaxInputSize option do not match.
+func (p *Parser[T]) Match(s string) bool {
+	s = p.input(s)
+	if checkInputSize(len(s), p.opts) != nil {
+		return false
+	}
+	return p.match(s)
+}
+
+// match reports whether the regular expression of any template matches the string
+func (p *typeParser) match(s string) bool {
+	if p.regexp.MatchString(s) {
+		return true
+	}
+	for _, alternate := range p.alternates {
+		if alternate.regexp.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse returns a new T parsed from the string, like ParseAs,
+// or unmarshaled from it if it is a JSON object and the
+// SniffJSON option is given. If T has several templates, they
+// are tried in order, parsing with the first that matches.
+//
+// Errors occur if:
+//  - the string exceeds the MaxInputSize option
+//  - regular expression does not match the string
+//  - any field fails to parse, or the JSON fails to unmarshal
+func (p *Parser[T]) Parse(s string) (T, error) {
+	var v T
+	err := p.parse(reflect.ValueOf(&v).Elem(), s)
+	return v, err
+}
+
+// parse sets the struct value from the string, like Parser.Parse
+func (p *typeParser) parse(v reflect.Value, s string) error {
+	s = p.input(s)
+	if err := checkInputSize(len(s), p.opts); err != nil {
+		return err
+	}
+	start := p.stats.start()
+	if p.opts.sniffJSON && isJSONObject(s) {
+		err := json.Unmarshal([]byte(s), v.Addr().Interface())
+		p.stats.observe(start, err)
+		return err
+	}
+	c, _, loc := p.candidate(s)
+	err := setStructMatchIndex(v, c.regexp, c.fields, s, loc)
+	p.stats.observe(start, err)
+	if err == nil && p.opts.warningHook != nil {
+		p.warn(matchWarnings(v.Type(), c.regexp, c.fields, s, loc))
+	}
+	return err
+}
+
+// candidate returns the typeParser of the first template whose regular
+// expression matches the string, its index, and the location of the match,
+// as from FindStringSubmatchIndex. If none match, it returns the typeParser
+// itself and a nil location. Each expression is matched once.
+func (p *typeParser) candidate(s string) (*typeParser, int, []int) {
+	if loc := findSubmatchIndex(p.regexp, p.scanned, s); loc != nil || len(p.alternates) == 0 {
+		return p, 0, loc
+	}
+	for i, alternate := range p.alternates {
+		if loc := findSubmatchIndex(alternate.regexp, alternate.scanned, s); loc != nil {
+			return alternate, i + 1, loc
+		}
+	}
+	return p, 0, nil
+}
+
+// ParseAll returns a new T parsed from every match in the string, like ParseAllAs.
+//
+// Errors occur if the string exceeds the MaxInputSize
+// option, or any match's fields fail to parse.
+func (p *Parser[T]) ParseAll(s string) ([]T, error) {
+	return p.ParseAllContext(context.Background(), s)
+}
+
+// ParseAllContext is like ParseAll, but stops parsing matches when
+// the context is done, returning its error, like ParseAllContext
+func (p *Parser[T]) ParseAllContext(ctx context.Context, s string) ([]T, error) {
+	var v []T
+	s = p.input(s)
+	if err := checkInputSize(len(s), p.opts); err != nil {
+		return v, err
+	}
+	start := p.stats.start()
+	var err error
+	if p.opts.workers > 1 {
+		v, err = p.parseAllParallel(ctx, s)
+	} else {
+		err = p.parseAll(ctx, reflect.ValueOf(&v).Elem(), s)
+	}
+	p.stats.observe(start, err)
+	return v, err
+}
+
+// parseAll fills the slice with a new element for every match in the
+// string, like ParseAllContext. With several templates, each match is
+// parsed with the template it matched, as found by findAll.
+func (p *typeParser) parseAll(ctx context.Context, slice reflect.Value, s string) error {
+	if len(p.alternates) == 0 {
+		return setStructSliceMatches(ctx, slice, p.all, p.fields, s)
+	}
+	candidates, locs := p.findAll(s)
+	elems := reflect.MakeSlice(slice.Type(), len(locs), len(locs))
+	for i, loc := range locs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := setFields(elems.Index(i), candidates[i].all, candidates[i].fields, submatches(s, loc)); err != nil {
+			return err
+		}
+	}
+	slice.Set(elems)
+	return nil
+}
+
+// findAll returns the location of every match in the string of the
+// unanchored regular expressions of the templates, like
+// FindAllStringSubmatchIndex, and the typeParser of each match's template.
+// At each position, the earliest match is taken, and of matches
+// starting at the same byte, that of the earliest template.
+func (p *typeParser) findAll(s string) ([]*typeParser, [][]int) {
+	var candidates []*typeParser
+	var locs [][]int
+	// Follows the search of regexp's FindAll methods from each match's end
+	for pos, prevEnd := 0, -1; pos <= len(s); {
+		c, loc := p, p.search.findScanned(p.scanned, s, pos)
+		for _, alternate := range p.alternates {
+			if l := alternate.search.findScanned(alternate.scanned, s, pos); l != nil && (loc == nil || l[0] < loc[0]) {
+				c, loc = alternate, l
+			}
+		}
+		if loc == nil {
+			break
+		}
+		var accept bool
+		if pos, prevEnd, accept = nextSearch(s, pos, prevEnd, loc); accept {
+			candidates = append(candidates, c)
+			locs = append(locs, loc)
+		}
+	}
+	return candidates, locs
+}
+
+// nextSearch follows the search of regexp's FindAll methods from the match at
+// the location, found searching the string from the position, returning the
+// position to search from next, the end of the match, and whether the match
+// is accepted. Empty matches directly after the previous match are skipped.
+func nextSearch(s string, pos, prevEnd int, loc []int) (int, int, bool) {
+	start, end := loc[0], loc[1]
+	if end != pos {
+		return end, end, true
+	}
+	if pos < len(s) {
+		_, width := utf8.DecodeRuneInString(s[pos:])
+		return pos + width, end, start != prevEnd
+	}
+	return pos + 1, end, start != prevEnd
+}
+
+// Format renders the value back into a string, like Format
+func (p *Parser[T]) Format(v T) (string, error) {
+	return format(reflect.ValueOf(v), p.regexp, p.fields)
+}
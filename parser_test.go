@@ -0,0 +1,97 @@
+package structexp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	parser, err := Compile[ParentHeaderStruct]()
+	require.NoError(t, err)
+
+	value, err := parser.Parse("[WARN: 12] 34")
+	require.NoError(t, err)
+	assert.EqualValues(t, ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}, Code: 34}, value)
+
+	assert.Equal(t, `^\[(?P<Header>[A-Z]+: [0-9]+)\] (?P<code>[[:digit:]]+)$`, parser.Regexp().String())
+
+	s, err := parser.Format(value)
+	require.NoError(t, err)
+	assert.Equal(t, "[WARN: 12] 34", s)
+
+	_, err = parser.Parse("WARN: 12")
+	assert.EqualValues(t, noMatch[ParentHeaderStruct]("WARN: 12"), err)
+
+	_, err = Compile[int]()
+	assert.EqualValues(t, &NotStruct{reflect.Int}, err)
+
+	_, err = Compile[MissingFieldStruct]()
+	assert.EqualValues(t, &MissingField{}, err)
+
+	_, err = Compile[Header](WithTemplate(`^{{level}}: {{cod}} {{msg}} {{cod}}$`))
+	assert.EqualValues(t, &UnknownPlaceholder{[]string{"cod", "msg"}}, err)
+
+	_, err = Compile[DuplicateGroupStruct]()
+	assert.EqualValues(t, &DuplicateGroup{"code", [2]string{"Code", "Nested.Status"}}, err)
+}
+
+func TestParserParseAll(t *testing.T) {
+	parser, err := Compile[Entry]()
+	require.NoError(t, err)
+
+	entries, err := parser.ParseAll("a=1;b=2;")
+	require.NoError(t, err)
+	assert.EqualValues(t, []Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, entries)
+}
+
+func TestMustCompile(t *testing.T) {
+	assert.NotPanics(t, func() { MustCompile[Int]() })
+	assert.PanicsWithValue(t, "structexp: Compile[int]: object to parse is not struct, is int", func() {
+		MustCompile[int]()
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var value ParentHeaderStruct
+		if err := Parse("[WARN: 12] 34", &value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserParse(b *testing.B) {
+	parser, err := Compile[ParentHeaderStruct]()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Parse("[WARN: 12] 34"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParserMatch(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct](MaxInputSize(16))
+	assert.True(t, parser.Match("[WARN: 12] 34"))
+	assert.False(t, parser.Match("[WARN] 34"))
+	assert.False(t, parser.Match("[WARN: 12] 34567890"))
+}
+
+func TestParserParseAllContext(t *testing.T) {
+	parser := MustCompile[Entry]()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	values, err := parser.ParseAllContext(ctx, "a=b; c=d;")
+	require.NoError(t, err)
+	assert.Len(t, values, 2)
+
+	cancel()
+	_, err = parser.ParseAllContext(ctx, "a=b; c=d;")
+	assert.Equal(t, context.Canceled, err)
+}
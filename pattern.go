@@ -0,0 +1,52 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Patterner is implemented by a struct type that defines its template with a
+// method, instead of the structexp tag of a StructExp field, so that a long
+// template can be written across lines or built programmatically. Pattern is
+// called on the zero value of the struct type, or of a pointer to it.
+type Patterner interface {
+	Pattern() string
+}
+
+var patterns = struct {
+	mu    sync.RWMutex
+	types map[reflect.Type][]string
+}{types: map[reflect.Type][]string{}}
+
+// RegisterPattern registers the templates of the struct type T, for struct
+// types that cannot have a StructExp field or Pattern method, such as those
+// of another package. Several templates, such as those of the versions of a
+// format, are tried in order when parsing, like several StructExp fields.
+// Registering patterns again replaces them, and registering none removes them.
+// Patterns are shared by every Parser, whatever its Registry.
+func RegisterPattern[T any](templates ...string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	patterns.mu.Lock()
+	defer patterns.mu.Unlock()
+	defer invalidateCompiledTypes()
+	if len(templates) == 0 {
+		delete(patterns.types, t)
+		return
+	}
+	patterns.types[t] = append([]string(nil), templates...)
+}
+
+// lookupPatterns returns the templates of the struct type registered with
+// RegisterPattern, or returned by its Pattern method
+func lookupPatterns(t reflect.Type) ([]string, bool) {
+	patterns.mu.RLock()
+	templates, ok := patterns.types[t]
+	patterns.mu.RUnlock()
+	if ok {
+		return templates, true
+	}
+	if patterner, ok := reflect.New(t).Interface().(Patterner); ok {
+		return []string{patterner.Pattern()}, true
+	}
+	return nil, false
+}
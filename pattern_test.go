@@ -0,0 +1,249 @@
+package structexp
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PatternStruct struct {
+	Level string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code  int    `structexp.name:"code"`
+}
+
+func (PatternStruct) Pattern() string {
+	return strings.Join([]string{
+		`^{{level}}`,
+		`code={{code}}$`,
+	}, " ")
+}
+
+type PatternPtrStruct struct {
+	Code int `structexp.name:"code"`
+}
+
+func (*PatternPtrStruct) Pattern() string {
+	return `^#{{code}}$`
+}
+
+type RegisteredPatternStruct struct {
+	Level string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code  int    `structexp.name:"code"`
+}
+
+type PatternParentStruct struct {
+	StructExp `structexp:"^{{Name}} \\({{Child}}\\)$"`
+	Name      string `structexp.exp:"[a-z]+"`
+	Child     PatternPtrStruct
+}
+
+func init() {
+	RegisterPattern[RegisteredPatternStruct](`^{{code}}/{{level}}$`)
+	RegisterPattern[RegisteredVersionedStruct](`^{{level}}/{{code}}$`, `^{{code}} {{level}}$`)
+}
+
+func TestPattern(t *testing.T) {
+	type TestCase struct {
+		Name   string
+		Input  string
+		Value  interface{}
+		Result interface{}
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "method",
+			Input:  "WARN code=12",
+			Value:  &PatternStruct{},
+			Result: &PatternStruct{Level: "WARN", Code: 12},
+		},
+		{
+			Name:   "pointer method",
+			Input:  "#7",
+			Value:  &PatternPtrStruct{},
+			Result: &PatternPtrStruct{Code: 7},
+		},
+		{
+			Name:   "registered",
+			Input:  "3/INFO",
+			Value:  &RegisteredPatternStruct{},
+			Result: &RegisteredPatternStruct{Level: "INFO", Code: 3},
+		},
+		{
+			Name:   "nested",
+			Input:  "abc (#5)",
+			Value:  &PatternParentStruct{},
+			Result: &PatternParentStruct{Name: "abc", Child: PatternPtrStruct{Code: 5}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			require.NoError(t, Parse(tc.Input, tc.Value))
+			assert.EqualValues(t, tc.Result, tc.Value)
+			assert.NoError(t, Validate(tc.Value))
+		})
+	}
+}
+
+func TestPatternCompile(t *testing.T) {
+	parser, err := Compile[PatternStruct]()
+	require.NoError(t, err)
+	assert.Equal(t, `^(?P<level>[A-Z]+) code=(?P<code>[[:digit:]]+)$`, parser.Regexp().String())
+}
+
+type VersionedStruct struct {
+	V2    StructExp `structexp:"^v2 {{level}} {{code}} {{msg}}$"`
+	V1    StructExp `structexp:"^{{level}}: {{code}}$"`
+	Level string    `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code  int       `structexp.name:"code"`
+	Msg   string    `structexp.name:"msg" structexp.optional:"true"`
+}
+
+type RegisteredVersionedStruct struct {
+	Level string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code  int    `structexp.name:"code"`
+}
+
+func TestMultiplePatterns(t *testing.T) {
+	type TestCase struct {
+		Name         string
+		Input        string
+		Value        VersionedStruct
+		PatternIndex int
+		Error        error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:         "First",
+			Input:        "v2 WARN 12 disk full",
+			Value:        VersionedStruct{Level: "WARN", Code: 12, Msg: "disk full"},
+			PatternIndex: 0,
+		},
+		{
+			Name:         "Second",
+			Input:        "WARN: 12",
+			Value:        VersionedStruct{Level: "WARN", Code: 12},
+			PatternIndex: 1,
+		},
+		{
+			Name:  "NoMatch",
+			Input: "warn 12",
+			Error: noMatch[VersionedStruct]("warn 12"),
+		},
+	}
+
+	parser, err := Compile[VersionedStruct]()
+	require.NoError(t, err)
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			value, err := parser.Parse(tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+			assert.EqualValues(t, tc.Value, value)
+
+			result := parser.ParseResult(tc.Input)
+			assert.EqualValues(t, tc.Error, result.Err)
+			assert.Equal(t, tc.PatternIndex, result.Provenance.PatternIndex)
+		})
+	}
+}
+
+func TestMultiplePatternsRegistered(t *testing.T) {
+	parser, err := Compile[RegisteredVersionedStruct]()
+	require.NoError(t, err)
+
+	value, err := parser.Parse("3 INFO")
+	require.NoError(t, err)
+	assert.EqualValues(t, RegisteredVersionedStruct{Level: "INFO", Code: 3}, value)
+
+	projection, err := parser.Project("Code")
+	require.NoError(t, err)
+	value, err = projection.Parse("3 INFO")
+	require.NoError(t, err)
+	assert.EqualValues(t, RegisteredVersionedStruct{Code: 3}, value)
+}
+
+func TestMultiplePatternsValidate(t *testing.T) {
+	assert.NoError(t, Validate(VersionedStruct{}))
+}
+
+type LetterOrNumber struct {
+	Letter StructExp `structexp:"\\b{{w}}"`
+	Number StructExp `structexp:"#{{n}}"`
+	W      string    `structexp.name:"w" structexp.exp:"[a-z]"`
+	N      int       `structexp.name:"n"`
+}
+
+func TestMultiplePatternsWordBoundary(t *testing.T) {
+	// Each template is searched with the text before the search as context
+	expected := []LetterOrNumber{{W: "a"}, {N: 1}, {W: "c"}}
+	var all []LetterOrNumber
+	require.NoError(t, ParseAll("ab #1 cd", &all))
+	assert.EqualValues(t, expected, all)
+
+	all, err := MustCompile[LetterOrNumber]().ParseAll("ab #1 cd")
+	require.NoError(t, err)
+	assert.EqualValues(t, expected, all)
+}
+
+func TestMultiplePatternsEntryPoints(t *testing.T) {
+	first := VersionedStruct{Level: "WARN", Code: 12, Msg: "disk full"}
+	second := VersionedStruct{Level: "INFO", Code: 3}
+	parser := MustCompile[VersionedStruct]()
+
+	var value VersionedStruct
+	require.NoError(t, Parse("INFO: 3", &value))
+	assert.EqualValues(t, second, value)
+	assert.EqualValues(t, noMatch[VersionedStruct]("info 3"), Parse("info 3", &value))
+
+	value = VersionedStruct{}
+	require.NoError(t, ParseBytes([]byte("INFO: 3"), &value))
+	assert.EqualValues(t, second, value)
+	value, err := parser.ParseBytes([]byte("INFO: 3"))
+	require.NoError(t, err)
+	assert.EqualValues(t, second, value)
+	value = VersionedStruct{}
+	require.NoError(t, parser.ParseBytesInto([]byte("INFO: 3"), &value))
+	assert.EqualValues(t, second, value)
+	assert.EqualValues(t, noMatch[VersionedStruct]("info 3"), parser.ParseBytesInto([]byte("info 3"), &value))
+
+	assert.True(t, Match("INFO: 3", VersionedStruct{}))
+	assert.True(t, parser.Match("INFO: 3"))
+	assert.False(t, Match("info 3", VersionedStruct{}))
+	assert.False(t, parser.Match("info 3"))
+
+	decoder := NewDecoder(strings.NewReader("v2 WARN 12 disk full\nINFO: 3\n"))
+	var decoded []VersionedStruct
+	for {
+		var v VersionedStruct
+		if err := decoder.Decode(&v); err != nil {
+			require.Equal(t, io.EOF, err)
+			break
+		}
+		decoded = append(decoded, v)
+	}
+	assert.EqualValues(t, []VersionedStruct{first, second}, decoded)
+
+	var lines []VersionedStruct
+	require.NoError(t, ParseLines("v2 WARN 12 disk full\nINFO: 3", &lines))
+	assert.EqualValues(t, []VersionedStruct{first, second}, lines)
+
+	// Each match is parsed with the template it matched, in input order
+	const both = "INFO: 3\nv2 WARN 12 disk full\nINFO: 3"
+	var all []VersionedStruct
+	require.NoError(t, ParseAll(both, &all))
+	assert.EqualValues(t, []VersionedStruct{second, first, second}, all)
+	all, err = parser.ParseAll(both)
+	require.NoError(t, err)
+	assert.EqualValues(t, []VersionedStruct{second, first, second}, all)
+	all, err = MustCompile[VersionedStruct](Workers(2)).ParseAll(both)
+	require.NoError(t, err)
+	assert.EqualValues(t, []VersionedStruct{second, first, second}, all)
+}
@@ -0,0 +1,148 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/densestvoid/structexp/convert"
+)
+
+// planFields resolves the index of every field's capture group in the compiled
+// regular expression, so that setting the fields from its submatches, or those
+// of a regular expression with the same groups, does not look them up by name
+func planFields(regxp *regexp.Regexp, fields []*field) {
+	names := regxp.SubexpNames()
+	for _, f := range fields {
+		f.Group = subexpIndex(names, f.group())
+		f.Planned = names
+	}
+}
+
+// plannedFor reports whether the field's capture group was planned for the
+// group names, which are the same slice for every call of SubexpNames
+func (f *field) plannedFor(names []string) bool {
+	return len(f.Planned) > 0 && len(f.Planned) == len(names) && &f.Planned[0] == &names[0]
+}
+
+// groupIndex returns the index of the field's capture group among the group names,
+// as planned if they are those of the regular expression the field was compiled into
+func (f *field) groupIndex(names []string) int {
+	if f.plannedFor(names) {
+		return f.Group
+	}
+	return subexpIndex(names, f.group())
+}
+
+// converter sets values of its type from strings. Its function is chosen once
+// from the type, so that setting a value does not reflect over the type again.
+// Values of the basic kinds are also set from byte slices, without allocating.
+type converter struct {
+	Type     reflect.Type
+	Set      func(val reflect.Value, s string) error
+	SetBytes func(val reflect.Value, b []byte) error
+}
+
+// newConverter returns the converter of the type, setting the values of the
+// basic kinds directly, and any other value like setField
+func newConverter(t reflect.Type) converter {
+	c := converter{Type: t, Set: setField}
+	if convert.Implements(t) {
+		return c
+	}
+
+	// nolint:exhaustive // unnecessary
+	switch t.Kind() {
+	case reflect.String:
+		c.Set = settable(func(val reflect.Value, s string) error {
+			val.SetString(s)
+			return nil
+		})
+		c.SetBytes = settable(func(val reflect.Value, b []byte) error {
+			val.SetString(string(b))
+			return nil
+		})
+	case reflect.Bool:
+		c.Set = settable(func(val reflect.Value, s string) error {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			val.SetBool(b)
+			return nil
+		})
+		c.SetBytes = settable(func(val reflect.Value, b []byte) error {
+			v, err := strconv.ParseBool(string(b))
+			if err != nil {
+				return err
+			}
+			val.SetBool(v)
+			return nil
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64:
+		bits := t.Bits()
+		c.Set = settable(func(val reflect.Value, s string) error {
+			i, err := strconv.ParseInt(s, 10, bits)
+			if err != nil {
+				return err
+			}
+			val.SetInt(i)
+			return nil
+		})
+		c.SetBytes = settable(func(val reflect.Value, b []byte) error {
+			i, err := strconv.ParseInt(string(b), 10, bits)
+			if err != nil {
+				return err
+			}
+			val.SetInt(i)
+			return nil
+		})
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		bits := t.Bits()
+		c.Set = settable(func(val reflect.Value, s string) error {
+			u, err := strconv.ParseUint(s, 10, bits)
+			if err != nil {
+				return err
+			}
+			val.SetUint(u)
+			return nil
+		})
+		c.SetBytes = settable(func(val reflect.Value, b []byte) error {
+			u, err := strconv.ParseUint(string(b), 10, bits)
+			if err != nil {
+				return err
+			}
+			val.SetUint(u)
+			return nil
+		})
+	case reflect.Float32, reflect.Float64:
+		bits := t.Bits()
+		c.Set = settable(func(val reflect.Value, s string) error {
+			f, err := strconv.ParseFloat(s, bits)
+			if err != nil {
+				return err
+			}
+			val.SetFloat(f)
+			return nil
+		})
+		c.SetBytes = settable(func(val reflect.Value, b []byte) error {
+			f, err := strconv.ParseFloat(string(b), bits)
+			if err != nil {
+				return err
+			}
+			val.SetFloat(f)
+			return nil
+		})
+	}
+	return c
+}
+
+// settable wraps the setter of a value, checking the value is settable like setField
+func settable[S string | []byte](set func(val reflect.Value, s S) error) func(val reflect.Value, s S) error {
+	return func(val reflect.Value, s S) error {
+		if !val.CanSet() {
+			return &InvalidType{val.Type()}
+		}
+		return set(val, s)
+	}
+}
@@ -0,0 +1,119 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanFields(t *testing.T) {
+	parser, err := Compile[ParentHeaderStruct]()
+	require.NoError(t, err)
+
+	names := parser.Regexp().SubexpNames()
+	for _, f := range parser.fields {
+		assert.Equal(t, subexpIndex(names, f.group()), f.groupIndex(names), f.CaptureGroupName)
+	}
+
+	// The names of a regular expression with other groups are looked up,
+	// even if it has as many groups
+	f := parser.fields[len(parser.fields)-1]
+	require.True(t, f.plannedFor(names))
+	assert.Equal(t, 1, f.groupIndex([]string{"", "code"}))
+	assert.Equal(t, -1, f.groupIndex([]string{"", "level"}))
+	assert.Equal(t, 2, f.groupIndex([]string{"", "level", "code"}))
+}
+
+func TestAssignSubmatchesUnplanned(t *testing.T) {
+	// Fields listed without a regular expression are not planned
+	var value Header
+	assert.NoError(t, AssignSubmatches(&value, []string{}, []string{}))
+	assert.EqualValues(t, Header{}, value)
+
+	var whole RawHeader
+	assert.NoError(t, AssignSubmatches(&whole, []string{}, []string{}))
+	assert.EqualValues(t, RawHeader{}, whole)
+
+	require.NoError(t, AssignSubmatches(&value, []string{"", "code"}, []string{"12", "12"}))
+	assert.EqualValues(t, Header{Code: 12}, value)
+}
+
+func TestConverter(t *testing.T) {
+	type Values struct {
+		String   string
+		Bool     bool
+		Int      int
+		Int8     int8
+		Uint16   uint16
+		Float32  float32
+		Duration time.Duration
+		Pointer  *int
+		Byte     byte
+		Rune     rune
+	}
+
+	type TestCase struct {
+		Name  string
+		Field string
+		Input string
+	}
+
+	testCases := []TestCase{
+		{Name: "String", Field: "String", Input: "text"},
+		{Name: "Bool", Field: "Bool", Input: "true"},
+		{Name: "InvalidBool", Field: "Bool", Input: "yes"},
+		{Name: "Int", Field: "Int", Input: "-12"},
+		{Name: "InvalidInt", Field: "Int", Input: "12a"},
+		{Name: "Int8OutOfRange", Field: "Int8", Input: "300"},
+		{Name: "Uint16", Field: "Uint16", Input: "65535"},
+		{Name: "NegativeUint16", Field: "Uint16", Input: "-1"},
+		{Name: "Float32", Field: "Float32", Input: "0.5"},
+		{Name: "Duration", Field: "Duration", Input: "1500"},
+		{Name: "Pointer", Field: "Pointer", Input: "7"},
+		{Name: "Byte", Field: "Byte", Input: "b"},
+		{Name: "InvalidRune", Field: "Rune", Input: "ab"},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var planned, unplanned Values
+			sf, ok := reflect.TypeOf(Values{}).FieldByName(tc.Field)
+			require.True(t, ok)
+
+			// The converter sets the same value, or fails with the same error, as setField
+			c := newConverter(sf.Type)
+			assert.Equal(t, sf.Type, c.Type)
+			err := c.Set(reflect.ValueOf(&planned).Elem().FieldByIndex(sf.Index), tc.Input)
+			assert.EqualValues(t, setField(reflect.ValueOf(&unplanned).Elem().FieldByIndex(sf.Index), tc.Input), err)
+			assert.EqualValues(t, unplanned, planned)
+		})
+	}
+
+	// Unsettable values are rejected
+	c := newConverter(reflect.TypeOf(0))
+	assert.EqualValues(t, &InvalidType{reflect.TypeOf(0)}, c.Set(reflect.ValueOf(0), "1"))
+}
+
+func BenchmarkParserParseKinds(b *testing.B) {
+	type Kinds struct {
+		StructExp `structexp:"^{{level}} {{code}} {{ok}} {{message}}$"`
+		Level     string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+		Code      int    `structexp.name:"code"`
+		OK        bool   `structexp.name:"ok" structexp.exp:"true|false"`
+		Message   string `structexp.name:"message" structexp.exp:"[a-z ]+"`
+	}
+
+	parser, err := Compile[Kinds]()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Parse("WARN 12 true disk almost full"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// ParseConcatenated returns a new T parsed from each of the records at the
+// start of the string, like the ParseConcatenated method of a Parser.
+//
+// Errors occur if T cannot be compiled, like Compile,
+// or if a record fails to parse.
+func ParseConcatenated[T any](s string) ([]T, string, error) {
+	p, err := Compile[T]()
+	if err != nil {
+		return nil, s, err
+	}
+	return p.ParseConcatenated(s)
+}
+
+// ParsePrefix returns a new T parsed from the match of the Parser's regular
+// expression at the start of the string, ignoring its anchor at the end, and
+// the rest of the string after the match, for input with records back to back,
+// such as fixed width formats. An empty match is not a record.
+//
+// Errors occur if:
+//  - the string exceeds the MaxInputSize option
+//  - regular expression does not match the start of the string
+//  - any field fails to parse
+func (p *Parser[T]) ParsePrefix(s string) (T, string, error) {
+	s = p.input(s)
+	if err := checkInputSize(len(s), p.opts); err != nil {
+		var v T
+		return v, s, err
+	}
+	return p.parsePrefix(s)
+}
+
+// parsePrefix parses the record at the start of the input, like ParsePrefix
+func (p *Parser[T]) parsePrefix(s string) (T, string, error) {
+	var v T
+	start := p.stats.start()
+	var matches []string
+	if loc := findSubmatchIndex(p.prefix, p.scanned, s); loc != nil {
+		matches = submatches(s, loc)
+	}
+	if matches == nil || matches[0] == "" {
+		err := newNoMatch(p.prefix, s)
+		p.stats.observe(start, err)
+		return v, s, err
+	}
+	err := setFields(reflect.ValueOf(&v).Elem(), p.prefix, p.fields, matches)
+	p.stats.observe(start, err)
+	if err != nil {
+		return v, s, err
+	}
+	return v, s[len(matches[0]):], nil
+}
+
+// ParseConcatenated returns a new T parsed from each of the records back to
+// back at the start of the string, applying ParsePrefix until the rest of the
+// string does not start with a record, and the rest it could not parse, which
+// is empty if the whole string was parsed.
+//
+// Errors occur if the string exceeds the MaxInputSize option or a record
+// fails to parse, returning the records before it and the rest from it.
+func (p *Parser[T]) ParseConcatenated(s string) ([]T, string, error) {
+	s = p.input(s)
+	if err := checkInputSize(len(s), p.opts); err != nil {
+		return nil, s, err
+	}
+	var records []T
+	for s != "" {
+		v, rest, err := p.parsePrefix(s)
+		if isNoMatch(err) {
+			break
+		}
+		if err != nil {
+			return records, s, err
+		}
+		records = append(records, v)
+		s = rest
+	}
+	return records, s, nil
+}
+
+// prefixRegexp returns the unanchored regular expression anchored only at the
+// beginning of the text, so that it matches a record at the start of the rest
+func prefixRegexp(all *regexp.Regexp) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + all.String() + `)`)
+}
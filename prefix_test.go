@@ -0,0 +1,78 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type FixedRecord struct {
+	StructExp `structexp:"^{{Code}}{{Name}}$"`
+	Code      int    `structexp.exp:"[0-9-]{3}"`
+	Name      string `structexp.exp:"[A-Z]{4}"`
+}
+
+func TestParsePrefix(t *testing.T) {
+	parser, err := Compile[FixedRecord]()
+	require.NoError(t, err)
+
+	value, rest, err := parser.ParsePrefix("001ABCD002EFGH")
+	require.NoError(t, err)
+	assert.EqualValues(t, FixedRecord{Code: 1, Name: "ABCD"}, value)
+	assert.Equal(t, "002EFGH", rest)
+
+	_, rest, err = parser.ParsePrefix("x001ABCD")
+	assert.EqualValues(t, newNoMatch(parser.prefix, "x001ABCD"), err)
+	assert.Equal(t, "x001ABCD", rest)
+}
+
+func TestParseConcatenated(t *testing.T) {
+	type TestCase struct {
+		Name    string
+		Input   string
+		Records []FixedRecord
+		Rest    string
+		Error   bool
+	}
+
+	testCases := []TestCase{
+		{
+			Name:    "whole",
+			Input:   "001ABCD002EFGH",
+			Records: []FixedRecord{{Code: 1, Name: "ABCD"}, {Code: 2, Name: "EFGH"}},
+		},
+		{
+			Name:    "remainder",
+			Input:   "001ABCD002EFGH00",
+			Records: []FixedRecord{{Code: 1, Name: "ABCD"}, {Code: 2, Name: "EFGH"}},
+			Rest:    "00",
+		},
+		{
+			Name:  "no records",
+			Input: "ABCD",
+			Rest:  "ABCD",
+		},
+		{
+			Name:    "record error",
+			Input:   "001ABCD9-9EFGH003IJKL",
+			Records: []FixedRecord{{Code: 1, Name: "ABCD"}},
+			Rest:    "9-9EFGH003IJKL",
+			Error:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			records, rest, err := ParseConcatenated[FixedRecord](tc.Input)
+			if tc.Error {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.EqualValues(t, tc.Records, records)
+			assert.Equal(t, tc.Rest, rest)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package presets
+
+import (
+	"time"
+
+	"github.com/densestvoid/structexp"
+)
+
+// CI consoles color their output, so the presets of CI console lines are
+// meant to be compiled with the structexp.StripANSI option, such as with
+// structexp.Compile[presets.GitLabSection](structexp.StripANSI()), which
+// removes the escape sequences before matching.
+
+// JenkinsTimestampLine is a line of a Jenkins console log with the prefix of
+// the Timestamper plugin, such as [2024-03-09T10:15:42.123Z] + make test
+type JenkinsTimestampLine struct {
+	structexp.StructExp `structexp:"^\\[{{time}}\\] {{message}}$"`
+	Time                time.Time `structexp.name:"time" structexp.sub:"rfc3339" structexp.exp:"[0-9]{4}-[0-9]{2}-[0-9]{2}T[^\\]]+"`
+	Message             string    `structexp.name:"message" structexp.exp:"[^\\r\\n]*"`
+}
+
+// JenkinsPipelineStep is a step marker of a Jenkins Pipeline console log,
+// such as [Pipeline] sh, or [Pipeline] { (Build), opening the block of the
+// Build stage, with the step, such as sh or {, and its arguments, if any.
+// The console note Jenkins hides before the marker, in an escape sequence
+// of concealed text, is left by StripANSI and skipped.
+type JenkinsPipelineStep struct {
+	structexp.StructExp `structexp:"^(?:ha:////[A-Za-z0-9+/=]*)?\\[Pipeline\\] {{step}} ?{{args}}$"`
+	Step                string `structexp.name:"step" structexp.exp:"[^ \\r\\n]+"`
+	Args                string `structexp.name:"args" structexp.exp:"[^\\r\\n]*"`
+}
+
+// GitHubActionsLine is a line of a GitHub Actions job log, prefixed with its
+// timestamp, and with the workflow command of the line, if any, such as
+// 2024-03-09T10:15:42.1234567Z ##[group]Run make test, opening a collapsed
+// group, where the command is group and the message Run make test
+type GitHubActionsLine struct {
+	structexp.StructExp `structexp:"^{{time}} (?:##\\[{{command}}\\])?{{message}}$"`
+	Time                time.Time `structexp.name:"time" structexp.sub:"rfc3339" structexp.exp:"[0-9]{4}-[0-9]{2}-[0-9]{2}T[^ ]+"`
+	Command             string    `structexp.name:"command" structexp.exp:"[a-z]+" structexp.optional:"true"`
+	Message             string    `structexp.name:"message" structexp.exp:"[^\\r\\n]*"`
+}
+
+// GitLabSection is a section marker of a GitLab CI job log, such as
+// section_start:1710000000:build_script[collapsed=true]\r\x1b[0KBuilding,
+// whose marker is start or end, time the Unix time it was printed, and header
+// the text shown for the section, which is empty at its end. The carriage
+// return and escape sequence erase the marker when shown in a terminal.
+type GitLabSection struct {
+	structexp.StructExp `structexp:"^section_{{marker}}:{{time}}:{{name}}(?:\\[{{options}}\\])?\\r?{{header}}$"`
+	Marker              string    `structexp.name:"marker" structexp.exp:"start|end"`
+	Time                time.Time `structexp.name:"time" structexp.sub:"unix" structexp.exp:"[0-9]+"`
+	Name                string    `structexp.name:"name" structexp.exp:"[A-Za-z0-9_.-]+"`
+	Options             string    `structexp.name:"options" structexp.exp:"[^\\]]*" structexp.optional:"true"`
+	Header              string    `structexp.name:"header" structexp.exp:"[^\\r\\n]*"`
+}
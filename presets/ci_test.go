@@ -0,0 +1,123 @@
+package presets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+)
+
+// parseStripped returns a new T parsed from the string without its ANSI escape sequences
+func parseStripped[T any](s string) (interface{}, error) {
+	p, err := structexp.Compile[T](structexp.StripANSI())
+	if err != nil {
+		return nil, err
+	}
+	v, err := p.Parse(s)
+	return &v, err
+}
+
+// noMatch returns the NoMatch error of the input not matching the regular expression of T,
+// matching up to the byte prefix
+func noMatch[T any](input string, opts ...structexp.Option) *structexp.NoMatch {
+	return structexp.NewNoMatch(structexp.MustCompile[T](opts...).Regexp(), input)
+}
+
+func TestCI(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Parse    func(s string) (interface{}, error)
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "JenkinsTimestampLine",
+			String: "[2024-03-09T10:15:42.123Z] \x1b[32m+ make test\x1b[0m",
+			Parse:  parseStripped[JenkinsTimestampLine],
+			Expected: &JenkinsTimestampLine{
+				Time:    time.Date(2024, 3, 9, 10, 15, 42, 123000000, time.UTC),
+				Message: "+ make test",
+			},
+			Error: nil,
+		},
+		{
+			Name:     "JenkinsPipelineStep",
+			String:   "\x1b[8mha:////4Kz\x1b[0m[Pipeline] { (Build)",
+			Parse:    parseStripped[JenkinsPipelineStep],
+			Expected: &JenkinsPipelineStep{Step: "{", Args: "(Build)"},
+			Error:    nil,
+		},
+		{
+			Name:     "JenkinsPipelineStepWithoutArgs",
+			String:   "[Pipeline] }",
+			Parse:    parseStripped[JenkinsPipelineStep],
+			Expected: &JenkinsPipelineStep{Step: "}"},
+			Error:    nil,
+		},
+		{
+			Name:   "GitHubActionsGroup",
+			String: "2024-03-09T10:15:42.1234567Z ##[group]\x1b[36;1mRun make test\x1b[0m",
+			Parse:  parseStripped[GitHubActionsLine],
+			Expected: &GitHubActionsLine{
+				Time:    time.Date(2024, 3, 9, 10, 15, 42, 123456700, time.UTC),
+				Command: "group",
+				Message: "Run make test",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "GitHubActionsOutput",
+			String: "2024-03-09T10:15:43.0000000Z ok  \tpkg\t0.012s",
+			Parse:  parseStripped[GitHubActionsLine],
+			Expected: &GitHubActionsLine{
+				Time:    time.Date(2024, 3, 9, 10, 15, 43, 0, time.UTC),
+				Message: "ok  \tpkg\t0.012s",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "GitLabSectionStart",
+			String: "section_start:1710000000:build_script[collapsed=true]\r\x1b[0K\x1b[36;1mBuilding\x1b[0;m",
+			Parse:  parseStripped[GitLabSection],
+			Expected: &GitLabSection{
+				Marker:  "start",
+				Time:    time.Unix(1710000000, 0).UTC(),
+				Name:    "build_script",
+				Options: "collapsed=true",
+				Header:  "Building",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "GitLabSectionEnd",
+			String: "section_end:1710000042:build_script\r\x1b[0K",
+			Parse:  parseStripped[GitLabSection],
+			Expected: &GitLabSection{
+				Marker: "end",
+				Time:   time.Unix(1710000042, 0).UTC(),
+				Name:   "build_script",
+			},
+			Error: nil,
+		},
+		{
+			Name:     "GitLabSectionIsNotStep",
+			String:   "section_end:1710000042:build_script",
+			Parse:    parseStripped[JenkinsPipelineStep],
+			Expected: &JenkinsPipelineStep{},
+			Error:    noMatch[JenkinsPipelineStep]("section_end:1710000042:build_script", structexp.StripANSI()),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := tc.Parse(tc.String)
+			assert.EqualValues(t, tc.Expected, v)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
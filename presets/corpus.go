@@ -0,0 +1,107 @@
+package presets
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CorpusVersion is incremented whenever a corpus or its golden results change,
+// so that tests built on the corpora can tell which samples they were written for
+const CorpusVersion = 4
+
+//go:embed corpus
+var corpora embed.FS
+
+// Corpus file extensions, like those of the structexptest package
+const (
+	// lineExt is the extension of a corpus holding a sample per line
+	lineExt = ".txt"
+	// quotedExt is the extension of a corpus holding a Go quoted sample per line
+	quotedExt = ".quoted"
+)
+
+// Corpora returns the file system of the preset corpora. Each preset has a
+// corpus file named after its type, and a golden file of the same name
+// followed by .golden, holding the JSON result of parsing each sample.
+// Corpus files have a .txt extension, such as HTTPRequestLine.txt, holding a
+// sample line per line, or, for presets of records that cannot be held in a
+// line as is, such as RESPSimple with its CRLF framing, a .quoted extension,
+// such as RESPSimple.quoted, holding a Go quoted sample per line, such as
+// "+OK\r\n". CorpusFile returns the name of a preset's corpus file, and
+// structexptest reads both formats.
+func Corpora() fs.FS {
+	sub, err := fs.Sub(corpora, "corpus")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// CorpusNames returns the names of the presets with a corpus, in sorted order
+func CorpusNames() []string {
+	entries, err := fs.ReadDir(corpora, "corpus")
+	if err != nil {
+		panic(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		for _, ext := range []string{lineExt, quotedExt} {
+			if name, ok := strings.CutSuffix(entry.Name(), ext); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CorpusFile returns the name of the corpus file of the named preset in
+// Corpora, such as HTTPRequestLine.txt. Errors occur if the preset has no corpus.
+func CorpusFile(name string) (string, error) {
+	file := name + lineExt
+	if _, err := fs.Stat(corpora, path.Join("corpus", file)); err == nil {
+		return file, nil
+	}
+	file = name + quotedExt
+	if _, err := fs.Stat(corpora, path.Join("corpus", file)); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// Corpus returns the samples of the named preset, such as HTTPRequestLine,
+// skipping empty lines, and unquoting those of a .quoted corpus file.
+// Errors occur if the preset has no corpus.
+func Corpus(name string) ([]string, error) {
+	file, err := CorpusFile(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := corpora.Open(path.Join("corpus", file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if strings.HasSuffix(file, quotedExt) {
+			if line, err = strconv.Unquote(line); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", file, n, err)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
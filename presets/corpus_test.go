@@ -0,0 +1,79 @@
+package presets
+
+import (
+	"io/fs"
+	"sort"
+	"testing"
+
+	"github.com/densestvoid/structexp/structexptest"
+	"github.com/stretchr/testify/assert"
+)
+
+// corpusPresets are the new values of the presets with a corpus, by type name
+var corpusPresets = map[string]func() interface{}{
+	"AuthorizedKey":        func() interface{} { return new(AuthorizedKey) },
+	"CPUStat":              func() interface{} { return new(CPUStat) },
+	"CronSchedule":         func() interface{} { return new(CronSchedule) },
+	"CrontabEntry":         func() interface{} { return new(CrontabEntry) },
+	"FTPReply":             func() interface{} { return new(FTPReply) },
+	"GitHubActionsLine":    func() interface{} { return new(GitHubActionsLine) },
+	"GitLabSection":        func() interface{} { return new(GitLabSection) },
+	"HTTPHeader":           func() interface{} { return new(HTTPHeader) },
+	"HTTPRequestLine":      func() interface{} { return new(HTTPRequestLine) },
+	"HTTPStatusLine":       func() interface{} { return new(HTTPStatusLine) },
+	"HTTPVersion":          func() interface{} { return new(HTTPVersion) },
+	"HexDumpLine":          func() interface{} { return new(HexDumpLine) },
+	"IISDirective":         func() interface{} { return new(IISDirective) },
+	"IISLogEntry":          func() interface{} { return new(IISLogEntry) },
+	"JenkinsPipelineStep":  func() interface{} { return new(JenkinsPipelineStep) },
+	"JenkinsTimestampLine": func() interface{} { return new(JenkinsTimestampLine) },
+	"KnownHost":            func() interface{} { return new(KnownHost) },
+	"MeminfoLine":          func() interface{} { return new(MeminfoLine) },
+	"NetDevLine":           func() interface{} { return new(NetDevLine) },
+	"RESPSimple":           func() interface{} { return new(RESPSimple) },
+	"RedisInlineCommand":   func() interface{} { return new(RedisInlineCommand) },
+	"SMTPCommand":          func() interface{} { return new(SMTPCommand) },
+	"SMTPReply":            func() interface{} { return new(SMTPReply) },
+	"SystemdCalendar":      func() interface{} { return new(SystemdCalendar) },
+	"WindowsEvent":         func() interface{} { return new(WindowsEvent) },
+	"XXDLine":              func() interface{} { return new(XXDLine) },
+}
+
+func TestCorpora(t *testing.T) {
+	names := make([]string, 0, len(corpusPresets))
+	for name := range corpusPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, names, CorpusNames())
+
+	for _, name := range names {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			lines, err := Corpus(name)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, lines)
+			file, err := CorpusFile(name)
+			assert.NoError(t, err)
+			// Written with -structexptest.update, then checked as embedded
+			structexptest.RunGolden(t, corpusPresets[name], "corpus/"+file)
+			structexptest.RunGoldenFS(t, corpusPresets[name], Corpora(), file)
+		})
+	}
+
+	_, err := Corpus("Unknown")
+	assert.Error(t, err)
+	_, err = CorpusFile("Unknown")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestCorpusQuoted(t *testing.T) {
+	file, err := CorpusFile("RESPSimple")
+	assert.NoError(t, err)
+	assert.Equal(t, "RESPSimple.quoted", file)
+
+	// Each sample is a whole record, with its CRLF framing
+	samples, err := Corpus("RESPSimple")
+	assert.NoError(t, err)
+	assert.Contains(t, samples, "+OK\r\n")
+}
@@ -0,0 +1,150 @@
+package presets
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/densestvoid/structexp"
+)
+
+// cronNames are the values of the month and weekday names of cron
+// expressions and systemd calendar events, by their first three letters
+var cronNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// CronRange is a range of values of a cron field, such as 1-5, 9, or */15
+type CronRange struct {
+	// First and Last are the bounds of the range, both -1 for *
+	First, Last int
+	// Step is the increment between the values, 1 if not given
+	Step int
+}
+
+// Any reports whether the range is *, every value of the field
+func (r CronRange) Any() bool {
+	return r.First == -1
+}
+
+func (r CronRange) String() string {
+	var s string
+	switch {
+	case r.Any():
+		s = "*"
+	case r.First == r.Last:
+		s = strconv.Itoa(r.First)
+	default:
+		s = fmt.Sprintf("%d-%d", r.First, r.Last)
+	}
+	if r.Step != 1 {
+		s += "/" + strconv.Itoa(r.Step)
+	}
+	return s
+}
+
+// CronField is a field of a cron expression, or a component of a systemd
+// calendar event, as its comma separated ranges, such as 1-5,10,*/15.
+// Ranges are separated by - in cron, or .. in systemd, and months and
+// weekdays may be named, such as Jan or MON-FRI.
+type CronField []CronRange
+
+// Parse parses the ranges of the field
+func (f *CronField) Parse(s string) error {
+	parts := strings.Split(s, ",")
+	ranges := make(CronField, len(parts))
+	for i, part := range parts {
+		r := CronRange{Step: 1}
+		if before, step, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(step)
+			if err != nil {
+				return err
+			}
+			if n < 1 {
+				return fmt.Errorf("step of %q is not positive", part)
+			}
+			part, r.Step = before, n
+		}
+		if part == "*" {
+			r.First, r.Last = -1, -1
+			ranges[i] = r
+			continue
+		}
+		first, last, ok := strings.Cut(part, "..")
+		if !ok {
+			first, last, ok = strings.Cut(part, "-")
+		}
+		var err error
+		if r.First, err = cronValue(first); err != nil {
+			return err
+		}
+		r.Last = r.First
+		if ok {
+			if r.Last, err = cronValue(last); err != nil {
+				return err
+			}
+		}
+		ranges[i] = r
+	}
+	*f = ranges
+	return nil
+}
+
+// cronValue returns the value of the number, or month or weekday name
+func cronValue(s string) (int, error) {
+	if len(s) >= 3 {
+		if v, ok := cronNames[strings.ToLower(s[:3])]; ok {
+			return v, nil
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+func (f CronField) String() string {
+	parts := make([]string, len(f))
+	for i, r := range f {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// CronSchedule is the five field expression of a cron job, such as
+// */15 9-17 * * MON-FRI: minute, hour, day of month, month, and day of week
+type CronSchedule struct {
+	structexp.StructExp `structexp:"^{{minute}}[ \\t]+{{hour}}[ \\t]+{{day}}[ \\t]+{{month}}[ \\t]+{{weekday}}$"`
+	Minute              CronField `structexp.name:"minute" structexp.exp:"[0-9*,/-]+"`
+	Hour                CronField `structexp.name:"hour" structexp.exp:"[0-9*,/-]+"`
+	DayOfMonth          CronField `structexp.name:"day" structexp.exp:"[0-9*,/-]+"`
+	Month               CronField `structexp.name:"month" structexp.exp:"[0-9A-Za-z*,/-]+"`
+	DayOfWeek           CronField `structexp.name:"weekday" structexp.exp:"[0-9A-Za-z*,/-]+"`
+}
+
+// CrontabEntry is a job line of a user's crontab, such as
+// 0 3 * * * /usr/local/bin/backup --full, with its schedule and command
+type CrontabEntry struct {
+	structexp.StructExp `structexp:"^{{schedule}}[ \\t]+{{command}}$"`
+	Schedule            CronSchedule `structexp.name:"schedule"`
+	Command             string       `structexp.name:"command" structexp.exp:"[^\\r\\n]+"`
+}
+
+// SystemdCalendar is the calendar event of the OnCalendar setting of a
+// systemd timer, such as Mon..Fri *-*-* 09:00:00 or 2024-*-01 00:00, with
+// its optional weekdays, year, seconds, and time zone, or is a shorthand,
+// such as daily or weekly, in which case only Shorthand is set. Shorthands
+// are only matched by a Parser, from structexp.Compile, which tries each
+// template of the struct.
+type SystemdCalendar struct {
+	Event      structexp.StructExp `structexp:"^(?:{{weekdays}} )?(?:{{year}}-)?{{month}}-{{day}} {{hour}}:{{minute}}(?::{{second}})?(?: {{zone}})?$"`
+	Shorthands structexp.StructExp `structexp:"^{{shorthand}}$"`
+	Weekdays   CronField           `structexp.name:"weekdays" structexp.exp:"[A-Za-z.,]+" structexp.optional:"true"`
+	Year       CronField           `structexp.name:"year" structexp.exp:"[0-9*,./]+" structexp.optional:"true"`
+	Month      CronField           `structexp.name:"month" structexp.exp:"[0-9*,./]+"`
+	Day        CronField           `structexp.name:"day" structexp.exp:"[0-9*,./]+"`
+	Hour       CronField           `structexp.name:"hour" structexp.exp:"[0-9*,./]+"`
+	Minute     CronField           `structexp.name:"minute" structexp.exp:"[0-9*,./]+"`
+	Second     CronField           `structexp.name:"second" structexp.exp:"[0-9*,./]+" structexp.optional:"true"`
+	Timezone   string              `structexp.name:"zone" structexp.exp:"[A-Za-z_/+-]+" structexp.optional:"true"`
+	Shorthand  string              `structexp.name:"shorthand" structexp.exp:"minutely|hourly|daily|monthly|weekly|yearly|annually|quarterly|semiannually"`
+}
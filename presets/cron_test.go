@@ -0,0 +1,112 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	every := CronField{{First: -1, Last: -1, Step: 1}}
+	testCases := []TestCase{
+		{
+			Name:   "CronSchedule",
+			String: "*/15 9-17 1,15 * MON-FRI",
+			Input:  &CronSchedule{},
+			Expected: &CronSchedule{
+				Minute:     CronField{{First: -1, Last: -1, Step: 15}},
+				Hour:       CronField{{First: 9, Last: 17, Step: 1}},
+				DayOfMonth: CronField{{First: 1, Last: 1, Step: 1}, {First: 15, Last: 15, Step: 1}},
+				Month:      every,
+				DayOfWeek:  CronField{{First: 1, Last: 5, Step: 1}},
+			},
+			Error: nil,
+		},
+		{
+			Name:   "CrontabEntry",
+			String: "0 3 * jan,jul *\t/usr/local/bin/backup --full",
+			Input:  &CrontabEntry{},
+			Expected: &CrontabEntry{
+				Schedule: CronSchedule{
+					Minute:     CronField{{First: 0, Last: 0, Step: 1}},
+					Hour:       CronField{{First: 3, Last: 3, Step: 1}},
+					DayOfMonth: every,
+					Month:      CronField{{First: 1, Last: 1, Step: 1}, {First: 7, Last: 7, Step: 1}},
+					DayOfWeek:  every,
+				},
+				Command: "/usr/local/bin/backup --full",
+			},
+			Error: nil,
+		},
+		{
+			Name:     "CronScheduleFourFields",
+			String:   "0 3 * *",
+			Input:    &CronSchedule{},
+			Expected: &CronSchedule{},
+			Error:    noMatch[CronSchedule]("0 3 * *"),
+		},
+		{
+			Name:   "SystemdCalendar",
+			String: "Mon..Fri *-*-* 09:00/30:00 Europe/Berlin",
+			Input:  &SystemdCalendar{},
+			Expected: &SystemdCalendar{
+				Weekdays: CronField{{First: 1, Last: 5, Step: 1}},
+				Year:     every,
+				Month:    every,
+				Day:      every,
+				Hour:     CronField{{First: 9, Last: 9, Step: 1}},
+				Minute:   CronField{{First: 0, Last: 0, Step: 30}},
+				Second:   CronField{{First: 0, Last: 0, Step: 1}},
+				Timezone: "Europe/Berlin",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "SystemdCalendarMonthDay",
+			String: "*-01 00:00",
+			Input:  &SystemdCalendar{},
+			Expected: &SystemdCalendar{
+				Month:  every,
+				Day:    CronField{{First: 1, Last: 1, Step: 1}},
+				Hour:   CronField{{First: 0, Last: 0, Step: 1}},
+				Minute: CronField{{First: 0, Last: 0, Step: 1}},
+			},
+			Error: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestSystemdCalendarShorthand(t *testing.T) {
+	parser, err := structexp.Compile[SystemdCalendar]()
+	require.NoError(t, err)
+
+	calendar, err := parser.Parse("weekly")
+	require.NoError(t, err)
+	assert.EqualValues(t, SystemdCalendar{Shorthand: "weekly"}, calendar)
+}
+
+func TestCronFieldString(t *testing.T) {
+	var f CronField
+	assert.NoError(t, f.Parse("*/15,1-5,MON,7"))
+	assert.Equal(t, "*/15,1-5,1,7", f.String())
+	assert.Error(t, f.Parse("*/0"))
+}
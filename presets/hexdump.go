@@ -0,0 +1,70 @@
+package presets
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/densestvoid/structexp"
+)
+
+// HexOffset is the hexadecimal offset of a hex dump line, such as 00000010
+type HexOffset int64
+
+// Parse parses the offset from its hexadecimal digits
+func (o *HexOffset) Parse(s string) error {
+	i, err := strconv.ParseInt(s, 16, 64)
+	if err != nil {
+		return err
+	}
+	*o = HexOffset(i)
+	return nil
+}
+
+func (o HexOffset) String() string {
+	return fmt.Sprintf("%08x", int64(o))
+}
+
+// HexBytes are the bytes of a hex dump line, parsed from pairs of hexadecimal
+// digits separated by any number of spaces, such as 48 65 6c or 4865 6c
+type HexBytes []byte
+
+// Parse parses the bytes from their hexadecimal digits, ignoring spaces
+func (b *HexBytes) Parse(s string) error {
+	decoded, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+func (b HexBytes) String() string {
+	pairs := make([]string, len(b))
+	for i, c := range b {
+		pairs[i] = fmt.Sprintf("%02x", c)
+	}
+	return strings.Join(pairs, " ")
+}
+
+// HexDumpLine is a line of the canonical output of hexdump -C, or hd, such as
+// 00000010  6f 20 77 6f 72 6c 64 0a                           |o world.|
+// The lines of repeated data, *, and of the final offset have no bytes and
+// do not match.
+type HexDumpLine struct {
+	structexp.StructExp `structexp:"^{{offset}}  {{data}} +\\|{{text}}\\|$"`
+	Offset              HexOffset `structexp.name:"offset" structexp.exp:"[0-9a-fA-F]{8,}"`
+	Data                HexBytes  `structexp.name:"data" structexp.exp:"[0-9a-fA-F]{2}(?:  ?[0-9a-fA-F]{2}){0,15}"`
+	Text                string    `structexp.name:"text" structexp.exp:".{1,16}"`
+}
+
+// XXDLine is a line of the output of xxd, such as
+// 00000010: 6f20 776f 726c 640a                      o world.
+// The text column is the printable characters of the bytes, with . for others.
+type XXDLine struct {
+	structexp.StructExp `structexp:"^{{offset}}: {{data}}  +{{text}}$"`
+	Offset              HexOffset `structexp.name:"offset" structexp.exp:"[0-9a-fA-F]{8,}"`
+	Data                HexBytes  `structexp.name:"data" structexp.exp:"[0-9a-fA-F]{2}(?:[0-9a-fA-F]{2}| [0-9a-fA-F]{2})*"`
+	Text                string    `structexp.name:"text" structexp.exp:".+"`
+}
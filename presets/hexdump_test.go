@@ -0,0 +1,86 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexDump(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "HexDumpLine",
+			String: "00000000  48 65 6c 6c 6f 20 77 6f  72 6c 64 2e 48 65 6c 6c  |Hello world.Hell|",
+			Input:  &HexDumpLine{},
+			Expected: &HexDumpLine{
+				Offset: 0,
+				Data:   HexBytes("Hello world.Hell"),
+				Text:   "Hello world.Hell",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "HexDumpShortLine",
+			String: "00000010  6f 20 77 6f 72 6c 64 0a                           |o world.|",
+			Input:  &HexDumpLine{},
+			Expected: &HexDumpLine{
+				Offset: 16,
+				Data:   HexBytes("o world\n"),
+				Text:   "o world.",
+			},
+			Error: nil,
+		},
+		{
+			Name:     "HexDumpFinalOffset",
+			String:   "00000018",
+			Input:    &HexDumpLine{},
+			Expected: &HexDumpLine{},
+			Error:    noMatch[HexDumpLine]("00000018"),
+		},
+		{
+			Name:   "XXDLine",
+			String: "00000010: 6f20 776f 726c 642c 2061 6761 696e 210a  o world, again!.",
+			Input:  &XXDLine{},
+			Expected: &XXDLine{
+				Offset: 16,
+				Data:   HexBytes("o world, again!\n"),
+				Text:   "o world, again!.",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "XXDShortLine",
+			String: "00000020: 6f20 776f 726c 640a                      o world.",
+			Input:  &XXDLine{},
+			Expected: &XXDLine{
+				Offset: 32,
+				Data:   HexBytes("o world\n"),
+				Text:   "o world.",
+			},
+			Error: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestHexString(t *testing.T) {
+	assert.Equal(t, "00000010", HexOffset(16).String())
+	assert.Equal(t, "48 65 0a", HexBytes("He\n").String())
+}
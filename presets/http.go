@@ -0,0 +1,44 @@
+package presets
+
+import (
+	"fmt"
+
+	"github.com/densestvoid/structexp"
+)
+
+// HTTPVersion is the protocol version of an HTTP/1.x message, such as HTTP/1.1
+type HTTPVersion struct {
+	structexp.StructExp `structexp:"^HTTP/{{major}}\\.{{minor}}$"`
+	Major               int `structexp.name:"major" structexp.exp:"[0-9]"`
+	Minor               int `structexp.name:"minor" structexp.exp:"[0-9]"`
+}
+
+func (v HTTPVersion) String() string {
+	return fmt.Sprintf("HTTP/%d.%d", v.Major, v.Minor)
+}
+
+// HTTPRequestLine is the request line of an HTTP/1.x request,
+// such as GET /index.html?q=1 HTTP/1.1
+type HTTPRequestLine struct {
+	structexp.StructExp `structexp:"^{{method}} {{target}} {{Proto}}$"`
+	Method              string `structexp.name:"method" structexp.exp:"[!#$%&'*+.^_\x60|~0-9A-Za-z-]+"`
+	Target              string `structexp.name:"target" structexp.exp:"[^\\s]+"`
+	Proto               HTTPVersion
+}
+
+// HTTPStatusLine is the status line of an HTTP/1.x response,
+// such as HTTP/1.1 404 Not Found. The reason phrase may be empty.
+type HTTPStatusLine struct {
+	structexp.StructExp `structexp:"^{{Proto}} {{code}}(?: {{reason}})?$"`
+	Proto               HTTPVersion
+	StatusCode          int    `structexp.name:"code" structexp.exp:"[0-9]{3}"`
+	Reason              string `structexp.name:"reason" structexp.exp:"[^\\r\\n]*"`
+}
+
+// HTTPHeader is a single header field line of an HTTP/1.x message, such as
+// Content-Type: text/html. Whitespace around the value is not captured.
+type HTTPHeader struct {
+	structexp.StructExp `structexp:"^{{name}}:[ \\t]*{{value}}[ \\t]*$"`
+	Name                string `structexp.name:"name" structexp.exp:"[!#$%&'*+.^_\x60|~0-9A-Za-z-]+"`
+	Value               string `structexp.name:"value" structexp.exp:"(?:[^\\s](?:[^\\r\\n]*[^\\s])?)?"`
+}
@@ -0,0 +1,80 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTP(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "RequestLine",
+			String: "GET /index.html?q=1 HTTP/1.1",
+			Input:  &HTTPRequestLine{},
+			Expected: &HTTPRequestLine{
+				Method: "GET",
+				Target: "/index.html?q=1",
+				Proto:  HTTPVersion{Major: 1, Minor: 1},
+			},
+			Error: nil,
+		},
+		{
+			Name:   "StatusLine",
+			String: "HTTP/1.0 404 Not Found",
+			Input:  &HTTPStatusLine{},
+			Expected: &HTTPStatusLine{
+				Proto:      HTTPVersion{Major: 1, Minor: 0},
+				StatusCode: 404,
+				Reason:     "Not Found",
+			},
+			Error: nil,
+		},
+		{
+			Name:   "StatusLineWithoutReason",
+			String: "HTTP/1.1 204",
+			Input:  &HTTPStatusLine{},
+			Expected: &HTTPStatusLine{
+				Proto:      HTTPVersion{Major: 1, Minor: 1},
+				StatusCode: 204,
+			},
+			Error: nil,
+		},
+		{
+			Name:     "Header",
+			String:   "Content-Type:  text/html; charset=utf-8 ",
+			Input:    &HTTPHeader{},
+			Expected: &HTTPHeader{Name: "Content-Type", Value: "text/html; charset=utf-8"},
+			Error:    nil,
+		},
+		{
+			Name:     "NoMatchError",
+			String:   "GET /index.html",
+			Input:    &HTTPRequestLine{},
+			Expected: &HTTPRequestLine{},
+			Error:    noMatch[HTTPRequestLine]("GET /index.html"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestHTTPVersionString(t *testing.T) {
+	assert.Equal(t, "HTTP/1.1", HTTPVersion{Major: 1, Minor: 1}.String())
+}
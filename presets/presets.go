@@ -0,0 +1,14 @@
+// Package presets provides ready to use structexp structs for common text
+// formats: log lines, such as of IIS and CI consoles, protocol lines, such as
+// of HTTP, SMTP, FTP, and Redis, configuration lines, such as of crontabs,
+// systemd timers, and SSH, and system output, such as hex dumps and /proc files.
+//
+// Presets are parsed like any other struct, with structexp.Parse,
+// structexp.ParseAll, or structexp.Compile, and can be nested in other
+// structs, as their own StructExp expressions are spliced into the parent's.
+//
+// Every preset has a corpus of samples, with the golden results of
+// parsing them, embedded in the package. Corpora returns them, so that a
+// modified or extended preset can be checked against the same samples with
+// structexptest.RunGoldenFS.
+package presets
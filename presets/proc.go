@@ -0,0 +1,106 @@
+package presets
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/densestvoid/structexp"
+)
+
+// userHZ is the frequency of the clock ticks of /proc/stat, USER_HZ,
+// which is 100 on every architecture Linux supports
+const userHZ = 100
+
+// ByteSize is a number of bytes, parsed from a number with an optional
+// unit of /proc files, such as 16316412 kB, where kB is 1024 bytes
+type ByteSize int64
+
+// byteUnits are the multipliers of the units of /proc files, which are powers of 1024
+var byteUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"kB": 1 << 10,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+}
+
+// Parse parses the number of bytes, multiplied by its unit
+func (b *ByteSize) Parse(s string) error {
+	number, unit, _ := strings.Cut(s, " ")
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return &strconv.NumError{Func: "ParseByteSize", Num: s, Err: strconv.ErrSyntax}
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return err
+	}
+	*b = ByteSize(n * multiplier)
+	return nil
+}
+
+func (b ByteSize) String() string {
+	return strconv.FormatInt(int64(b), 10)
+}
+
+// Ticks is a number of clock ticks of /proc/stat, in units of USER_HZ
+type Ticks int
+
+// Duration returns the time of the clock ticks
+func (t Ticks) Duration() time.Duration {
+	return time.Duration(t) * time.Second / userHZ
+}
+
+// MeminfoLine is a line of /proc/meminfo, such as MemTotal: 16316412 kB,
+// with its size converted to bytes. Counts without a unit, such as
+// HugePages_Total: 0, are parsed as their number.
+type MeminfoLine struct {
+	structexp.StructExp `structexp:"^{{name}}: +{{size}}$"`
+	Name                string   `structexp.name:"name" structexp.exp:"[A-Za-z0-9_()]+"`
+	Size                ByteSize `structexp.name:"size" structexp.exp:"[0-9]+(?: [kKMG]?B)?"`
+}
+
+// CPUStat is a cpu line of /proc/stat, such as cpu0 4705 356 584 3699176
+// 23060 0 277 0 0 0, with the time spent by the CPU in each state. The CPU is
+// empty for the first line, summing all CPUs. The steal and guest times are
+// absent from the lines of old kernels, and left zero.
+type CPUStat struct {
+	structexp.StructExp `structexp:"^cpu{{cpu}} +{{user}} {{nice}} {{system}} {{idle}} {{iowait}} {{irq}} {{softirq}}(?: {{steal}})?(?: {{guest}})?(?: {{guestnice}})?$"`
+	CPU                 string `structexp.name:"cpu" structexp.exp:"[0-9]*"`
+	User                Ticks  `structexp.name:"user"`
+	Nice                Ticks  `structexp.name:"nice"`
+	System              Ticks  `structexp.name:"system"`
+	Idle                Ticks  `structexp.name:"idle"`
+	IOWait              Ticks  `structexp.name:"iowait"`
+	IRQ                 Ticks  `structexp.name:"irq"`
+	SoftIRQ             Ticks  `structexp.name:"softirq"`
+	Steal               Ticks  `structexp.name:"steal" structexp.optional:"true"`
+	Guest               Ticks  `structexp.name:"guest" structexp.optional:"true"`
+	GuestNice           Ticks  `structexp.name:"guestnice" structexp.optional:"true"`
+}
+
+// NetDevLine is an interface line of /proc/net/dev, such as
+// eth0: 1234 12 0 0 0 0 0 0 5678 34 0 0 0 0 0 0, with the received and
+// transmitted counters of the interface. The two header lines do not match.
+type NetDevLine struct {
+	structexp.StructExp `structexp:"^ *{{iface}}: *{{rxbytes}} +{{rxpackets}} +{{rxerrs}} +{{rxdrop}} +{{rxfifo}} +{{rxframe}} +{{rxcompressed}} +{{rxmulticast}} +{{txbytes}} +{{txpackets}} +{{txerrs}} +{{txdrop}} +{{txfifo}} +{{txcolls}} +{{txcarrier}} +{{txcompressed}}$"`
+	Interface           string   `structexp.name:"iface" structexp.exp:"[^ :]+"`
+	RxBytes             ByteSize `structexp.name:"rxbytes" structexp.exp:"[0-9]+"`
+	RxPackets           int      `structexp.name:"rxpackets"`
+	RxErrors            int      `structexp.name:"rxerrs"`
+	RxDropped           int      `structexp.name:"rxdrop"`
+	RxFIFO              int      `structexp.name:"rxfifo"`
+	RxFrame             int      `structexp.name:"rxframe"`
+	RxCompressed        int      `structexp.name:"rxcompressed"`
+	RxMulticast         int      `structexp.name:"rxmulticast"`
+	TxBytes             ByteSize `structexp.name:"txbytes" structexp.exp:"[0-9]+"`
+	TxPackets           int      `structexp.name:"txpackets"`
+	TxErrors            int      `structexp.name:"txerrs"`
+	TxDropped           int      `structexp.name:"txdrop"`
+	TxFIFO              int      `structexp.name:"txfifo"`
+	TxCollisions        int      `structexp.name:"txcolls"`
+	TxCarrier           int      `structexp.name:"txcarrier"`
+	TxCompressed        int      `structexp.name:"txcompressed"`
+}
@@ -0,0 +1,86 @@
+package presets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProc(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "MeminfoLine",
+			String:   "MemTotal:       16316412 kB",
+			Input:    &MeminfoLine{},
+			Expected: &MeminfoLine{Name: "MemTotal", Size: 16316412 * 1024},
+			Error:    nil,
+		},
+		{
+			Name:     "MeminfoLineCount",
+			String:   "HugePages_Total:       4",
+			Input:    &MeminfoLine{},
+			Expected: &MeminfoLine{Name: "HugePages_Total", Size: 4},
+			Error:    nil,
+		},
+		{
+			Name:   "CPUStat",
+			String: "cpu  4705 356 584 3699176 23060 0 277 1 2 3",
+			Input:  &CPUStat{},
+			Expected: &CPUStat{
+				User: 4705, Nice: 356, System: 584, Idle: 3699176, IOWait: 23060,
+				IRQ: 0, SoftIRQ: 277, Steal: 1, Guest: 2, GuestNice: 3,
+			},
+			Error: nil,
+		},
+		{
+			Name:   "CPUStatOldKernel",
+			String: "cpu3 1393280 32966 572056 13343292 6130 0 17875",
+			Input:  &CPUStat{},
+			Expected: &CPUStat{
+				CPU: "3", User: 1393280, Nice: 32966, System: 572056, Idle: 13343292, IOWait: 6130,
+				IRQ: 0, SoftIRQ: 17875,
+			},
+			Error: nil,
+		},
+		{
+			Name:   "NetDevLine",
+			String: "  eth0: 1234567   8901    1    2    0     0          0        12  7654321    4321    0    3    0     0       0          0",
+			Input:  &NetDevLine{},
+			Expected: &NetDevLine{
+				Interface: "eth0", RxBytes: 1234567, RxPackets: 8901, RxErrors: 1, RxDropped: 2, RxMulticast: 12,
+				TxBytes: 7654321, TxPackets: 4321, TxDropped: 3,
+			},
+			Error: nil,
+		},
+		{
+			Name:     "NetDevHeader",
+			String:   " face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed",
+			Input:    &NetDevLine{},
+			Expected: &NetDevLine{},
+			Error:    noMatch[NetDevLine](" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestTicksDuration(t *testing.T) {
+	assert.Equal(t, 47050*time.Millisecond, Ticks(4705).Duration())
+}
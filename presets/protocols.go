@@ -0,0 +1,62 @@
+package presets
+
+import "github.com/densestvoid/structexp"
+
+// SMTPReply is a reply line of an SMTP server, such as 250-SIZE 35882577, or
+// 550 5.1.1 User unknown, with the enhanced status code of RFC 3463, if any.
+// A hyphen after the code continues a multiline reply, ended by the line
+// with a space after it. The line may end with the carriage return of CRLF.
+type SMTPReply struct {
+	structexp.StructExp `structexp:"^{{code}}{{sep}}(?:{{enhanced}} )?{{text}}\\r?$"`
+	Code                int    `structexp.name:"code" structexp.exp:"[2-5][0-9]{2}"`
+	Separator           string `structexp.name:"sep" structexp.exp:"[ -]?"`
+	EnhancedCode        string `structexp.name:"enhanced" structexp.exp:"[245]\\.[0-9]{1,3}\\.[0-9]{1,3}" structexp.optional:"true"`
+	Text                string `structexp.name:"text" structexp.exp:"[^\\r\\n]*"`
+}
+
+// Last reports whether the line is the last of its reply
+func (r SMTPReply) Last() bool {
+	return r.Separator != "-"
+}
+
+// SMTPCommand is a command line of an SMTP client, such as
+// MAIL FROM:<alice@example.com>, with its verb and arguments, if any
+type SMTPCommand struct {
+	structexp.StructExp `structexp:"^{{verb}} ?{{args}}\\r?$"`
+	Verb                string `structexp.name:"verb" structexp.exp:"[A-Za-z]{4}"`
+	Args                string `structexp.name:"args" structexp.exp:"[^\\r\\n]*"`
+}
+
+// FTPReply is a reply line of an FTP server, such as 230 User logged in,
+// or the first line of a multiline reply, such as 211-Features:, whose
+// hyphen continues the reply until the line with the same code and a space
+type FTPReply struct {
+	structexp.StructExp `structexp:"^{{code}}{{sep}}{{text}}\\r?$"`
+	Code                int    `structexp.name:"code" structexp.exp:"[1-6][0-9]{2}"`
+	Separator           string `structexp.name:"sep" structexp.exp:"[ -]?"`
+	Text                string `structexp.name:"text" structexp.exp:"[^\\r\\n]*"`
+}
+
+// Last reports whether the line is the last of its reply
+func (r FTPReply) Last() bool {
+	return r.Separator != "-"
+}
+
+// RESPSimple is a simple value of the Redis serialization protocol: a simple
+// string, such as +OK, an error, such as -ERR unknown command, or an
+// integer, such as :1000, of type +, -, or : followed by its text, and
+// CRLF. Replies sent back to back are parsed with ParseConcatenated.
+type RESPSimple struct {
+	structexp.StructExp `structexp:"^{{type}}{{value}}\\r\\n$"`
+	Type                string `structexp.name:"type" structexp.exp:"[+:-]"`
+	Value               string `structexp.name:"value" structexp.exp:"[^\\r\\n]*"`
+}
+
+// RedisInlineCommand is an inline command of the Redis protocol, sent without
+// RESP framing, such as by telnet, like SET greeting hello, with its arguments
+// separated by spaces
+type RedisInlineCommand struct {
+	structexp.StructExp `structexp:"^{{command}} ?{{args}}\\r?$"`
+	Command             string   `structexp.name:"command" structexp.exp:"[A-Za-z]+"`
+	Args                []string `structexp.name:"args" structexp.exp:"(?:[^ \\r\\n]+(?: [^ \\r\\n]+)*)?" structexp.split:" "`
+}
@@ -0,0 +1,124 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtocols(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "SMTPReplyContinued",
+			String:   "250-SIZE 35882577\r",
+			Input:    &SMTPReply{},
+			Expected: &SMTPReply{Code: 250, Separator: "-", Text: "SIZE 35882577"},
+			Error:    nil,
+		},
+		{
+			Name:     "SMTPReplyEnhanced",
+			String:   "550 5.1.1 User unknown",
+			Input:    &SMTPReply{},
+			Expected: &SMTPReply{Code: 550, Separator: " ", EnhancedCode: "5.1.1", Text: "User unknown"},
+			Error:    nil,
+		},
+		{
+			Name:     "SMTPCommand",
+			String:   "MAIL FROM:<alice@example.com>\r",
+			Input:    &SMTPCommand{},
+			Expected: &SMTPCommand{Verb: "MAIL", Args: "FROM:<alice@example.com>"},
+			Error:    nil,
+		},
+		{
+			Name:     "SMTPCommandWithoutArgs",
+			String:   "QUIT",
+			Input:    &SMTPCommand{},
+			Expected: &SMTPCommand{Verb: "QUIT"},
+			Error:    nil,
+		},
+		{
+			Name:     "FTPReply",
+			String:   "230 User logged in, proceed.",
+			Input:    &FTPReply{},
+			Expected: &FTPReply{Code: 230, Separator: " ", Text: "User logged in, proceed."},
+			Error:    nil,
+		},
+		{
+			Name:     "FTPReplyContinued",
+			String:   "211-Features:",
+			Input:    &FTPReply{},
+			Expected: &FTPReply{Code: 211, Separator: "-", Text: "Features:"},
+			Error:    nil,
+		},
+		{
+			Name:     "FTPReplyInvalidCode",
+			String:   "700 Unknown",
+			Input:    &FTPReply{},
+			Expected: &FTPReply{},
+			Error:    noMatch[FTPReply]("700 Unknown"),
+		},
+		{
+			Name:     "RESPError",
+			String:   "-ERR unknown command\r\n",
+			Input:    &RESPSimple{},
+			Expected: &RESPSimple{Type: "-", Value: "ERR unknown command"},
+			Error:    nil,
+		},
+		{
+			Name:     "RedisInlineCommand",
+			String:   "SET greeting hello",
+			Input:    &RedisInlineCommand{},
+			Expected: &RedisInlineCommand{Command: "SET", Args: []string{"greeting", "hello"}},
+			Error:    nil,
+		},
+		{
+			Name:     "RedisInlineCommandWithoutArgs",
+			String:   "PING\r",
+			Input:    &RedisInlineCommand{},
+			Expected: &RedisInlineCommand{Command: "PING"},
+			Error:    nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestRESPConcatenated(t *testing.T) {
+	replies, rest, err := structexp.ParseConcatenated[RESPSimple]("+OK\r\n:1000\r\n-ERR wrong type\r\n$5\r\nhello\r\n")
+	require.NoError(t, err)
+	assert.EqualValues(t, []RESPSimple{
+		{Type: "+", Value: "OK"},
+		{Type: ":", Value: "1000"},
+		{Type: "-", Value: "ERR wrong type"},
+	}, replies)
+	assert.EqualValues(t, "$5\r\nhello\r\n", rest)
+}
+
+func TestSMTPReplyLast(t *testing.T) {
+	var replies []SMTPReply
+	for _, line := range []string{"250-mail.example.com", "250-SIZE 35882577", "250 HELP"} {
+		reply, err := structexp.ParseAs[SMTPReply](line)
+		require.NoError(t, err)
+		replies = append(replies, reply)
+	}
+	assert.False(t, replies[0].Last())
+	assert.False(t, replies[1].Last())
+	assert.True(t, replies[2].Last())
+}
@@ -0,0 +1,104 @@
+package presets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/densestvoid/structexp"
+)
+
+// SSHKeyBlob is the binary public key of an OpenSSH key line,
+// parsed from its base64 encoding, such as AAAAC3NzaC1lZDI1NTE5AAAAI...
+type SSHKeyBlob []byte
+
+// Parse decodes the key from standard base64
+func (b *SSHKeyBlob) Parse(s string) error {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+func (b SSHKeyBlob) String() string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// SSHOptions are the comma separated options of an authorized_keys line,
+// such as no-pty,command="/usr/bin/backup",from="10.0.0.*", by name. Options
+// without a value, such as no-pty, have an empty value, and the quotes of
+// values are removed. An option given more than once, such as permitopen,
+// keeps its last value.
+type SSHOptions map[string]string
+
+// Parse parses the options, splitting them at the commas outside quotes
+func (o *SSHOptions) Parse(s string) error {
+	options := SSHOptions{}
+	for s != "" {
+		end, quoted := 0, false
+		for ; end < len(s) && (quoted || s[end] != ','); end++ {
+			switch s[end] {
+			case '\\':
+				end++
+			case '"':
+				quoted = !quoted
+			}
+		}
+		if quoted {
+			return fmt.Errorf("unterminated quote in option %s", s)
+		}
+		name, value, _ := strings.Cut(s[:min(end, len(s))], "=")
+		if strings.HasPrefix(value, `"`) {
+			var err error
+			if value, err = strconv.Unquote(value); err != nil {
+				return err
+			}
+		}
+		options[name] = value
+		s = s[min(end+1, len(s)):]
+	}
+	*o = options
+	return nil
+}
+
+func (o SSHOptions) String() string {
+	options := make([]string, 0, len(o))
+	for name, value := range o {
+		if value == "" {
+			options = append(options, name)
+			continue
+		}
+		options = append(options, name+"="+strconv.Quote(value))
+	}
+	sort.Strings(options)
+	return strings.Join(options, ",")
+}
+
+// AuthorizedKey is a line of an OpenSSH authorized_keys file, such as
+// from="10.0.0.*",no-pty ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... alice@laptop,
+// with its options and comment, if any
+type AuthorizedKey struct {
+	structexp.StructExp `structexp:"^(?:{{options}} )?{{type}} {{key}}(?: {{comment}})?$"`
+	Options             SSHOptions `structexp.name:"options" structexp.exp:"[A-Za-z-]+(?:=\"(?:[^\"\\\\]|\\\\.)*\")?(?:,[A-Za-z-]+(?:=\"(?:[^\"\\\\]|\\\\.)*\")?)*" structexp.optional:"true"`
+	Type                string     `structexp.name:"type" structexp.exp:"(?:ssh-[a-z0-9]+|ecdsa-sha2-nistp[0-9]+|sk-[a-z0-9-]+@openssh\\.com)(?:-cert-v01@openssh\\.com)?"`
+	Key                 SSHKeyBlob `structexp.name:"key" structexp.exp:"[A-Za-z0-9+/]+={0,2}"`
+	Comment             string     `structexp.name:"comment" structexp.exp:"[^\\r\\n]*"`
+}
+
+// KnownHost is a line of an OpenSSH known_hosts file, such as
+// github.com,140.82.121.4 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI..., with its
+// marker, @cert-authority or @revoked, and comment, if any. Hosts may be
+// patterns, such as *.example.com, include a port, such as [git.example.com]:2222,
+// or be hashed, such as |1|salt|hash.
+type KnownHost struct {
+	structexp.StructExp `structexp:"^(?:@{{marker}} )?{{hosts}} {{type}} {{key}}(?: {{comment}})?$"`
+	Marker              string     `structexp.name:"marker" structexp.exp:"cert-authority|revoked" structexp.optional:"true"`
+	Hosts               []string   `structexp.name:"hosts" structexp.exp:"[^ ,]+(?:,[^ ,]+)*" structexp.split:","`
+	Type                string     `structexp.name:"type" structexp.exp:"(?:ssh-[a-z0-9]+|ecdsa-sha2-nistp[0-9]+|sk-[a-z0-9-]+@openssh\\.com)(?:-cert-v01@openssh\\.com)?"`
+	Key                 SSHKeyBlob `structexp.name:"key" structexp.exp:"[A-Za-z0-9+/]+={0,2}"`
+	Comment             string     `structexp.name:"comment" structexp.exp:"[^\\r\\n]*"`
+}
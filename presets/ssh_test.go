@@ -0,0 +1,92 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSH(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	// The start of every ssh-ed25519 key blob: the length and name of the key type
+	key := SSHKeyBlob("\x00\x00\x00\x0bssh-ed25519")
+	testCases := []TestCase{
+		{
+			Name:     "AuthorizedKey",
+			String:   "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5 alice@laptop",
+			Input:    &AuthorizedKey{},
+			Expected: &AuthorizedKey{Type: "ssh-ed25519", Key: key, Comment: "alice@laptop"},
+			Error:    nil,
+		},
+		{
+			Name:   "AuthorizedKeyOptions",
+			String: `from="10.0.0.*",no-pty,command="echo \"hi, there\"" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5`,
+			Input:  &AuthorizedKey{},
+			Expected: &AuthorizedKey{
+				Options: SSHOptions{"from": "10.0.0.*", "no-pty": "", "command": `echo "hi, there"`},
+				Type:    "ssh-ed25519",
+				Key:     key,
+			},
+			Error: nil,
+		},
+		{
+			Name:     "AuthorizedKeyUnknownType",
+			String:   "ssh AAAAC3NzaC1lZDI1NTE5",
+			Input:    &AuthorizedKey{},
+			Expected: &AuthorizedKey{},
+			Error:    noMatch[AuthorizedKey]("ssh AAAAC3NzaC1lZDI1NTE5"),
+		},
+		{
+			Name:   "KnownHost",
+			String: "github.com,140.82.121.4 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5",
+			Input:  &KnownHost{},
+			Expected: &KnownHost{
+				Hosts: []string{"github.com", "140.82.121.4"},
+				Type:  "ssh-ed25519",
+				Key:   key,
+			},
+			Error: nil,
+		},
+		{
+			Name:   "KnownHostMarker",
+			String: "@cert-authority *.example.com,[git.example.com]:2222 ecdsa-sha2-nistp256 AAAAC3NzaC1lZDI1NTE5 ca",
+			Input:  &KnownHost{},
+			Expected: &KnownHost{
+				Marker:  "cert-authority",
+				Hosts:   []string{"*.example.com", "[git.example.com]:2222"},
+				Type:    "ecdsa-sha2-nistp256",
+				Key:     key,
+				Comment: "ca",
+			},
+			Error: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestSSHFormat(t *testing.T) {
+	s, err := structexp.Format(AuthorizedKey{
+		Options: SSHOptions{"no-pty": "", "from": "10.0.0.*"},
+		Type:    "ssh-ed25519",
+		Key:     SSHKeyBlob("\x00\x00\x00\x0bssh-ed25519"),
+		Comment: "alice@laptop",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `from="10.0.0.*",no-pty ssh-ed25519 AAAAC3NzaC1lZDI1NTE5 alice@laptop`, s)
+}
@@ -0,0 +1,53 @@
+package presets
+
+import "github.com/densestvoid/structexp"
+
+// IISLogEntry is an entry of an IIS log in the W3C extended format with the
+// default fields of IIS, in the order of its #Fields directive: date time
+// s-ip cs-method cs-uri-stem cs-uri-query s-port cs-username c-ip
+// cs(User-Agent) cs(Referer) sc-status sc-substatus sc-win32-status
+// time-taken. Absent values are logged, and parsed, as -, and the spaces of
+// the user agent are logged as +.
+type IISLogEntry struct {
+	structexp.StructExp `structexp:"^{{date}} {{time}} {{sip}} {{method}} {{stem}} {{query}} {{port}} {{user}} {{cip}} {{agent}} {{referer}} {{status}} {{substatus}} {{win32}} {{taken}}$"`
+	Date                string `structexp.name:"date" structexp.exp:"[0-9]{4}-[0-9]{2}-[0-9]{2}"`
+	Time                string `structexp.name:"time" structexp.exp:"[0-9]{2}:[0-9]{2}:[0-9]{2}"`
+	ServerIP            string `structexp.name:"sip" structexp.exp:"[^ ]+"`
+	Method              string `structexp.name:"method" structexp.exp:"[^ ]+"`
+	URIStem             string `structexp.name:"stem" structexp.exp:"[^ ]+"`
+	URIQuery            string `structexp.name:"query" structexp.exp:"[^ ]+"`
+	ServerPort          int    `structexp.name:"port"`
+	Username            string `structexp.name:"user" structexp.exp:"[^ ]+"`
+	ClientIP            string `structexp.name:"cip" structexp.exp:"[^ ]+"`
+	UserAgent           string `structexp.name:"agent" structexp.exp:"[^ ]+"`
+	Referer             string `structexp.name:"referer" structexp.exp:"[^ ]+"`
+	Status              int    `structexp.name:"status"`
+	SubStatus           int    `structexp.name:"substatus"`
+	Win32Status         int    `structexp.name:"win32"`
+	TimeTaken           int    `structexp.name:"taken"`
+}
+
+// IISDirective is a directive line of an IIS log in the W3C extended
+// format, such as #Fields: date time s-ip, or #Software: Microsoft Internet
+// Information Services 10.0
+type IISDirective struct {
+	structexp.StructExp `structexp:"^#{{name}}: ?{{value}}$"`
+	Name                string `structexp.name:"name" structexp.exp:"[A-Za-z-]+"`
+	Value               string `structexp.name:"value" structexp.exp:"[^\\r\\n]*"`
+}
+
+// WindowsEvent is a record of a Windows Event Log saved as tab delimited text
+// by Event Viewer, with the columns Level, Date and Time, Source, Event ID,
+// and Task Category, followed by the message of the event, such as
+// Information, 3/9/2024 10:15:42 AM, Service Control Manager, 7036, None, and
+// The Windows Update service entered the running state., separated by tabs.
+// The date and time are in the format of the locale of the exporting system.
+type WindowsEvent struct {
+	structexp.StructExp `structexp:"^{{level}}\\t{{time}}\\t{{source}}\\t{{id}}\\t{{task}}\\t{{message}}$"`
+	Level               string `structexp.name:"level" structexp.exp:"[^\\t]+"`
+	DateTime            string `structexp.name:"time" structexp.exp:"[^\\t]+"`
+	Source              string `structexp.name:"source" structexp.exp:"[^\\t]+"`
+	EventID             int    `structexp.name:"id"`
+	TaskCategory        string `structexp.name:"task" structexp.exp:"[^\\t]*"`
+	Message             string `structexp.name:"message" structexp.exp:"[^\\r\\n]*"`
+}
@@ -0,0 +1,81 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindows(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "IISLogEntry",
+			String: "2024-03-09 10:15:42 10.0.0.5 GET /index.html q=1 443 - 192.168.1.20 Mozilla/5.0+(Windows+NT+10.0) https://example.com/ 200 0 0 46",
+			Input:  &IISLogEntry{},
+			Expected: &IISLogEntry{
+				Date:        "2024-03-09",
+				Time:        "10:15:42",
+				ServerIP:    "10.0.0.5",
+				Method:      "GET",
+				URIStem:     "/index.html",
+				URIQuery:    "q=1",
+				ServerPort:  443,
+				Username:    "-",
+				ClientIP:    "192.168.1.20",
+				UserAgent:   "Mozilla/5.0+(Windows+NT+10.0)",
+				Referer:     "https://example.com/",
+				Status:      200,
+				SubStatus:   0,
+				Win32Status: 0,
+				TimeTaken:   46,
+			},
+			Error: nil,
+		},
+		{
+			Name:     "IISDirective",
+			String:   "#Fields: date time s-ip cs-method",
+			Input:    &IISDirective{},
+			Expected: &IISDirective{Name: "Fields", Value: "date time s-ip cs-method"},
+			Error:    nil,
+		},
+		{
+			Name:     "IISDirectiveIsNotEntry",
+			String:   "#Version: 1.0",
+			Input:    &IISLogEntry{},
+			Expected: &IISLogEntry{},
+			Error:    noMatch[IISLogEntry]("#Version: 1.0"),
+		},
+		{
+			Name:   "WindowsEvent",
+			String: "Information\t3/9/2024 10:15:42 AM\tService Control Manager\t7036\tNone\tThe Windows Update service entered the running state.",
+			Input:  &WindowsEvent{},
+			Expected: &WindowsEvent{
+				Level:        "Information",
+				DateTime:     "3/9/2024 10:15:42 AM",
+				Source:       "Service Control Manager",
+				EventID:      7036,
+				TaskCategory: "None",
+				Message:      "The Windows Update service entered the running state.",
+			},
+			Error: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := structexp.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
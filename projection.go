@@ -0,0 +1,62 @@
+package structexp // nolint:golint // in another file
+
+import "reflect"
+
+// Projection sets only the fields with the paths, such as Level or
+// Header.Code, still matching the whole regular expression. The other fields
+// keep their zero values, skipping their conversion, for hot paths needing a
+// few of the many fields of a template. A nested struct with its own
+// StructExp field is projected as a whole by its path.
+// Compile fails with an UnknownField error if a path names no field.
+func Projection(fields ...string) Option {
+	return func(o *options) {
+		o.projection = fields
+	}
+}
+
+// Project returns a Parser sharing the regular expression and statistics
+// of the Parser, setting only the fields with the paths, like Projection.
+// It is cheap enough to project the fields needed by each call.
+//
+// Errors occur if a path names no field of the Parser.
+func (p *Parser[T]) Project(fields ...string) (*Parser[T], error) {
+	projection, err := p.project(reflect.TypeOf((*T)(nil)).Elem(), fields)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser[T]{projection}, nil
+}
+
+// project returns a typeParser of the struct type setting only the fields with the paths, like Project
+func (p *typeParser) project(t reflect.Type, paths []string) (*typeParser, error) {
+	projected, err := projectFields(t, p.fields, paths)
+	if err != nil {
+		return nil, err
+	}
+	projection := *p
+	projection.fields = projected
+	projection.alternates = make([]*typeParser, len(p.alternates))
+	for i, alternate := range p.alternates {
+		if projection.alternates[i], err = alternate.project(t, paths); err != nil {
+			return nil, err
+		}
+	}
+	return &projection, nil
+}
+
+// projectFields returns the fields of the struct type with the paths
+func projectFields(t reflect.Type, fields []*field, paths []string) ([]*field, error) {
+	byPath := make(map[string]*field, len(fields))
+	for _, f := range fields {
+		byPath[fieldPath(t, f.Index)] = f
+	}
+	projected := make([]*field, 0, len(paths))
+	for _, path := range paths {
+		f, ok := byPath[path]
+		if !ok {
+			return nil, &UnknownField{path}
+		}
+		projected = append(projected, f)
+	}
+	return projected, nil
+}
@@ -0,0 +1,61 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjection(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Fields   []string
+		String   string
+		Expected ParentHeaderStruct
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "TopLevelField",
+			Fields:   []string{"Code"},
+			String:   "[WARN: 12] 34",
+			Expected: ParentHeaderStruct{Code: 34},
+		},
+		{
+			Name:     "NestedStructExp",
+			Fields:   []string{"Header"},
+			String:   "[WARN: 12] 34",
+			Expected: ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}},
+		},
+		{
+			Name:   "StillMatchesWholeTemplate",
+			Fields: []string{"Code"},
+			String: "34",
+			Error:  noMatch[ParentHeaderStruct]("34"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := MustCompile[ParentHeaderStruct](Projection(tc.Fields...)).Parse(tc.String)
+			assert.EqualValues(t, tc.Expected, v)
+			assert.EqualValues(t, tc.Error, err)
+
+			projected, err := MustCompile[ParentHeaderStruct]().Project(tc.Fields...)
+			assert.NoError(t, err)
+			v, err = projected.Parse(tc.String)
+			assert.EqualValues(t, tc.Expected, v)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestProjectionUnknownField(t *testing.T) {
+	_, err := Compile[ParentHeaderStruct](Projection("Header.Level"))
+	assert.EqualValues(t, &UnknownField{"Header.Level"}, err)
+
+	_, err = MustCompile[ParentHeaderStruct]().Project("Message")
+	assert.EqualValues(t, &UnknownField{"Message"}, err)
+}
@@ -0,0 +1,84 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// QuerySubParser parses the capture as a URL query string or form-encoded body
+const QuerySubParser = "query"
+
+func init() {
+	RegisterSubParser(QuerySubParser, ParseQuery)
+}
+
+// ParseQuery parses a URL query string or form-encoded body, such as
+// a=1&b=two%20words, into the argument, which must be the address of a
+// url.Values, a map[string]string, or a struct. A leading ? is ignored.
+// Struct fields are matched to keys the same way as ParseKeyValues matches
+// keys; for repeated keys, the last value is set.
+//
+// Errors occur if:
+//  - argument is not the address of a struct, url.Values, or map[string]string
+//  - a key or value is not correctly escaped
+//  - a key's value fails to parse into its field
+func ParseQuery(s string, i interface{}) error {
+	pairs, err := splitQuery(s)
+	if err != nil {
+		return err
+	}
+
+	if values, ok := i.(*url.Values); ok {
+		if *values == nil {
+			*values = url.Values{}
+		}
+		for _, pair := range pairs {
+			values.Add(pair.Key, pair.Value)
+		}
+		return nil
+	}
+	return assignKeyValues(pairs, i)
+}
+
+// splitQuery splits the query string into its unescaped pairs, in order
+func splitQuery(s string) ([]keyValue, error) {
+	var pairs []keyValue
+	for _, part := range strings.Split(strings.TrimPrefix(s, "?"), "&") {
+		if part == "" {
+			continue
+		}
+		key, value := part, ""
+		if i := strings.IndexByte(part, '='); i != -1 {
+			key, value = part[:i], part[i+1:]
+		}
+
+		var err error
+		if key, err = url.QueryUnescape(key); err != nil {
+			return nil, err
+		}
+		if value, err = url.QueryUnescape(value); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, keyValue{key, value})
+	}
+	return pairs, nil
+}
+
+// formatQuery renders the url.Values, map[string]string, or struct
+// value as a query string; the reverse of ParseQuery
+func formatQuery(v reflect.Value) (string, error) {
+	if values, ok := v.Interface().(url.Values); ok {
+		return values.Encode(), nil
+	}
+
+	pairs, err := listKeyValues(v)
+	if err != nil {
+		return "", err
+	}
+	values := make(url.Values, len(pairs))
+	for _, pair := range pairs {
+		values.Add(pair.Key, pair.Value)
+	}
+	return values.Encode(), nil
+}
@@ -0,0 +1,78 @@
+package structexp
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Query struct {
+	Page   int    `structexp.name:"page"`
+	Search string `structexp.name:"q"`
+}
+
+type AccessLog struct {
+	StructExp `structexp:"^GET {{path}}\\?{{query}} "`
+	Path      string `structexp.name:"path" structexp.exp:"[^? ]+"`
+	Query     Query  `structexp.name:"query" structexp.sub:"query" structexp.exp:"[^ ]*"`
+}
+
+func TestParseQuery(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Parse    func(string, interface{}) error
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    bool
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Values",
+			Parse:    ParseQuery,
+			String:   "?a=1&b=two%20words&a=3&c",
+			Input:    &url.Values{},
+			Expected: &url.Values{"a": {"1", "3"}, "b": {"two words"}, "c": {""}},
+		},
+		{
+			Name:     "Map",
+			Parse:    ParseQuery,
+			String:   "a=1&b=two+words&a=3",
+			Input:    &map[string]string{},
+			Expected: &map[string]string{"a": "3", "b": "two words"},
+		},
+		{
+			Name:     "Struct",
+			Parse:    ParseQuery,
+			String:   "page=2&q=go%26rust&other=x",
+			Input:    &Query{},
+			Expected: &Query{Page: 2, Search: "go&rust"},
+		},
+		{
+			Name:     "SubParser",
+			Parse:    Parse,
+			String:   "GET /search?q=regexp&page=3 HTTP/1.1",
+			Input:    &AccessLog{},
+			Expected: &AccessLog{Path: "/search", Query: Query{Page: 3, Search: "regexp"}},
+		},
+		{
+			Name:     "EscapeError",
+			Parse:    ParseQuery,
+			String:   "a=%zz",
+			Input:    &url.Values{},
+			Expected: &url.Values{},
+			Error:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.Equal(t, tc.Error, err != nil)
+		})
+	}
+}
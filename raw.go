@@ -0,0 +1,33 @@
+package structexp // nolint:golint // in another file
+
+import "reflect"
+
+// group returns the name of the capture group the field is set from:
+// its own, or that of the field whose raw text it keeps
+func (f field) group() string {
+	if f.Raw != "" {
+		return f.Raw
+	}
+	return f.CaptureGroupName
+}
+
+// checkRawGroups checks the structexp.raw tag of every
+// field of the struct type names another field's capture group
+func checkRawGroups(t reflect.Type, fields []*field) error {
+	for _, f := range fields {
+		if f.Raw == "" {
+			continue
+		}
+		found := f.Raw == WholeMatch
+		for _, other := range fields {
+			if other != f && other.Raw == "" && other.CaptureGroupName == f.Raw {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &UnknownRawGroup{fieldPath(t, f.Index), f.Raw}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package structexp
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Thousands is an int written with comma separated thousands, such as 1,234
+type Thousands int
+
+func (n *Thousands) Parse(s string) error {
+	i, err := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	*n = Thousands(i)
+	return err
+}
+
+type RawAmount struct {
+	StructExp  `structexp:"^{{name:quoted}} {{amount}}$"`
+	Name       string    `structexp.name:"name"`
+	QuotedName string    `structexp.raw:"name"`
+	Amount     Thousands `structexp.name:"amount" structexp.exp:"[0-9,]+"`
+	AmountText string    `structexp.raw:"amount"`
+}
+
+func TestRaw(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:   "raw text",
+			String: `"a \"b\"" 1,234`,
+			Input:  &RawAmount{},
+			Expected: &RawAmount{
+				Name:       `a "b"`,
+				QuotedName: `"a \"b\""`,
+				Amount:     1234,
+				AmountText: "1,234",
+			},
+			Error: nil,
+		},
+		{
+			Name:     "unknown group",
+			String:   "42",
+			Input:    &InvalidRawStruct{},
+			Expected: &InvalidRawStruct{},
+			Error:    &UnknownRawGroup{"CodeText", "cod"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestRawFormat(t *testing.T) {
+	s, err := Format(RawAmount{Name: "a", QuotedName: "ignored", Amount: 1234, AmountText: "ignored"})
+	require.NoError(t, err)
+	assert.EqualValues(t, `"a" 1234`, s)
+}
+
+func TestRawOffsets(t *testing.T) {
+	parser, err := Compile[RawAmount](Strict())
+	require.NoError(t, err)
+
+	_, offsets, err := parser.ParseWithOffsets(`"a" 1,234`)
+	require.NoError(t, err)
+	assert.EqualValues(t, []FieldOffset{
+		{"Name", "name", 0, 3},
+		{"QuotedName", "name", 0, 3},
+		{"Amount", "amount", 4, 9},
+		{"AmountText", "amount", 4, 9},
+	}, offsets)
+}
@@ -0,0 +1,101 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"reflect"
+)
+
+// readChunkSize is the size of the reads by AllMatches
+const readChunkSize = 32 << 10
+
+// AllMatches returns an iterator over the values of the struct type T parsed
+// from every match in the reader, like All, reading the input in chunks so
+// that a huge input is never fully in memory. Unlike AllReader, matches may
+// be anywhere in the input, including across lines.
+//
+// Matches are limited to bufio.MaxScanTokenSize bytes, or the MaxInputSize
+// option of the Parser, and longer matches yield bufio.ErrTooLong. As with
+// SplitFunc, a match is only parsed once more input cannot extend it.
+//
+// A match whose fields fail to parse yields its error, and iteration continues
+// with the next match. A read error is yielded last. If T cannot be compiled,
+// its error is yielded once.
+func AllMatches[T any](r io.Reader) iter.Seq2[T, error] {
+	p, err := Compile[T]()
+	if err != nil {
+		return yieldError[T](err)
+	}
+	return p.AllMatches(r)
+}
+
+// AllMatches returns an iterator over the values parsed from every match in the reader, like AllMatches
+func (p *Parser[T]) AllMatches(r io.Reader) iter.Seq2[T, error] {
+	maxMatch := bufio.MaxScanTokenSize
+	if p.opts.maxInputSize > 0 {
+		maxMatch = p.opts.maxInputSize
+	}
+
+	return func(yield func(T, error) bool) {
+		var buf []byte
+		chunk := make([]byte, readChunkSize)
+		for eof := false; !eof; {
+			n, err := io.ReadFull(r, chunk)
+			buf = append(buf, chunk[:n]...)
+			switch err {
+			case nil:
+			case io.EOF, io.ErrUnexpectedEOF:
+				eof = true
+			default:
+				var v T
+				yield(v, err)
+				return
+			}
+
+			keep, partial := 0, false
+			for _, loc := range p.all.FindAllSubmatchIndex(buf, -1) {
+				if loc[1] == len(buf) && !eof {
+					// More input could extend the match
+					keep, partial = loc[0], true
+					break
+				}
+				keep = loc[1]
+				if loc[0] == loc[1] {
+					continue
+				}
+				if !yield(p.parseMatch(buf, loc)) {
+					return
+				}
+			}
+			if eof {
+				return
+			}
+			// Keep the input that could start a match with more input
+			if !partial && keep < len(buf)-maxMatch {
+				keep = len(buf) - maxMatch
+			}
+			if len(buf)-keep > maxMatch {
+				var v T
+				yield(v, bufio.ErrTooLong)
+				return
+			}
+			if keep > 0 {
+				buf = append(buf[:0], buf[keep:]...)
+			}
+		}
+	}
+}
+
+// parseMatch returns a new T parsed from the submatch indexes in the bytes
+func (p *Parser[T]) parseMatch(b []byte, loc []int) (T, error) {
+	matches := make([]string, len(loc)/2)
+	for i := range matches {
+		if loc[2*i] >= 0 {
+			matches[i] = string(b[loc[2*i]:loc[2*i+1]])
+		}
+	}
+	var v T
+	err := setFields(reflect.ValueOf(&v).Elem(), p.all, p.fields, matches)
+	return v, err
+}
@@ -0,0 +1,64 @@
+package structexp
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllMatches(t *testing.T) {
+	// Spread the matches across many chunks, with junk between them
+	var input strings.Builder
+	var expected []Entry
+	for i := 0; i < 2000; i++ {
+		key := strings.Repeat("k", i%50+1)
+		input.WriteString(key + "=v; " + strings.Repeat(" ", i%97) + "\n")
+		expected = append(expected, Entry{Key: key, Value: "v"})
+	}
+
+	var values []Entry
+	for v, err := range AllMatches[Entry](strings.NewReader(input.String())) {
+		assert.NoError(t, err)
+		values = append(values, v)
+	}
+	assert.Equal(t, len(expected), len(values))
+	assert.EqualValues(t, expected, values)
+
+	values = nil
+	for v, err := range MustCompile[Entry]().AllMatches(iotest.OneByteReader(strings.NewReader("a=b;c=d;"))) {
+		assert.NoError(t, err)
+		values = append(values, v)
+	}
+	assert.EqualValues(t, []Entry{{Key: "a", Value: "b"}, {Key: "c", Value: "d"}}, values)
+}
+
+func TestAllMatchesErrors(t *testing.T) {
+	// A match filling the first chunk could be extended by the next
+	long := "a=" + strings.Repeat("v", readChunkSize-3) + ";" + "b=c;"
+	var errs []error
+	for _, err := range MustCompile[Entry](MaxInputSize(50)).AllMatches(strings.NewReader(long)) {
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []error{bufio.ErrTooLong}, errs)
+
+	errs = nil
+	for _, err := range MustCompile[Entry]().AllMatches(strings.NewReader(long)) {
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []error{nil, nil}, errs)
+
+	readErr := errors.New("read failed")
+	errs = nil
+	for _, err := range AllMatches[Entry](iotest.ErrReader(readErr)) {
+		errs = append(errs, err)
+	}
+	assert.Equal(t, []error{readErr}, errs)
+
+	for _, err := range AllMatches[MissingFieldStruct](strings.NewReader("")) {
+		assert.EqualValues(t, &MissingField{}, err)
+	}
+}
@@ -0,0 +1,128 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TemplateSource returns the current template of a ReloadableParser
+type TemplateSource func() (string, error)
+
+// FileTemplate reads the template from the file at the path,
+// without the trailing newline that editors add to files
+func FileTemplate(path string) TemplateSource {
+	return func() (string, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+}
+
+// ReloadableParser parses strings into the struct type T with a template
+// loaded from a TemplateSource, recompiling its Parser when the template
+// changes. Reloading swaps the Parser atomically: parses already in progress
+// finish with the Parser they started with, and a template that fails to
+// compile leaves the previous Parser in place.
+//
+// A ReloadableParser is safe for concurrent use by multiple goroutines.
+type ReloadableParser[T any] struct {
+	source TemplateSource
+	opts   []Option
+
+	// Serializes reloads
+	mu       sync.Mutex
+	template string
+	parser   atomic.Value
+}
+
+// NewReloadableParser compiles the Parser for the struct type T with the
+// template of the source, and the options applied to every reload.
+//
+// Errors occur if the source fails to return a template,
+// or the template fails to compile like Compile.
+func NewReloadableParser[T any](source TemplateSource, opts ...Option) (*ReloadableParser[T], error) {
+	r := &ReloadableParser[T]{
+		source: source,
+		opts:   opts,
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload compiles a new Parser if the template of the source has changed,
+// reporting whether it was swapped in. On an error the current Parser is kept.
+func (r *ReloadableParser[T]) Reload() (bool, error) {
+	template, err := r.source()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.parser.Load() != nil && template == r.template {
+		return false, nil
+	}
+	opts := append(append([]Option{}, r.opts...), WithTemplate(template))
+	p, err := Compile[T](opts...)
+	if err != nil {
+		return false, err
+	}
+	r.template = template
+	r.parser.Store(p)
+	return true, nil
+}
+
+// Watch reloads the template every interval until the context is done,
+// passing reload errors to the callback if it is not nil
+func (r *ReloadableParser[T]) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Template returns the template of the current Parser
+func (r *ReloadableParser[T]) Template() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.template
+}
+
+// Parser returns the current Parser. Callers parsing several strings
+// with the same template should use the returned Parser for all of them.
+func (r *ReloadableParser[T]) Parser() *Parser[T] {
+	return r.parser.Load().(*Parser[T])
+}
+
+// Parse returns a new T parsed from the string by the current Parser
+func (r *ReloadableParser[T]) Parse(s string) (T, error) {
+	return r.Parser().Parse(s)
+}
+
+// ParseAll returns a new T parsed from every match in the string by the current Parser
+func (r *ReloadableParser[T]) ParseAll(s string) ([]T, error) {
+	return r.Parser().ParseAll(s)
+}
+
+// Match reports whether the string matches the current Parser
+func (r *ReloadableParser[T]) Match(s string) bool {
+	return r.Parser().Match(s)
+}
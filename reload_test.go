@@ -0,0 +1,88 @@
+package structexp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTemplate(t *testing.T) {
+	parser, err := Compile[Header](WithTemplate(`^{{code}} {{level}}$`))
+	require.NoError(t, err)
+
+	value, err := parser.Parse("12 WARN")
+	require.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "WARN", Code: 12}, value)
+}
+
+func TestReloadableParser(t *testing.T) {
+	template := `^{{level}}: {{code}}$`
+	var sourceErr error
+	source := func() (string, error) { return template, sourceErr }
+
+	parser, err := NewReloadableParser[Header](source, RequireAnchors())
+	require.NoError(t, err)
+	initial := parser.Parser()
+
+	value, err := parser.Parse("WARN: 12")
+	require.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "WARN", Code: 12}, value)
+
+	reloaded, err := parser.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+	assert.Same(t, initial, parser.Parser())
+
+	template = `^{{code}} {{level}}$`
+	reloaded, err = parser.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, template, parser.Template())
+	assert.False(t, parser.Match("WARN: 12"))
+	value, err = parser.Parse("12 WARN")
+	require.NoError(t, err)
+	assert.EqualValues(t, Header{Level: "WARN", Code: 12}, value)
+
+	// Parses in progress keep the Parser they started with
+	_, err = initial.Parse("WARN: 12")
+	assert.NoError(t, err)
+
+	// Failing reloads keep the current Parser
+	current := parser.Parser()
+	template = `{{code}} {{level}}`
+	_, err = parser.Reload()
+	assert.EqualValues(t, &Unanchored{`(?P<code>[[:digit:]]+) (?P<level>[A-Z]+)`}, err)
+	assert.Same(t, current, parser.Parser())
+
+	sourceErr = errors.New("unavailable")
+	_, err = parser.Reload()
+	assert.Equal(t, sourceErr, err)
+	assert.Same(t, current, parser.Parser())
+}
+
+func TestReloadableParserWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "template")
+	require.NoError(t, os.WriteFile(path, []byte("^{{level}}: {{code}}\n"), 0o600))
+
+	parser, err := NewReloadableParser[Header](FileTemplate(path))
+	require.NoError(t, err)
+	assert.Equal(t, `^{{level}}: {{code}}`, parser.Template())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go parser.Watch(ctx, time.Millisecond, nil)
+
+	require.NoError(t, os.WriteFile(path, []byte("^{{code}} {{level}}\n"), 0o600))
+	assert.Eventually(t, func() bool { return parser.Match("12 WARN") }, time.Second, time.Millisecond)
+}
+
+func TestNewReloadableParserError(t *testing.T) {
+	_, err := NewReloadableParser[Header](FileTemplate(filepath.Join(t.TempDir(), "missing")))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,123 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+)
+
+// ParseResult is the outcome of parsing one record, with what is known
+// about how its value was parsed besides the value itself
+type ParseResult[T any] struct {
+	// Value is the parsed value, which may be partially set on an error
+	Value T
+	// Fields are the paths of the fields set from a submatch, like the
+	// Field of each FieldOffset, leaving out fields keeping their zero value
+	Fields []string
+	// Offsets are the byte offsets of each field's submatch, like ParseWithOffsets
+	Offsets []FieldOffset
+	// Provenance describes the definition and input that produced the value
+	Provenance Provenance
+	// Warnings are the problems that did not fail the parse
+	Warnings []error
+	// Err is the error that failed the parse, if any
+	Err error
+}
+
+// Provenance describes where the value of a ParseResult came from
+type Provenance struct {
+	// Pattern is the regular expression of the Parser
+	Pattern string
+	// PatternIndex is the index of the template of the struct type that
+	// matched, when it has several, such as several StructExp fields
+	PatternIndex int
+	// Variant is the key given to WithVariant, if any
+	Variant string
+	// JSON reports whether the record was a JSON object parsed with the SniffJSON option
+	JSON bool
+	// Start and End are the byte offsets of the record's match in the input
+	Start, End int
+}
+
+// ParseResult returns the ParseResult of parsing the string into a new T,
+// like Parse. Errors are returned in the result's Err, including those
+// from the same cases as Parse.
+func (p *Parser[T]) ParseResult(s string) (r ParseResult[T]) {
+	s = p.input(s)
+	c, index, loc := p.candidate(s)
+	r.Provenance = c.provenance(0, len(s))
+	r.Provenance.PatternIndex = index
+	if r.Err = checkInputSize(len(s), p.opts); r.Err != nil {
+		return r
+	}
+	start := p.stats.start()
+	defer func() { p.stats.observe(start, r.Err) }()
+
+	if p.opts.sniffJSON && isJSONObject(s) {
+		r.Provenance.JSON = true
+		r.Err = json.Unmarshal([]byte(s), &r.Value)
+		return r
+	}
+	if loc == nil {
+		r.Err = newNoMatch(c.regexp, s)
+		return r
+	}
+	p.setResult(&r, c.regexp, c.fields, s, loc)
+	return r
+}
+
+// ParseAllResults returns the ParseResult of parsing every match in the
+// string into a new T, like ParseAll. Unlike ParseAll, a match whose fields
+// fail to parse does not stop the matches after it. If the string exceeds
+// the MaxInputSize option, a single result with the error is returned.
+func (p *Parser[T]) ParseAllResults(s string) []ParseResult[T] {
+	s = p.input(s)
+	if err := checkInputSize(len(s), p.opts); err != nil {
+		return []ParseResult[T]{{Provenance: p.provenance(0, len(s)), Err: err}}
+	}
+	start := p.stats.start()
+	var err error
+	defer func() { p.stats.observe(start, err) }()
+
+	allLocs := findAllSubmatchIndex(p.all, p.scanned, s)
+	results := make([]ParseResult[T], len(allLocs))
+	for i, loc := range allLocs {
+		results[i].Provenance = p.provenance(loc[0], loc[1])
+		p.setResult(&results[i], p.all, p.fields, s, loc)
+		if err == nil {
+			err = results[i].Err
+		}
+	}
+	return results
+}
+
+func (p *typeParser) provenance(start, end int) Provenance {
+	return Provenance{
+		Pattern: p.regexp.String(),
+		Variant: p.opts.variant,
+		Start:   start,
+		End:     end,
+	}
+}
+
+// setResult sets the result from the submatch indexes of the regular
+// expression in the string, for the fields of the template it belongs to
+func (p *Parser[T]) setResult(r *ParseResult[T], regxp *regexp.Regexp, fields []*field, s string, loc []int) {
+	r.Provenance.Start, r.Provenance.End = loc[0], loc[1]
+	matches := make([]string, len(loc)/2)
+	for i := range matches {
+		if loc[2*i] >= 0 {
+			matches[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	v := reflect.ValueOf(&r.Value).Elem()
+	if r.Err = setFields(v, regxp, fields, matches); r.Err != nil {
+		return
+	}
+	r.Warnings = matchWarnings(v.Type(), regxp, fields, s, loc)
+	p.warn(r.Warnings)
+	r.Offsets = matchOffsets(v.Type(), regxp, fields, s, loc, 0, "")
+	for _, offset := range r.Offsets {
+		r.Fields = append(r.Fields, offset.Field)
+	}
+}
@@ -0,0 +1,87 @@
+package structexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResult(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		String   string
+		Expected ParseResult[Header]
+	}
+
+	pattern := MustCompile[Header]().Regexp().String()
+	testCases := []TestCase{
+		{
+			Name:   "Match",
+			String: "INFO: 3",
+			Expected: ParseResult[Header]{
+				Value:  Header{Level: "INFO", Code: 3},
+				Fields: []string{"Level", "Code"},
+				Offsets: []FieldOffset{
+					{Field: "Level", Group: "level", Start: 0, End: 4},
+					{Field: "Code", Group: "code", Start: 6, End: 7},
+				},
+				Provenance: Provenance{Pattern: pattern, Start: 0, End: 7},
+			},
+		},
+		{
+			Name:   "NoMatch",
+			String: "INFO",
+			Expected: ParseResult[Header]{
+				Provenance: Provenance{Pattern: pattern, Start: 0, End: 4},
+				Err:        noMatch[Header]("INFO"),
+			},
+		},
+		{
+			Name:    "JSON",
+			Options: []Option{SniffJSON()},
+			String:  `{"Level": "WARN", "Code": 1}`,
+			Expected: ParseResult[Header]{
+				Value:      Header{Level: "WARN", Code: 1},
+				Provenance: Provenance{Pattern: pattern, JSON: true, Start: 0, End: 28},
+			},
+		},
+		{
+			Name:    "InputTooLarge",
+			Options: []Option{MaxInputSize(3)},
+			String:  "INFO: 3",
+			Expected: ParseResult[Header]{
+				Provenance: Provenance{Pattern: pattern, Start: 0, End: 7},
+				Err:        &InputTooLarge{7, 3},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			result := MustCompile[Header](tc.Options...).ParseResult(tc.String)
+			assert.EqualValues(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestParseAllResults(t *testing.T) {
+	var parses int
+	parser := MustCompile[Header](WithMetricsHook(func(time.Duration, error) { parses++ }))
+	results := parser.ParseAllResults("WARN: 99999999999999999999 INFO: 1")
+	assert.Len(t, results, 2)
+	assert.Error(t, results[0].Err)
+	assert.EqualValues(t, Provenance{Pattern: parser.Regexp().String(), Start: 0, End: 26}, results[0].Provenance)
+	assert.EqualValues(t, ParseResult[Header]{
+		Value:  Header{Level: "INFO", Code: 1},
+		Fields: []string{"Level", "Code"},
+		Offsets: []FieldOffset{
+			{Field: "Level", Group: "level", Start: 27, End: 31},
+			{Field: "Code", Group: "code", Start: 33, End: 34},
+		},
+		Provenance: Provenance{Pattern: parser.Regexp().String(), Start: 27, End: 34},
+	}, results[1])
+	assert.EqualValues(t, 1, parses)
+}
@@ -0,0 +1,162 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"io"
+)
+
+// Position is the location of a record in the input of a Scanner or Decoder
+type Position struct {
+	// Line is the 1-based line number
+	Line int
+	// Column is the 1-based byte offset from the start of the line
+	Column int
+	// Offset is the 0-based byte offset from the start of the input
+	Offset int
+	// Record is the 1-based number of the record in the input
+	Record int
+}
+
+// advance returns the position after the bytes
+func (pos Position) advance(b []byte) Position {
+	for _, c := range b {
+		if c == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	pos.Offset += len(b)
+	return pos
+}
+
+// recordPositions tracks the Position of the records split from an input
+type recordPositions struct {
+	// Position of the unread input, counting the records before it, and of the current record
+	pos    Position
+	record Position
+}
+
+// newRecordPositions returns the recordPositions of an input not yet read
+func newRecordPositions() *recordPositions {
+	return &recordPositions{pos: Position{Line: 1, Column: 1}}
+}
+
+// split wraps the split function, tracking the Position of each record it splits
+func (p *recordPositions) split(split bufio.SplitFunc) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := split(data, atEOF)
+		if token != nil {
+			p.record = p.pos.advance(data[:tokenStart(data, token)])
+			p.pos.Record++
+			p.record.Record = p.pos.Record
+		}
+		if advance > 0 {
+			p.pos = p.pos.advance(data[:advance])
+		}
+		return advance, token, err
+	}
+}
+
+// Scanner reads records from an input stream, like Decoder, parsing each
+// into a new T and tracking the Position where it starts, to report the bad
+// records of large inputs. Records are lines by default; see Scanner.Split.
+//
+// Scanning stops at the end of the input or a read error, but not at a
+// record that fails to parse, whose error is returned by Record instead.
+// With the SkipNoMatch option of the Parser, records that do not match
+// are skipped.
+type Scanner[T any] struct {
+	parser    *Parser[T]
+	scanner   *bufio.Scanner
+	positions *recordPositions
+
+	value T
+	err   error
+}
+
+// NewScanner compiles a Parser for the struct type T with
+// the options, and returns a Scanner of the reader using it
+func NewScanner[T any](r io.Reader, opts ...Option) (*Scanner[T], error) {
+	p, err := Compile[T](opts...)
+	if err != nil {
+		return nil, err
+	}
+	return p.Scanner(r), nil
+}
+
+// Scanner returns a Scanner parsing the records of the reader with the Parser
+func (p *Parser[T]) Scanner(r io.Reader) *Scanner[T] {
+	s := &Scanner[T]{
+		parser:    p,
+		scanner:   bufio.NewScanner(r),
+		positions: newRecordPositions(),
+	}
+	s.Split(bufio.ScanLines)
+	return s
+}
+
+// Split sets the split function tokenizing the input stream into records.
+// It must be called before the first call to Scan.
+func (s *Scanner[T]) Split(split bufio.SplitFunc) {
+	s.scanner.Split(s.positions.split(split))
+}
+
+// tokenStart returns the offset of the token in the data, or 0 if the
+// token was not sliced from the data, such as a split function's copy
+func tokenStart(data, token []byte) int {
+	if len(token) == 0 || cap(token) > cap(data) {
+		return 0
+	}
+	start := cap(data) - cap(token)
+	if start > len(data) || &data[:cap(data)][start] != &token[0] {
+		return 0
+	}
+	return start
+}
+
+// Buffer sets the initial buffer and maximum record size of the scanner,
+// like bufio.Scanner.Buffer. It must be called before the first call to Scan.
+func (s *Scanner[T]) Buffer(buf []byte, max int) {
+	s.scanner.Buffer(buf, max)
+}
+
+// Scan reads and parses the next record, for Record and Position to return.
+// It returns false at the end of the input or a read error, returned by Err.
+func (s *Scanner[T]) Scan() bool {
+	for s.scanner.Scan() {
+		s.value, s.err = s.parser.Parse(s.scanner.Text())
+		if s.parser.opts.skipNoMatch && isNoMatch(s.err) {
+			continue
+		}
+		if s.err != nil {
+			s.err = &PositionError{s.positions.record, s.err}
+		}
+		return true
+	}
+	var v T
+	s.value, s.err = v, nil
+	return false
+}
+
+// Record returns the value parsed from the current record,
+// or its error as a PositionError
+func (s *Scanner[T]) Record() (T, error) {
+	return s.value, s.err
+}
+
+// Text returns the text of the current record
+func (s *Scanner[T]) Text() string {
+	return s.scanner.Text()
+}
+
+// Position returns the position where the current record starts
+func (s *Scanner[T]) Position() Position {
+	return s.positions.record
+}
+
+// Err returns the first read error of the scanner, or nil at the end of the input
+func (s *Scanner[T]) Err() error {
+	return s.scanner.Err()
+}
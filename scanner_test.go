@@ -0,0 +1,88 @@
+package structexp
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanner(t *testing.T) {
+	type TestCase struct {
+		Name      string
+		String    string
+		Split     bufio.SplitFunc
+		Options   []Option
+		Values    []Int
+		Positions []Position
+		Errors    []error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:      "Lines",
+			String:    "1\n22\r\na\n3",
+			Values:    []Int{{Value: 1}, {Value: 22}, {}, {Value: 3}},
+			Positions: []Position{{1, 1, 0, 1}, {2, 1, 2, 2}, {3, 1, 6, 3}, {4, 1, 8, 4}},
+			Errors:    []error{nil, nil, &PositionError{Position{3, 1, 6, 3}, noMatch[Int]("a")}, nil},
+		},
+		{
+			Name:      "Words",
+			String:    "1  22\n  a 3",
+			Split:     bufio.ScanWords,
+			Values:    []Int{{Value: 1}, {Value: 22}, {}, {Value: 3}},
+			Positions: []Position{{1, 1, 0, 1}, {1, 4, 3, 2}, {2, 3, 8, 3}, {2, 5, 10, 4}},
+			Errors:    []error{nil, nil, &PositionError{Position{2, 3, 8, 3}, noMatch[Int]("a")}, nil},
+		},
+		{
+			Name:      "SkipNoMatch",
+			String:    "1\na\n3\n",
+			Options:   []Option{SkipNoMatch()},
+			Values:    []Int{{Value: 1}, {Value: 3}},
+			Positions: []Position{{1, 1, 0, 1}, {3, 1, 4, 3}},
+			Errors:    []error{nil, nil},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			scanner, err := NewScanner[Int](strings.NewReader(tc.String), tc.Options...)
+			assert.NoError(t, err)
+			if tc.Split != nil {
+				scanner.Split(tc.Split)
+			}
+
+			var values []Int
+			var positions []Position
+			var errs []error
+			for scanner.Scan() {
+				v, err := scanner.Record()
+				values = append(values, v)
+				positions = append(positions, scanner.Position())
+				errs = append(errs, err)
+			}
+			assert.NoError(t, scanner.Err())
+			assert.EqualValues(t, tc.Values, values)
+			assert.EqualValues(t, tc.Positions, positions)
+			assert.EqualValues(t, tc.Errors, errs)
+		})
+	}
+}
+
+func TestScannerErrors(t *testing.T) {
+	_, err := NewScanner[MissingFieldStruct](strings.NewReader(""))
+	assert.EqualValues(t, &MissingField{}, err)
+
+	readErr := errors.New("read failed")
+	scanner := MustCompile[Int]().Scanner(iotest.ErrReader(readErr))
+	assert.False(t, scanner.Scan())
+	assert.EqualValues(t, readErr, scanner.Err())
+
+	err = &PositionError{Position{3, 5, 20, 2}, &NoMatch{}}
+	assert.EqualValues(t, "record 2, line 3, column 5, offset 20: "+(&NoMatch{}).Error(), err.Error())
+	assert.True(t, errors.Is(err, err.(*PositionError).Err))
+}
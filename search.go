@@ -0,0 +1,60 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// searcher finds the matches of a regular expression after a position in a
+// string, like the searches of regexp's FindAll methods. Searching the rest
+// of the string instead would lose the text before the position, which
+// assertions such as \b, \B, and (?m)^ look at, and \A would match again.
+type searcher struct {
+	regexp *regexp.Regexp
+
+	once sync.Once
+	// The regular expression, captured after the rune before the position and
+	// anything else, so that it only matches after that rune, with it as context
+	after *regexp.Regexp
+}
+
+// newSearcher returns the searcher of the regular expression
+func newSearcher(regxp *regexp.Regexp) *searcher {
+	return &searcher{regexp: regxp}
+}
+
+// find returns the location of the first match starting at or after the
+// position in the string, as from FindStringSubmatchIndex on the string
+func (sr *searcher) find(s string, pos int) []int {
+	if pos == 0 {
+		return sr.regexp.FindStringSubmatchIndex(s)
+	}
+	if pos > len(s) {
+		return nil
+	}
+	sr.once.Do(func() {
+		// Compiled from an expression that compiled, with no other group
+		sr.after = regexp.MustCompile(`\A(?s:.)(?s:.*?)(` + sr.regexp.String() + `)`)
+	})
+	_, width := utf8.DecodeLastRuneInString(s[:pos])
+	from := pos - width
+	loc := sr.after.FindStringSubmatchIndex(s[from:])
+	if loc == nil {
+		return nil
+	}
+	// The whole match is that of the capture
+	loc = loc[2:]
+	for i := range loc {
+		if loc[i] >= 0 {
+			loc[i] += from
+		}
+	}
+	return loc
+}
+
+// findScanned returns the location of the first match starting at or after
+// the position in the string, like find, scanning the scanned fields
+func (sr *searcher) findScanned(scanned []*field, s string, pos int) []int {
+	return scanMatch(sr, scanned, s, pos, sr.find(s, pos))
+}
@@ -0,0 +1,53 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// SplitFunc returns a split function for a bufio.Scanner, or a Decoder,
+// tokenizing a stream into the matches of the regular expression of the
+// struct type T, so that streams that are not newline delimited can be parsed.
+// Input between matches is skipped, like ParseAll.
+//
+// Errors occur if T cannot be compiled, like Compile.
+func SplitFunc[T any]() (bufio.SplitFunc, error) {
+	p, err := Compile[T]()
+	if err != nil {
+		return nil, err
+	}
+	return p.SplitFunc(), nil
+}
+
+// SplitFunc returns a split function tokenizing a stream into the matches of
+// the Parser's regular expression, like SplitFunc. A match reaching the end of
+// the buffered input is only returned once more input cannot extend it, and
+// input without a match is buffered until one is found, so both are limited
+// by the scanner's maximum token size.
+func (p *Parser[T]) SplitFunc() bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		loc := p.all.FindIndex(data)
+		if loc == nil {
+			if atEOF {
+				// Skip the remaining input that does not match
+				return len(data), nil, nil
+			}
+			return 0, nil, nil
+		}
+		start, end := loc[0], loc[1]
+		if end == len(data) && !atEOF {
+			// More input could extend the match
+			return 0, nil, nil
+		}
+		if start == end {
+			// Skip empty matches, which are not records
+			_, width := utf8.DecodeRune(data[end:])
+			return end + width, nil, nil
+		}
+		return end, data[start:end], nil
+	}
+}
@@ -0,0 +1,46 @@
+package structexp
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFunc(t *testing.T) {
+	split, err := SplitFunc[Entry]()
+	require.NoError(t, err)
+
+	scanner := bufio.NewScanner(iotest.OneByteReader(strings.NewReader("a=b;c=d; junk e=f;")))
+	scanner.Split(split)
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []string{"a=b;", "c=d;", "e=f;"}, tokens)
+
+	_, err = SplitFunc[MissingFieldStruct]()
+	assert.EqualValues(t, &MissingField{}, err)
+}
+
+func TestSplitFuncDecoder(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("WARN: 12WARN: 3 INFO: 4"))
+	decoder.Split(MustCompile[Header]().SplitFunc())
+
+	var values []Header
+	for {
+		var value Header
+		err := decoder.Decode(&value)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+	assert.EqualValues(t, []Header{{Level: "WARN", Code: 12}, {Level: "WARN", Code: 3}, {Level: "INFO", Code: 4}}, values)
+}
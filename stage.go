@@ -0,0 +1,118 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"errors"
+	"fmt"
+	"regexp/syntax"
+)
+
+// Stage is the stage of parsing at which an error occurred
+type Stage int
+
+// Stages of parsing, in order
+const (
+	// StageUnknown is the stage of a nil error
+	StageUnknown Stage = iota
+	// StageValidate is checking a struct definition, with Validate
+	StageValidate
+	// StageCompile is building and compiling the regular expression of a struct
+	StageCompile
+	// StageMatch is matching the regular expression to the input
+	StageMatch
+	// StageConvert is setting the fields from the submatches
+	StageConvert
+)
+
+func (s Stage) String() string {
+	switch s {
+	case StageValidate:
+		return "validate"
+	case StageCompile:
+		return "compile"
+	case StageMatch:
+		return "match"
+	case StageConvert:
+		return "convert"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseError wraps an error of the package with the stage it occurred at, so
+// that callers can handle the failures of each stage differently. The errors
+// of the package wrap their causes, such as the errors of strconv and of
+// regexp.Compile, for errors.Is and errors.As.
+type ParseError struct {
+	Stage Stage
+	Err   error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("%v: %v", err.Stage, err.Err)
+}
+
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+// Staged wraps the error in a ParseError with its stage, like StageOf,
+// returning nil for a nil error and a ParseError unchanged
+func Staged(err error) error {
+	if err == nil {
+		return nil
+	}
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+	return &ParseError{StageOf(err), err}
+}
+
+// StageOf returns the stage at which the error occurred, from the stage of a
+// ParseError it wraps, or the type of the errors it wraps, the first found in
+// the tree of wrapped errors as by errors.As. A FieldError is converting a
+// field, even if it wraps the NoMatch of a nested struct field. Errors of
+// unknown types, such as those of ParsableField types, are converting a field.
+func StageOf(err error) Stage {
+	if err == nil {
+		return StageUnknown
+	}
+	if stage, ok := wrappedStage(err); ok {
+		return stage
+	}
+	return StageConvert
+}
+
+// wrappedStage returns the stage of the first error in the tree of the
+// error whose stage is known, and whether there is one
+func wrappedStage(err error) (Stage, bool) {
+	switch e := err.(type) {
+	case *ParseError:
+		return e.Stage, true
+	case *InvalidDefinition, *InvalidTag:
+		return StageValidate, true
+	case *NotStruct, *NotSlice, *MissingField, *UnknownToken, *UnknownSubParser, *UnknownTransform,
+		*UnknownVariant, *UnknownGroup, *UnknownPlaceholder, *DuplicateGroup, *UnknownIndex, *UnknownRawGroup,
+		*UnplacedField, *InvalidDelimiters, *IncludeError, *IncludeCycle, *TooComplex,
+		*BannedConstruct, *Unanchored, *UnknownField, *MultipleFallbacks, *syntax.Error:
+		return StageCompile, true
+	case *NoMatch, *InputTooLarge:
+		return StageMatch, true
+	case *FieldError:
+		return StageConvert, true
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if wrapped := e.Unwrap(); wrapped != nil {
+			return wrappedStage(wrapped)
+		}
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if stage, ok := wrappedStage(wrapped); ok {
+				return stage, true
+			}
+		}
+	}
+	return StageUnknown, false
+}
@@ -0,0 +1,64 @@
+package structexp
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStageOf(t *testing.T) {
+	_, compileErr := Compile[Header](WithTemplate(`^({{level}}$`))
+	_, matchErr := ParseAs[Header]("warn")
+	_, convertErr := ParseAs[Int]("99999999999999999999")
+	_, nestedErr := ParseAs[LooseHeaderStruct]("[oops] 3")
+	_, joinedErr := MustCompile[DirtyStruct](JoinFieldErrors()).Parse("disk many yes 12")
+
+	type TestCase struct {
+		Name  string
+		Error error
+		Stage Stage
+	}
+
+	testCases := []TestCase{
+		{Name: "Nil", Error: nil, Stage: StageUnknown},
+		{Name: "Validate", Error: Validate(TypoStruct{}), Stage: StageValidate},
+		{Name: "MissingField", Error: Validate(MissingFieldStruct{}), Stage: StageValidate},
+		{Name: "Compile", Error: compileErr, Stage: StageCompile},
+		{Name: "Match", Error: matchErr, Stage: StageMatch},
+		{Name: "Convert", Error: convertErr, Stage: StageConvert},
+		{Name: "Wrapped", Error: &LineError{3, 10, matchErr}, Stage: StageMatch},
+		{Name: "NestedNoMatch", Error: nestedErr, Stage: StageConvert},
+		{Name: "JoinedFields", Error: joinedErr, Stage: StageConvert},
+		{Name: "Joined", Error: errors.Join(errors.New("custom"), &LineError{3, 10, matchErr}), Stage: StageMatch},
+		{Name: "ParseError", Error: &ParseError{StageCompile, matchErr}, Stage: StageCompile},
+		{Name: "Unknown", Error: errors.New("custom"), Stage: StageConvert},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Stage, StageOf(tc.Error))
+		})
+	}
+}
+
+func TestStaged(t *testing.T) {
+	assert.NoError(t, Staged(nil))
+
+	_, err := ParseAs[Int]("99999999999999999999")
+	err = Staged(err)
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, StageConvert, parseErr.Stage)
+	assert.ErrorIs(t, err, strconv.ErrRange)
+	assert.Equal(t, "convert: "+parseErr.Err.Error(), err.Error())
+	assert.Same(t, err, Staged(err))
+
+	err = Staged(Validate(InvalidDefaultStruct{}))
+	var numErr *strconv.NumError
+	require.ErrorAs(t, err, &numErr)
+	assert.Equal(t, "none", numErr.Num)
+}
@@ -0,0 +1,77 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// MetricsHook is called after every parse by a Parser compiled with
+// WithMetricsHook, with the time it took and the error it returned
+type MetricsHook func(d time.Duration, err error)
+
+// WithMetricsHook times every parse by the Parser, reporting each to the hook
+// and the average to Stats. Parsers are not timed without it.
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(o *options) {
+		o.metricsHook = hook
+	}
+}
+
+// Stats describes the size and performance of a Parser,
+// for capacity planning of services holding many Parsers
+type Stats struct {
+	// Groups is the number of capture groups in the regular expression
+	Groups int
+	// ProgramSize is the number of instructions in the compiled program of the
+	// regular expression, which the memory used by each match is proportional to
+	ProgramSize int
+	// Fields is the number of fields set by each parse
+	Fields int
+	// Parses is the number of timed parses, zero without WithMetricsHook
+	Parses int64
+	// AverageParseTime is the average time of the timed parses
+	AverageParseTime time.Duration
+}
+
+// parserStats accumulates the timed parses of a Parser
+type parserStats struct {
+	// Accessed atomically, first for 64-bit alignment
+	parses int64
+	total  int64
+
+	hook        MetricsHook
+	programSize int
+}
+
+// start returns the start time of a parse, or the zero time if parses are not timed
+func (s *parserStats) start() time.Time {
+	if s.hook == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// observe records a parse begun at start, if parses are timed
+func (s *parserStats) observe(start time.Time, err error) {
+	if s.hook == nil {
+		return
+	}
+	d := time.Since(start)
+	atomic.AddInt64(&s.parses, 1)
+	atomic.AddInt64(&s.total, int64(d))
+	s.hook(d, err)
+}
+
+// Stats returns the statistics of the Parser. It is safe to call while parsing.
+func (p *Parser[T]) Stats() Stats {
+	stats := Stats{
+		Groups:      p.regexp.NumSubexp(),
+		ProgramSize: p.stats.programSize,
+		Fields:      len(p.fields),
+		Parses:      atomic.LoadInt64(&p.stats.parses),
+	}
+	if stats.Parses > 0 {
+		stats.AverageParseTime = time.Duration(atomic.LoadInt64(&p.stats.total) / stats.Parses)
+	}
+	return stats
+}
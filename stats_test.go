@@ -0,0 +1,30 @@
+package structexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	parser := MustCompile[ParentHeaderStruct]()
+	_, _ = parser.Parse("[WARN: 12] 34")
+	assert.EqualValues(t, Stats{Groups: 2, ProgramSize: 19, Fields: 2}, parser.Stats())
+}
+
+func TestStatsMetricsHook(t *testing.T) {
+	var errs []error
+	parser := MustCompile[ParentHeaderStruct](WithMetricsHook(func(d time.Duration, err error) {
+		errs = append(errs, err)
+	}))
+
+	_, _ = parser.Parse("[WARN: 12] 34")
+	_, _ = parser.ParseBytes([]byte("a"))
+	_, _ = parser.ParseAll("[WARN: 12] 34 [INFO: 1] 2")
+
+	assert.EqualValues(t, []error{nil, noMatch[ParentHeaderStruct]("a"), nil}, errs)
+	stats := parser.Stats()
+	assert.EqualValues(t, 3, stats.Parses)
+	assert.Greater(t, stats.AverageParseTime, time.Duration(0))
+}
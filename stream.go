@@ -0,0 +1,71 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// ParseStream parses every line read from the reader into a new T on a new
+// goroutine, and delivers them in order on the returned values channel.
+// The goroutine only reads ahead while a value is waiting to be received,
+// so a slow consumer naturally slows reading down.
+//
+// Errors are delivered on the returned errors channel: an error compiling T
+// alone, each line that fails to parse as a LineError, unless skipped with
+// SkipNoMatch, and a read error last. When the input ends or the context is
+// done, the values channel and then the errors channel are closed; the
+// context's error is not delivered. Both channels must be received from
+// until closed, or the context canceled, for the goroutine to end.
+func ParseStream[T any](ctx context.Context, r io.Reader, opts ...Option) (<-chan T, <-chan error) {
+	values := make(chan T)
+	errs := make(chan error)
+
+	p, err := Compile[T](opts...)
+	if err != nil {
+		go func() {
+			defer close(errs)
+			close(values)
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+		}()
+		return values, errs
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(values)
+
+		scanner := bufio.NewScanner(r)
+		positions := newRecordPositions()
+		scanner.Split(positions.split(bufio.ScanLines))
+		for scanner.Scan() {
+			v, err := p.Parse(scanner.Text())
+			if err != nil {
+				if p.opts.skipNoMatch && isNoMatch(err) {
+					continue
+				}
+				select {
+				case errs <- &LineError{Line: positions.record.Line, Offset: positions.record.Offset, Err: err}:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case values <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return values, errs
+}
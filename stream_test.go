@@ -0,0 +1,99 @@
+package structexp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receiveStream receives from both channels of ParseStream until they are closed
+func receiveStream[T any](values <-chan T, errs <-chan error) ([]T, []error) {
+	var vs []T
+	var es []error
+	for values != nil || errs != nil {
+		select {
+		case v, ok := <-values:
+			if !ok {
+				values = nil
+				continue
+			}
+			vs = append(vs, v)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			es = append(es, err)
+		}
+	}
+	return vs, es
+}
+
+func TestParseStream(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Reader   io.Reader
+		Options  []Option
+		Expected []Int
+		Errors   []error
+	}
+
+	readErr := errors.New("read failed")
+	testCases := []TestCase{
+		{
+			Name:     "Lines",
+			Reader:   strings.NewReader("1\n2\n3\n"),
+			Expected: []Int{{Value: 1}, {Value: 2}, {Value: 3}},
+		},
+		{
+			Name:     "LineError",
+			Reader:   strings.NewReader("1\na\n3\n"),
+			Expected: []Int{{Value: 1}, {Value: 3}},
+			Errors:   []error{&LineError{Line: 2, Offset: 2, Err: noMatch[Int]("a")}},
+		},
+		{
+			Name:     "SkipNoMatch",
+			Reader:   strings.NewReader("1\na\n3\n"),
+			Options:  []Option{SkipNoMatch()},
+			Expected: []Int{{Value: 1}, {Value: 3}},
+		},
+		{
+			Name:   "ReadError",
+			Reader: iotest.ErrReader(readErr),
+			Errors: []error{readErr},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			values, errs := receiveStream(ParseStream[Int](context.Background(), tc.Reader, tc.Options...))
+			assert.EqualValues(t, tc.Expected, values)
+			assert.EqualValues(t, tc.Errors, errs)
+		})
+	}
+}
+
+func TestParseStreamCompileError(t *testing.T) {
+	values, errs := receiveStream(ParseStream[MissingFieldStruct](context.Background(), strings.NewReader("a\n")))
+	assert.Empty(t, values)
+	assert.EqualValues(t, []error{&MissingField{}}, errs)
+}
+
+func TestParseStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	values, errs := ParseStream[Int](ctx, strings.NewReader("1\n2\n3\n"))
+
+	assert.EqualValues(t, Int{Value: 1}, <-values)
+	cancel()
+	// Both channels are closed without receiving the remaining values
+	for range values {
+	}
+	for range errs {
+	}
+}
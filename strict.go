@@ -0,0 +1,38 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// Strict fails a parse with an EmptyCapture error when the capture group of
+// a field is empty or did not participate in the match, instead of leaving the
+// field's zero value. Fields with the structexp.optional or structexp.default
+// tag are exempt. Compile also fails with an UnplacedField error if a field
+// has no capture group in the regular expression.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// setStrict marks the fields, and the fields of nested structs, as strict
+func setStrict(fields []*field) {
+	for _, f := range fields {
+		f.Strict = true
+		if f.Nested != nil {
+			setStrict(f.Nested.Fields)
+		}
+	}
+}
+
+// checkPlaced checks every field of the struct type
+// has a capture group in the regular expression
+func checkPlaced(t reflect.Type, regxp *regexp.Regexp, fields []*field) error {
+	for _, f := range fields {
+		if subexpIndex(regxp.SubexpNames(), f.group()) == -1 {
+			return &UnplacedField{fieldPath(t, f.Index), f.CaptureGroupName}
+		}
+	}
+	return nil
+}
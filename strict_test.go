@@ -0,0 +1,63 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type StrictStruct struct {
+	StructExp `structexp:"^{{level}}:(?: {{code}})?(?: {{msg}})?$"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]*"`
+	Code      int    `structexp.name:"code" structexp.default:"200"`
+	Message   string `structexp.name:"msg" structexp.optional:"true"`
+}
+
+func TestStrict(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected StrictStruct
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "AllFields",
+			String:   "WARN: 404 not found",
+			Expected: StrictStruct{Level: "WARN", Code: 404, Message: "not found"},
+		},
+		{
+			Name:     "ExemptFields",
+			String:   "WARN:",
+			Expected: StrictStruct{Level: "WARN", Code: 200},
+		},
+		{
+			Name:   "EmptyCapture",
+			String: ": 404",
+			Error:  &EmptyCapture{"Level", "level"},
+		},
+	}
+
+	parser := MustCompile[StrictStruct](Strict())
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := parser.Parse(tc.String)
+			assert.EqualValues(t, tc.Error, err)
+			if err == nil {
+				assert.EqualValues(t, tc.Expected, v)
+			}
+		})
+	}
+
+	// Without Strict, the empty capture leaves the zero value
+	v, err := MustCompile[StrictStruct]().Parse(": 404")
+	assert.NoError(t, err)
+	assert.EqualValues(t, StrictStruct{Code: 404}, v)
+}
+
+func TestStrictUnplacedField(t *testing.T) {
+	_, err := Compile[Entry](Strict(), WithTemplate(`^{{key}}=`))
+	assert.EqualValues(t, &UnplacedField{"Value", "value"}, err)
+}
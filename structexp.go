@@ -4,24 +4,91 @@
 //  - bool
 //  - int
 //  - string
-//  - ParsableField
+//  - byte (uint8) and rune (int32), when tagged with structexp.name
+//  - ParsableField, and encoding.TextUnmarshaler
+//  - interface, when tagged with structexp.discriminator
+//  - struct and []struct, where the struct has its own StructExp field
+//  - slices of the types above, when tagged with structexp.split
 //
 // Struct variable tags:
-//  - structexp: used with the StructExp type to define the regular expression used for parsing
+//  - structexp: used with the StructExp type to define the regular expression used for parsing,
+//    or "-" on any other variable to ignore it, like encoding/json. Several StructExp
+//    variables define several versions of the format, tried in order; see Compile
+//  - structexp.flags: on a StructExp variable, the flags of the regular expression syntax
+//    to compile its template with, such as "ms" for records spanning several lines
 //  - structexp.name: the variable regexp capture group name and string wrapped in double curly
-//    braces {{}} to replace in the regular expression
+//    braces {{}} to replace in the regular expression, or "$0" to set the variable to
+//    the whole match, such as the raw record; see WholeMatch
 //  - structexp.exp: the variable regular expression to use in the named capture group
+//  - structexp.sub: the name of the SubParser to parse the variable's capture with,
+//    after the whole regular expression has matched; see RegisterSubParser. A comma
+//    separated list of names, such as "rfc3339,unix", is tried in order until one succeeds
+//  - structexp.transform: a comma separated list of the names of the Transforms to rewrite
+//    the variable's capture with, in order, before parsing it, such as "stripcommas" for
+//    1,234; see RegisterTransform
+//  - structexp.discriminator: for interface variables, the capture group name whose
+//    value selects the factory creating the concrete type; see RegisterFactory
+//  - structexp.optional: "true" to make the variable's capture group optional; when
+//    it is absent or empty, the variable keeps its zero value, or nil for pointers
+//  - structexp.default: the value to parse the variable from when its capture is
+//    absent or empty, such as for an optional variable
+//  - structexp.trim: "true" to trim white space from the variable's capture before
+//    parsing it, or the cutset of characters to trim, such as "0" or " _"
+//  - structexp.ignorecase: "true" to match the variable's expression case-insensitively
+//  - structexp.index: the position of an unnamed capture group in the regular expression
+//    to set the variable from, such as "2", instead of a placeholder, to reuse a regular
+//    expression without named groups as the template; the group is given the variable's
+//    capture group name
+//  - structexp.split: the separator of the elements of a slice variable's capture, such
+//    as "," for 1,2,3, each parsed like a variable of the element type; the variable's
+//    expression, by default a list of the element type's expressions, matches the whole list
+//  - structexp.raw: on a string variable, the capture group name of another variable whose
+//    unparsed submatch to set the variable to, such as the original "1,234" of an int
+//    variable; the variable needs no placeholder, and is not rendered when formatting
+//  - structexp.min and structexp.max: the least and greatest value of a numeric variable,
+//    or length of a string or slice variable, checked after parsing it, such as "1" and
+//    "65535" for a port; a failed check is a ValidationError
+//  - structexp.oneof: a comma separated list of the allowed values of the variable, in
+//    their default format, such as "GET,POST", checked after parsing it
+//  - structexp.match: a regular expression the variable's value, in its default format,
+//    must match, checked after parsing it
+//  - structexp.only: "parse" to never render the variable when formatting, its capture
+//    group being rendered like the rest of the expression, or "format" to never set
+//    the variable when parsing, such as for a computed display variable
+//
+// Placeholder tokens:
+//  - {{name:quoted}}: a double quoted string that may contain escaped quotes;
+//    the quotes are removed and escaped quotes and backslashes are unescaped
+//  - {{name:bracketed}}: a region enclosed in balanced (), [], or {} brackets,
+//    found by scanning from its opening bracket to the matching closing bracket,
+//    since regular expressions cannot match balanced brackets
 //
 // Notes:
 //  - bool values are parsed from the regexp string result using strconv.ParseBool.
 //    This is why the DefaultBoolExp value is `1|t|T|TRUE|true|True|0|f|F|FALSE|false|False`
 //  - int values are parsed from the regexp string result using strconv.ParseInt.
 //    This is why the DefaultIntExp value is `[[:digit:]]+`
+//  - byte and rune values are parsed from a single captured character. Since
+//    uint8 and int32 fields are often plain numbers, they are only included
+//    when they have the structexp.name tag set
 //  - It is not recommended to set the structexp.exp tag for bool or int fields,
 //    as this will likely make them unable to be parsed. Instead, define a type that
 //    satisfies the ParsableField interface
-//  - ParsableFields need the structexp.exp tag set
-//  - Nested and Embedded structs are supported
+//  - ParsableFields and encoding.TextUnmarshalers need the structexp.exp tag set.
+//    Fields are converted from their strings by the convert package
+//  - ParsableFields whose structexp.exp has named capture groups can implement
+//    SubmatchParsableField, to be given the submatches of their groups
+//  - Nested and Embedded structs, and pointers to them, are supported.
+//    Nil struct pointers are allocated when one of their fields is set
+//  - Nested and Embedded structs with their own StructExp field are not flattened;
+//    instead they are referenced by a single placeholder, which is replaced with
+//    the struct's expression, and their fields are parsed from its capture
+//  - Slices of structs with their own StructExp field are filled with every
+//    match of the struct's expression within the slice field's capture. By
+//    default the slice field's expression is the struct's expression repeated
+//  - Optional fields only make their own capture group optional. To make the text
+//    around it optional too, enclose both in an optional group of the template,
+//    such as `(?: code={{code}})?`
 //
 // Example:
 //
@@ -38,17 +105,21 @@
 package structexp
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
-	"strconv"
-	"strings"
+	"unicode/utf8"
+
+	"github.com/densestvoid/structexp/convert"
 )
 
 const tagKey = "structexp"
 
 // StructExp is a required field for a struct that will be parsed,
-// to apply the structexp tag as the base regular expression
+// to apply the structexp tag as the base regular expression, unless
+// the struct is a Patterner or its pattern is registered with RegisterPattern
 type StructExp struct{}
 
 // ParsableField interface defines a means of converting the regex
@@ -60,6 +131,15 @@ type ParsableField interface {
 	Parse(string) error
 }
 
+// SubmatchParsableField extends ParsableField for types whose structexp.exp
+// tag contains named capture groups, such as a date with year, month, and day
+// groups. ParseSubmatches is called instead of Parse with the named submatches
+// of the field's capture, so the type does not need to match its own regex again.
+type SubmatchParsableField interface {
+	ParsableField
+	ParseSubmatches(s string, submatches map[string]string) error
+}
+
 // Parse uses the struct argument's fields to construct a regular
 // expression with named capture groups to parse the struct fields
 // from the string argument.
@@ -69,45 +149,342 @@ type ParsableField interface {
 //  - struct is missing a StructExp field
 //  - regular expression does not match the string
 func Parse(s string, i interface{}) error {
+	p, err := structPtrParser(i)
+	if err != nil {
+		return err
+	}
+	return p.parse(reflect.ValueOf(i).Elem(), s)
+}
+
+// structPtrParser returns the cached typeParser of the struct the argument
+// points to, or the error of the argument or its struct definition
+func structPtrParser(i interface{}) (*typeParser, error) {
 	// Verify interface is a pointer to a structure
 	t := reflect.TypeOf(i)
 	if kind := t.Kind(); kind != reflect.Ptr {
-		return &NotStruct{kind}
+		return nil, &NotStruct{kind}
 	}
 
 	t = t.Elem()
 	if kind := t.Kind(); kind != reflect.Struct {
+		return nil, &NotStruct{kind}
+	}
+
+	return cachedParser(t)
+}
+
+// MustParse is like Parse but panics if the string cannot be parsed
+func MustParse(s string, i interface{}) {
+	if err := Parse(s, i); err != nil {
+		panic(fmt.Sprintf("structexp: Parse(%q): %v", s, err))
+	}
+}
+
+// Match reports whether the string matches the regular expression of the
+// struct, or struct pointer, argument, without setting any of its fields.
+// It is a cheap check for routing strings between multiple struct types,
+// though the fields of a matching string may still fail to parse.
+//
+// Match returns false if the argument's regular expression cannot be built.
+func Match(s string, i interface{}) bool {
+	t := reflect.TypeOf(i)
+	if t == nil {
+		return false
+	}
+	if t = derefType(t); t.Kind() != reflect.Struct {
+		return false
+	}
+
+	p, err := cachedParser(t)
+	if err != nil {
+		return false
+	}
+	return p.match(s)
+}
+
+// AssignSubmatches sets the fields of the struct argument from the submatches
+// of a regular expression matched by the caller, such as a cached result of
+// FindStringSubmatch, with the group names of the expression from SubexpNames.
+// Each field is converted from the submatch of the group with its capture
+// group name, in the same way as Parse; fields without a group are left unset.
+//
+// Errors occur if:
+//  - argument is not the address of a struct
+//  - the number of names and submatches differ
+//  - any field fails to parse
+func AssignSubmatches(i interface{}, names []string, matches []string) error {
+	t := reflect.TypeOf(i)
+	if t == nil {
+		return &NotStruct{reflect.Invalid}
+	}
+	if kind := t.Kind(); kind != reflect.Ptr {
+		return &NotStruct{kind}
+	}
+	if kind := t.Elem().Kind(); kind != reflect.Struct {
 		return &NotStruct{kind}
 	}
+	if len(names) != len(matches) {
+		return &MismatchedSubmatches{len(names), len(matches)}
+	}
 
-	base, err := regexpBase(t)
+	fields, err := listFields(t.Elem(), nil, nil, defaultRegistry)
 	if err != nil {
 		return err
 	}
-	fields := listFields(reflect.ValueOf(i).Elem())
-	regxp, err := fillRegexp(base, fields)
+	return setSubmatches(reflect.ValueOf(i).Elem(), names, fields, matches)
+}
+
+// ParseAs is the generic form of Parse, returning a new value of the struct
+// type parsed from the string instead of setting the fields of an argument.
+//
+// Errors occur in the same cases as Parse, including if the type is not a struct.
+func ParseAs[T any](s string) (T, error) {
+	var v T
+	err := Parse(s, &v)
+	return v, err
+}
+
+// ParseAll parses every match of the struct's regular expression in the string
+// into a new element of the slice argument, replacing its contents. The struct
+// regular expression's beginning and end anchors are ignored, so that more than
+// one match can be found. With several templates, each match is parsed with the
+// template matching earliest in the rest of the string, or the first of them.
+//
+// Errors occur if:
+//  - argument is not the address of a slice of structs, or pointers to structs
+//  - struct is missing a StructExp field
+//  - any match's fields fail to parse
+func ParseAll(s string, slicePtr interface{}) error {
+	return ParseAllContext(context.Background(), s, slicePtr)
+}
+
+// ParseAllContext is like ParseAll, but stops parsing matches when the
+// context is done, returning its error, so callers can abort parsing
+// huge inputs cleanly. The context is checked between matches.
+func ParseAllContext(ctx context.Context, s string, slicePtr interface{}) error {
+	v := reflect.ValueOf(slicePtr)
+	if kind := v.Kind(); kind != reflect.Ptr {
+		return &NotSlice{kind}
+	}
+
+	v = v.Elem()
+	if kind := v.Kind(); kind != reflect.Slice {
+		return &NotSlice{kind}
+	}
+	structType := derefType(v.Type().Elem())
+	if kind := structType.Kind(); kind != reflect.Struct {
+		return &NotStruct{kind}
+	}
+
+	p, err := cachedParser(structType)
 	if err != nil {
 		return err
 	}
+	return p.parseAll(ctx, v, s)
+}
+
+// ParseAllAs is the generic form of ParseAll, returning
+// a new slice of the structs parsed from the string.
+//
+// Errors occur in the same cases as ParseAll.
+func ParseAllAs[T any](s string) ([]T, error) {
+	var v []T
+	err := ParseAll(s, &v)
+	return v, err
+}
+
+// Build the regular expression for the first template of the struct type,
+// and list the fields to set from its capture groups, once per struct type
+// until a registration
+func compile(t reflect.Type) (*regexp.Regexp, []*field, error) {
+	p, err := cachedParser(t)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.regexp, p.fields, nil
+}
+
+// cachedParser returns the typeParser of every template of the struct type,
+// compiled without options, once per struct type until a registration
+func cachedParser(t reflect.Type) (*typeParser, error) {
+	return cachedCompile(t, compileType)
+}
+
+// compileType compiles the typeParser of the struct type without options, like cachedParser
+func compileType(t reflect.Type) (*typeParser, error) {
+	return compileParser(t, newOptions(nil))
+}
+
+// Build the regular expression from the template instead of the StructExp
+// field, and list the fields to set, with sub-parsers from the registry
+func compileTemplate(t reflect.Type, base string, reg *Registry) (*regexp.Regexp, []*field, error) {
+	fields, err := listFields(t, nil, nil, reg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkDuplicateGroups(t, fields); err != nil {
+		return nil, nil, err
+	}
+	if err := checkRawGroups(t, fields); err != nil {
+		return nil, nil, err
+	}
+	regxp, err := fillRegexp(base, fields, placeholderRegexp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if regxp, err = nameIndexedGroups(t, regxp, fields); err != nil {
+		return nil, nil, err
+	}
+	planFields(regxp, fields)
+	return regxp, fields, nil
+}
 
-	if !regxp.MatchString(s) {
-		return &NoMatch{}
+// checkDuplicateGroups checks no two fields of the struct type have the same capture group name
+func checkDuplicateGroups(t reflect.Type, fields []*field) error {
+	byGroup := make(map[string]*field, len(fields))
+	for _, f := range fields {
+		if other, ok := byGroup[f.CaptureGroupName]; ok {
+			return &DuplicateGroup{f.CaptureGroupName, [2]string{fieldPath(t, other.Index), fieldPath(t, f.Index)}}
+		}
+		byGroup[f.CaptureGroupName] = f
 	}
+	return nil
+}
+
+// Set the struct value's fields from the regular expression submatches
+func setFields(v reflect.Value, regxp *regexp.Regexp, fields []*field, matches []string) error {
+	return setSubmatches(v, regxp.SubexpNames(), fields, matches)
+}
 
-	matches := regxp.FindStringSubmatch(s)
+// Set the struct value's fields from the submatches of the named groups
+func setSubmatches(v reflect.Value, names []string, fields []*field, matches []string) error {
+	group := func(name string) string {
+		if idx := subexpIndex(names, name); idx != -1 {
+			return matches[idx]
+		}
+		return ""
+	}
+	var errs []error
 	for _, field := range fields {
-		if idx := regxp.SubexpIndex(field.CaptureGroupName); idx != -1 {
-			if err := setField(field.Value, matches[idx]); err != nil {
+		if field.FormatOnly {
+			continue
+		}
+		if err := field.setSubmatch(v, names, matches, group); err != nil {
+			if !field.JoinErrors {
 				return err
 			}
+			// The joined errors of a nested struct are listed with the others
+			if joined, ok := err.(interface{ Unwrap() []error }); ok && field.Nested != nil {
+				errs = append(errs, joined.Unwrap()...)
+				continue
+			}
+			errs = append(errs, err)
 		}
 	}
+	return errors.Join(errs...)
+}
 
-	return nil
+// Set the struct value's field from the submatch of its group, if it has one
+func (f *field) setSubmatch(v reflect.Value, names []string, matches []string, group func(name string) string) error {
+	idx := f.groupIndex(names)
+	if idx == -1 || idx >= len(matches) {
+		return nil
+	}
+	s := matches[idx]
+	if f.Raw != "" {
+		if err := f.setValue(fieldByIndex(v, f.Index), s); err != nil {
+			return f.fieldError(v.Type(), s, err)
+		}
+		return f.checkValue(v)
+	}
+	if f.Trim != nil {
+		s = f.Trim(s)
+	}
+	if f.Default != "" && s == "" {
+		if err := f.SetDefault(v, group); err != nil {
+			return f.fieldError(v.Type(), f.Default, err)
+		}
+		return f.checkValue(v)
+	}
+	if f.Strict && !f.Optional && s == "" {
+		return &EmptyCapture{fieldPath(v.Type(), f.Index), f.CaptureGroupName}
+	}
+	if f.Optional && s == "" {
+		// Keep the zero value, and nil struct pointers
+		return nil
+	}
+	if f.Submatches != nil && idx+len(f.Submatches) < len(matches) {
+		// The field's own groups directly follow its capture group
+		if err := f.SetSubmatches(v, s, matches[idx+1:]); err != nil {
+			return f.fieldError(v.Type(), s, err)
+		}
+		return f.checkValue(v)
+	}
+	if err := f.Set(v, s, group); err != nil {
+		return f.fieldError(v.Type(), s, err)
+	}
+	return f.checkValue(v)
+}
+
+// fieldError returns the FieldError of the field of the struct type failing
+// to be set from the value. The FieldError of a field of a nested struct
+// is returned with the path of its field from the struct type.
+func (f *field) fieldError(t reflect.Type, value string, err error) error {
+	path := fieldPath(t, f.Index)
+	if nested, ok := err.(*FieldError); ok {
+		return &FieldError{path + "." + nested.Field, nested.Group, nested.Value, nested.Err}
+	}
+	// The joined errors of the fields of a nested struct, with the JoinFieldErrors option
+	if joined, ok := err.(interface{ Unwrap() []error }); ok && f.Nested != nil && f.JoinErrors {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, f.fieldError(t, value, err))
+		}
+		return errors.Join(errs...)
+	}
+	return &FieldError{path, f.CaptureGroupName, value, err}
 }
 
-// Get the Regexp base from the Regexp field
+// Get the index of the first group with the name, like regexp.Regexp.SubexpIndex,
+// or 0 for the whole match
+func subexpIndex(names []string, name string) int {
+	if name == WholeMatch {
+		return 0
+	}
+	if name != "" {
+		for i, s := range names {
+			if name == s {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Get the Regexp base from the registered pattern, the Pattern method, or the Regexp field
 func regexpBase(t reflect.Type) (string, error) {
+	bases, err := regexpBases(t)
+	if err != nil {
+		return "", err
+	}
+	return bases[0], nil
+}
+
+// Get every Regexp base of the struct type: the registered patterns, the
+// Pattern method, or the Regexp fields, of which there may be several
+func regexpBases(t reflect.Type) ([]string, error) {
+	if patterns, ok := lookupPatterns(t); ok {
+		return patterns, nil
+	}
+	var bases []string
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Type == reflect.TypeOf(StructExp{}) {
+			bases = append(bases, fieldTemplate(field))
+		}
+	}
+	if len(bases) > 0 {
+		return bases, nil
+	}
 	regexpField, ok := t.FieldByNameFunc(func(name string) bool {
 		if field, _ := t.FieldByName(name); field.Type == reflect.TypeOf(StructExp{}) {
 			return true
@@ -115,14 +492,16 @@ func regexpBase(t reflect.Type) (string, error) {
 		return false
 	})
 	if !ok {
-		return "", &MissingField{}
+		return nil, &MissingField{}
 	}
-	return regexpField.Tag.Get(tagKey), nil
+	return []string{fieldTemplate(regexpField)}, nil
 }
 
-func listFields(v reflect.Value) []*field {
-	t := v.Type()
-
+// List the fields of the struct type, indexed from the root struct type
+// through the index of the struct type. The parents are the struct types
+// the struct type is nested in, to avoid following recursive pointers.
+// Sub-parsers and transforms are looked up in the registry.
+func listFields(t reflect.Type, index []int, parents []reflect.Type, reg *Registry) ([]*field, error) {
 	var fields []*field
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
@@ -132,39 +511,180 @@ func listFields(v reflect.Value) []*field {
 			continue
 		}
 
+		// Skip the fields ignored by their tag
+		if field.Tag.Get(tagKey) == "-" {
+			continue
+		}
+
+		// Any field type can be parsed by a sub-parser
+		if _, ok := field.Tag.Lookup(subParserKey); ok {
+			f, err := newSubParserField(fieldIndex(index, i), &field, reg)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, f)
+			continue
+		}
+
 		// nolint:exhaustive // unnecessary
 		switch field.Type.Kind() {
+		case reflect.Interface:
+			// interface fields are only parsed with a discriminator to choose their type
+			if _, ok := field.Tag.Lookup(discriminatorKey); !ok {
+				continue
+			}
 		case reflect.Bool:
 		case reflect.Int:
 		case reflect.String:
+		case reflect.Uint8, reflect.Int32:
+			// byte and rune fields are only parsed when explicitly tagged
+			if _, ok := field.Tag.Lookup(captureGroupNameKey); !ok {
+				continue
+			}
 		default:
-			if reflect.PtrTo(field.Type).Implements(reflect.TypeOf((*ParsableField)(nil)).Elem()) {
+			if convert.Implements(field.Type) {
+				break
+			}
+			// Slices split from a delimited capture
+			if field.Type.Kind() == reflect.Slice && field.Tag.Get(splitKey) != "" {
 				break
 			}
-			if field.Type.Kind() == reflect.Struct {
-				fields = append(fields, listFields(v.Field(i))...)
+			// Pointers to structs are allocated when their fields are set
+			structType := derefType(field.Type)
+			if containsType(append(parents, t), structType) {
+				continue
+			}
+			if hasStructExp(structType) || isStructExpSlice(field.Type) {
+				f, err := newStructExpField(fieldIndex(index, i), &field, reg)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, f)
+				continue
+			}
+			if structType.Kind() == reflect.Struct {
+				nested, err := listFields(structType, fieldIndex(index, i), append(parents, t), reg)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, nested...)
 			}
 			continue
 		}
 
-		fields = append(fields, newField(v.Field(i), &field))
+		f := newField(fieldIndex(index, i), &field)
+		if err := f.lookupTransforms(field.Tag, reg); err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// Check if the type is in the list of types
+func containsType(types []reflect.Type, t reflect.Type) bool {
+	for _, typ := range types {
+		if typ == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the nested field by its index, allocating
+// any nil struct pointers along the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		v = allocate(v).Field(i)
+	}
+	if v.Kind() == reflect.Ptr {
+		allocate(v)
+	}
+	return v
+}
+
+// Allocate the pointer value if it is nil, and return the value it points to
+func allocate(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr {
+		return v
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
 	}
-	return fields
+	return v.Elem()
 }
 
+// Append the field index to a copy of the struct index
+func fieldIndex(index []int, i int) []int {
+	return append(append(make([]int, 0, len(index)+1), index...), i)
+}
+
+// placeholderRegexp matches the {{name}} and {{name:token}} placeholders
+var placeholderRegexp = regexp.MustCompile(`{{([^{}:]+)(?::([^{}]*))?}}`)
+
 // Fill in the regexp string with field expressions
-func fillRegexp(base string, fields []*field) (*regexp.Regexp, error) {
+func fillRegexp(base string, fields []*field, placeholders *regexp.Regexp) (*regexp.Regexp, error) {
+	pending := make(map[string][]*field, len(fields))
 	for _, field := range fields {
-		base = strings.Replace(
-			base,
-			fmt.Sprintf("{{%s}}", field.CaptureGroupName),
-			field.NamedCaptureGroup(),
-			1,
-		)
+		// Bound by position, to the whole match, or to the capture
+		// group of another field, the field has no placeholder
+		if field.GroupIndex > 0 || field.CaptureGroupName == WholeMatch || field.Raw != "" {
+			continue
+		}
+		pending[field.CaptureGroupName] = append(pending[field.CaptureGroupName], field)
+	}
+
+	var err error
+	var unknown []string
+	base = placeholders.ReplaceAllStringFunc(base, func(placeholder string) string {
+		submatches := placeholders.FindStringSubmatch(placeholder)
+		name, tokenName := submatches[1], submatches[2]
+
+		fields := pending[name]
+		if len(fields) == 0 {
+			// Left in the pattern, it would fail to compile, or match literal braces
+			if !containsString(unknown, name) {
+				unknown = append(unknown, name)
+			}
+			return placeholder
+		}
+		field := fields[0]
+		pending[name] = fields[1:]
+
+		if tokenName != "" {
+			tok, ok := tokens[tokenName]
+			if !ok {
+				if err == nil {
+					err = &UnknownToken{tokenName}
+				}
+				return placeholder
+			}
+			field.Exp = tok.Exp
+			field.Transform = tok.Transform
+			field.Escape = tok.Escape
+			field.Scan = tok.Scan
+		}
+		return field.NamedCaptureGroup()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(unknown) > 0 {
+		return nil, &UnknownPlaceholder{unknown}
 	}
 	return regexp.Compile(base)
 }
 
+// containsString reports whether the string is in the list
+func containsString(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
+
 func setField(val reflect.Value, s string) error {
 	underVal := underlyingValue(val)
 
@@ -173,32 +693,47 @@ func setField(val reflect.Value, s string) error {
 		return &InvalidType{val.Type()}
 	}
 
-	// Check if pointer to underlying type satisfies the ParsableFiled interface
-	if underVal.CanAddr() {
-		if parsable, ok := underVal.Addr().Interface().(ParsableField); ok {
-			return parsable.Parse(s)
-		}
+	// Types converting themselves, such as ParsableFields, take precedence over their kind
+	if convert.Implements(underVal.Type()) {
+		return convert.Set(underVal, s)
 	}
 
-	// Set the fields of the basic types
+	// Set the fields of the kinds parsed differently than by the convert package
 	// nolint:exhaustive // unnecessary
 	switch underVal.Kind() {
-	case reflect.Bool:
-		b, err := strconv.ParseBool(s)
-		if err != nil {
-			return err
+	case reflect.Struct:
+		if hasStructExp(underVal.Type()) {
+			return setStructExp(underVal, s)
+		}
+		return nil
+	case reflect.Slice:
+		if isStructExpSlice(underVal.Type()) {
+			return setStructExpSlice(context.Background(), underVal, s)
 		}
-		underVal.SetBool(b)
-	case reflect.Int:
-		i, err := strconv.ParseInt(s, 10, 0)
-		if err != nil {
-			return err
+		return nil
+	case reflect.Uint8:
+		if len(s) != 1 {
+			return &InvalidChar{s, underVal.Kind()}
 		}
-		underVal.SetInt(i)
-	case reflect.String:
-		underVal.SetString(s)
+		underVal.SetUint(uint64(s[0]))
+		return nil
+	case reflect.Int32:
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError || size != len(s) {
+			return &InvalidChar{s, underVal.Kind()}
+		}
+		underVal.SetInt(int64(r))
+		return nil
 	}
 
+	// Set the fields of the basic types
+	if err := convert.Set(underVal, s); err != nil {
+		var unsupported *convert.Unsupported
+		if errors.As(err, &unsupported) {
+			return nil
+		}
+		return err
+	}
 	return nil
 }
 
@@ -1,13 +1,23 @@
 package structexp
 
 import (
+	"context"
+	"fmt"
+	"net/netip"
 	"reflect"
+	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// noMatch returns the NoMatch error of the input not matching the regular expression of T
+func noMatch[T any](input string, opts ...Option) *NoMatch {
+	return NewNoMatch(MustCompile[T](opts...).Regexp(), input)
+}
+
 type Bool struct {
 	StructExp `structexp:"{{test}}"`
 	Value     bool `structexp.name:"test"`
@@ -23,6 +33,42 @@ type String struct {
 	Value     string `structexp.name:"test"`
 }
 
+type Byte struct {
+	StructExp `structexp:"{{test}}"`
+	Value     byte `structexp.name:"test"`
+}
+
+type Rune struct {
+	StructExp `structexp:"{{test}}"`
+	Value     rune `structexp.name:"test"`
+}
+
+type UntaggedRune struct {
+	StructExp `structexp:"x"`
+	Value     rune
+}
+
+type Quoted struct {
+	StructExp `structexp:"^msg={{test:quoted}}$"`
+	Value     string `structexp.name:"test"`
+}
+
+type Bracketed struct {
+	StructExp `structexp:"^call{{test:bracketed}} end$"`
+	Value     string `structexp.name:"test"`
+}
+
+type BracketedRest struct {
+	StructExp `structexp:"^{{body:bracketed}} {{rest}}$"`
+	Body      string `structexp.name:"body"`
+	Rest      string `structexp.name:"rest"`
+}
+
+type UnknownTokenStruct struct {
+	StructExp `structexp:"{{test:unknown}}"`
+	Value     string `structexp.name:"test"`
+}
+
 type ParsableBool bool
 
 func (p *ParsableBool) Parse(s string) error {
@@ -42,6 +88,30 @@ type ParsableStruct struct {
 	Value     ParsableBool `structexp.name:"test" structexp.exp:"a|b"`
 }
 
+type SubmatchDate struct {
+	Year, Month, Day string
+}
+
+func (d *SubmatchDate) Parse(s string) error {
+	return fmt.Errorf("Parse called instead of ParseSubmatches with %q", s)
+}
+
+func (d *SubmatchDate) ParseSubmatches(s string, submatches map[string]string) error {
+	d.Year, d.Month, d.Day = submatches["year"], submatches["month"], submatches["day"]
+	return nil
+}
+
+type SubmatchStruct struct {
+	StructExp `structexp:"^{{start}} to {{end}}$"`
+	Start     SubmatchDate `structexp.name:"start" structexp.exp:"(?P<year>\\d{4})-(\\d{2}|(?P<month>[A-Z][a-z]+))-(?P<day>\\d{2})"`
+	End       SubmatchDate `structexp.name:"end" structexp.exp:"(?P<year>\\d{4})-(\\d{2}|(?P<month>[A-Z][a-z]+))-(?P<day>\\d{2})"`
+}
+
+type TextUnmarshalerStruct struct {
+	StructExp `structexp:"^{{addr}}$"`
+	Addr      netip.Addr `structexp.name:"addr" structexp.exp:"[0-9.]+"`
+}
+
 type NestedStruct struct {
 	Value string `structexp.name:"test"`
 }
@@ -60,6 +130,91 @@ type ParentEmbeddedStruct struct {
 	EmbeddedStruct
 }
 
+type Entry struct {
+	StructExp `structexp:"^{{key}}={{value}};$"`
+	Key       string `structexp.name:"key" structexp.exp:"\\w+"`
+	Value     string `structexp.name:"value" structexp.exp:"\\w+"`
+}
+
+type EntriesStruct struct {
+	StructExp `structexp:"^entries: {{test}}$"`
+	Entries   []Entry `structexp.name:"test"`
+}
+
+type Header struct {
+	StructExp `structexp:"^{{level}}: {{code}}$"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code      int    `structexp.name:"code"`
+}
+
+type ParentHeaderStruct struct {
+	StructExp `structexp:"^\\[{{Header}}\\] {{code}}$"`
+	Header    Header
+	Code      int `structexp.name:"code"`
+}
+
+type ParentNestedPointerStruct struct {
+	StructExp `structexp:"{{test}}"`
+	Nested    *NestedStruct
+}
+
+type ParentEmbeddedPointerStruct struct {
+	StructExp `structexp:"{{test}}"`
+	*EmbeddedStruct
+}
+
+type RecursivePointerStruct struct {
+	StructExp `structexp:"{{test}}"`
+	Value     string `structexp.name:"test"`
+	Next      *RecursivePointerStruct
+}
+
+type ParentHeaderPointerStruct struct {
+	StructExp `structexp:"^\\[{{Header}}\\]$"`
+	Header    *Header
+}
+
+type SubParsedStruct struct {
+	StructExp `structexp:"^{{json}} \\| {{kv}} \\| {{header}}$"`
+	JSON      map[string]int    `structexp.name:"json" structexp.sub:"json" structexp.exp:"{.*?}"`
+	KeyValues map[string]string `structexp.name:"kv" structexp.sub:"kv" structexp.exp:"[^|]*[^| ]"`
+	Header    Header            `structexp.name:"header" structexp.sub:"structexp"`
+}
+
+type UnknownSubParserStruct struct {
+	StructExp `structexp:"{{test}}"`
+	Value     string `structexp.name:"test" structexp.sub:"unknown"`
+}
+
+type OptionalStruct struct {
+	StructExp `structexp:"^{{level}}{{count}}(?: code={{code}})?(?: \\[{{Header}}\\])?$"`
+	Level     string  `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Count     int     `structexp.name:"count" structexp.optional:"true"`
+	Code      int     `structexp.name:"code" structexp.optional:"true"`
+	Header    *Header `structexp.optional:"true"`
+}
+
+type DefaultStruct struct {
+	StructExp `structexp:"^{{level}}(?: code={{code}})?$"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]*" structexp.default:"INFO"`
+	Code      int    `structexp.name:"code" structexp.optional:"true" structexp.default:"200"`
+}
+
+type DuplicateGroupStruct struct {
+	StructExp `structexp:"^{{code}}$"`
+	Code      int `structexp.name:"code"`
+	Nested    struct {
+		Status int `structexp.name:"code"`
+	}
+}
+
+type IgnoredFieldStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test"`
+	Count     int    `structexp:"-"`
+	Cache     Header `structexp:"-"`
+}
+
 type MissingFieldStruct struct {
 	Value string `structexp.name:"test"`
 }
@@ -95,6 +250,69 @@ func TestParse(t *testing.T) {
 			Expected: &String{Value: "string"},
 			Error:    nil,
 		},
+		{
+			Name:     "Byte",
+			String:   "x",
+			Input:    &Byte{},
+			Expected: &Byte{Value: 'x'},
+			Error:    nil,
+		},
+		{
+			Name:     "Rune",
+			String:   "é",
+			Input:    &Rune{},
+			Expected: &Rune{Value: 'é'},
+			Error:    nil,
+		},
+		{
+			Name:     "UntaggedRune",
+			String:   "x",
+			Input:    &UntaggedRune{},
+			Expected: &UntaggedRune{},
+			Error:    nil,
+		},
+		{
+			Name:     "QuotedToken",
+			String:   `msg="say \"hi\" \\ \n"`,
+			Input:    &Quoted{},
+			Expected: &Quoted{Value: `say "hi" \ \n`},
+			Error:    nil,
+		},
+		{
+			Name:     "BracketedToken",
+			String:   "call(a, [b], {c: (d)}) end",
+			Input:    &Bracketed{},
+			Expected: &Bracketed{Value: "(a, [b], {c: (d)})"},
+			Error:    nil,
+		},
+		{
+			Name:     "UnbalancedBracketsError",
+			String:   "call(a, [b) end",
+			Input:    &Bracketed{},
+			Expected: &Bracketed{},
+			Error:    &FieldError{"Value", "test", "(a, [b)", &UnbalancedBrackets{"(a, [b)"}},
+		},
+		{
+			Name:     "BracketedTokenNested",
+			String:   "(a (b) c) tail",
+			Input:    &BracketedRest{},
+			Expected: &BracketedRest{Body: "(a (b) c)", Rest: "tail"},
+			Error:    nil,
+		},
+		{
+			Name:     "BracketedTokenNestedMixed",
+			String:   "{a: [1, (2)] {b}} rest (d)",
+			Input:    &BracketedRest{},
+			Expected: &BracketedRest{Body: "{a: [1, (2)] {b}}", Rest: "rest (d)"},
+			Error:    nil,
+		},
+		{
+			Name:     "UnclosedBracketsError",
+			String:   "(a (b) tail",
+			Input:    &BracketedRest{},
+			Expected: &BracketedRest{},
+			Error:    &FieldError{"Body", "body", "(a (b)", &UnbalancedBrackets{"(a (b)"}},
+		},
 		{
 			Name:     "ParsableField",
 			String:   "a",
@@ -102,6 +320,20 @@ func TestParse(t *testing.T) {
 			Expected: &ParsableStruct{Value: ParsableBool(true)},
 			Error:    nil,
 		},
+		{
+			Name:     "SubmatchParsableField",
+			String:   "2024-Jan-02 to 2025-12-31",
+			Input:    &SubmatchStruct{},
+			Expected: &SubmatchStruct{Start: SubmatchDate{"2024", "Jan", "02"}, End: SubmatchDate{"2025", "", "31"}},
+			Error:    nil,
+		},
+		{
+			Name:     "TextUnmarshalerField",
+			String:   "10.0.0.1",
+			Input:    &TextUnmarshalerStruct{},
+			Expected: &TextUnmarshalerStruct{Addr: netip.MustParseAddr("10.0.0.1")},
+			Error:    nil,
+		},
 		{
 			Name:     "NestedStruct",
 			String:   "string",
@@ -116,6 +348,116 @@ func TestParse(t *testing.T) {
 			Expected: &ParentEmbeddedStruct{EmbeddedStruct: EmbeddedStruct{"string"}},
 			Error:    nil,
 		},
+		{
+			Name:     "NestedStructPointer",
+			String:   "string",
+			Input:    &ParentNestedPointerStruct{},
+			Expected: &ParentNestedPointerStruct{Nested: &NestedStruct{"string"}},
+			Error:    nil,
+		},
+		{
+			Name:     "EmbeddedStructPointer",
+			String:   "string",
+			Input:    &ParentEmbeddedPointerStruct{},
+			Expected: &ParentEmbeddedPointerStruct{EmbeddedStruct: &EmbeddedStruct{"string"}},
+			Error:    nil,
+		},
+		{
+			Name:     "RecursiveStructPointer",
+			String:   "string",
+			Input:    &RecursivePointerStruct{},
+			Expected: &RecursivePointerStruct{Value: "string"},
+			Error:    nil,
+		},
+		{
+			Name:     "NestedStructExpPointer",
+			String:   "[WARN: 12]",
+			Input:    &ParentHeaderPointerStruct{},
+			Expected: &ParentHeaderPointerStruct{Header: &Header{Level: "WARN", Code: 12}},
+			Error:    nil,
+		},
+		{
+			Name:     "NestedStructExp",
+			String:   "[WARN: 12] 34",
+			Input:    &ParentHeaderStruct{},
+			Expected: &ParentHeaderStruct{Header: Header{Level: "WARN", Code: 12}, Code: 34},
+			Error:    nil,
+		},
+		{
+			Name:   "StructSlice",
+			String: "entries: a=1;b=2;",
+			Input:  &EntriesStruct{},
+			Expected: &EntriesStruct{Entries: []Entry{
+				{Key: "a", Value: "1"},
+				{Key: "b", Value: "2"},
+			}},
+			Error: nil,
+		},
+		{
+			Name:     "EmptyStructSlice",
+			String:   "entries: ",
+			Input:    &EntriesStruct{},
+			Expected: &EntriesStruct{Entries: []Entry{}},
+			Error:    nil,
+		},
+		{
+			Name:   "SubParsers",
+			String: `{"a": 1} | b=2 c="d e" | INFO: 3`,
+			Input:  &SubParsedStruct{},
+			Expected: &SubParsedStruct{
+				JSON:      map[string]int{"a": 1},
+				KeyValues: map[string]string{"b": "2", "c": "d e"},
+				Header:    Header{Level: "INFO", Code: 3},
+			},
+			Error: nil,
+		},
+		{
+			Name:   "OptionalFields",
+			String: "WARN2 code=3 [INFO: 1]",
+			Input:  &OptionalStruct{},
+			Expected: &OptionalStruct{
+				Level:  "WARN",
+				Count:  2,
+				Code:   3,
+				Header: &Header{Level: "INFO", Code: 1},
+			},
+			Error: nil,
+		},
+		{
+			Name:     "OptionalFieldsAbsent",
+			String:   "WARN",
+			Input:    &OptionalStruct{},
+			Expected: &OptionalStruct{Level: "WARN"},
+			Error:    nil,
+		},
+		{
+			Name:     "Defaults",
+			String:   "",
+			Input:    &DefaultStruct{},
+			Expected: &DefaultStruct{Level: "INFO", Code: 200},
+			Error:    nil,
+		},
+		{
+			Name:     "DefaultsReplaced",
+			String:   "WARN code=404",
+			Input:    &DefaultStruct{},
+			Expected: &DefaultStruct{Level: "WARN", Code: 404},
+			Error:    nil,
+		},
+		{
+			Name:     "IgnoredFields",
+			String:   "string",
+			Input:    &IgnoredFieldStruct{Count: 3},
+			Expected: &IgnoredFieldStruct{Value: "string", Count: 3},
+			Error:    nil,
+		},
+		{
+			Name:     "UnknownSubParserError",
+			String:   "string",
+			Input:    &UnknownSubParserStruct{},
+			Expected: &UnknownSubParserStruct{},
+			Error:    &UnknownSubParser{"unknown"},
+		},
 		{
 			Name:     "BoolNotStructError",
 			String:   "true",
@@ -144,6 +486,13 @@ func TestParse(t *testing.T) {
 			Expected: &MissingFieldStruct{},
 			Error:    &MissingField{},
 		},
+		{
+			Name:     "UnknownTokenError",
+			String:   "string",
+			Input:    &UnknownTokenStruct{},
+			Expected: &UnknownTokenStruct{},
+			Error:    &UnknownToken{"unknown"},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -156,6 +505,204 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestMustParse(t *testing.T) {
+	var value Int
+	assert.NotPanics(t, func() { MustParse("100", &value) })
+	assert.EqualValues(t, Int{Value: 100}, value)
+	assert.Panics(t, func() { MustParse("a", &value) })
+}
+
+func TestMatch(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected bool
+	}
+
+	testCases := []TestCase{
+		{Name: "Match", String: "[WARN: 12] 34", Input: &ParentHeaderStruct{}, Expected: true},
+		{Name: "MatchInvalidField", String: "[WARN: 99999999999999999999] 34", Input: &ParentHeaderStruct{}, Expected: true},
+		{Name: "MatchValue", String: "[WARN: 12] 34", Input: ParentHeaderStruct{}, Expected: true},
+		{Name: "NoMatch", String: "[WARN] 34", Input: &ParentHeaderStruct{}, Expected: false},
+		{Name: "NotStruct", String: "1", Input: new(int), Expected: false},
+		{Name: "Nil", String: "1", Input: nil, Expected: false},
+		{Name: "MissingField", String: "1", Input: &MissingFieldStruct{}, Expected: false},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, Match(tc.String, tc.Input))
+		})
+	}
+}
+
+func TestAssignSubmatches(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Names    []string
+		Matches  []string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Assign",
+			Names:    []string{"", "code", "level"},
+			Matches:  []string{"12 WARN", "12", "WARN"},
+			Input:    &Header{},
+			Expected: &Header{Level: "WARN", Code: 12},
+			Error:    nil,
+		},
+		{
+			Name:     "MissingGroup",
+			Names:    []string{"", "level"},
+			Matches:  []string{"WARN", "WARN"},
+			Input:    &Header{},
+			Expected: &Header{Level: "WARN"},
+			Error:    nil,
+		},
+		{
+			Name:     "NestedStructExp",
+			Names:    []string{"", "Header", "code"},
+			Matches:  []string{"", "INFO: 1", "2"},
+			Input:    &ParentHeaderStruct{},
+			Expected: &ParentHeaderStruct{Header: Header{Level: "INFO", Code: 1}, Code: 2},
+			Error:    nil,
+		},
+		{
+			Name:     "MismatchedSubmatchesError",
+			Names:    []string{"", "level"},
+			Matches:  []string{"WARN"},
+			Input:    &Header{},
+			Expected: &Header{},
+			Error:    &MismatchedSubmatches{2, 1},
+		},
+		{
+			Name:     "NotStructError",
+			Names:    nil,
+			Matches:  nil,
+			Input:    Header{},
+			Expected: Header{},
+			Error:    &NotStruct{reflect.Struct},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := AssignSubmatches(tc.Input, tc.Names, tc.Matches)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+
+	// Submatches of a caller's own regular expression
+	regxp := regexp.MustCompile(`(?P<code>\d+)/(?P<level>\w+)`)
+	var header Header
+	require.NoError(t, AssignSubmatches(&header, regxp.SubexpNames(), regxp.FindStringSubmatch("3/DEBUG")))
+	assert.EqualValues(t, Header{Level: "DEBUG", Code: 3}, header)
+}
+
+func TestParseAs(t *testing.T) {
+	value, err := ParseAs[Int]("100")
+	require.NoError(t, err)
+	assert.EqualValues(t, Int{Value: 100}, value)
+
+	_, err = ParseAs[int]("100")
+	assert.EqualValues(t, &NotStruct{reflect.Int}, err)
+
+	_, err = ParseAs[Int]("a")
+	assert.EqualValues(t, noMatch[Int]("a"), err)
+}
+
+func TestParseAll(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Structs",
+			String:   "a=1;b=2;",
+			Input:    &[]Entry{},
+			Expected: &[]Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}},
+			Error:    nil,
+		},
+		{
+			Name:     "StructPointers",
+			String:   "a=1; b=2;",
+			Input:    &[]*Entry{},
+			Expected: &[]*Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}},
+			Error:    nil,
+		},
+		{
+			Name:     "NoMatches",
+			String:   "a",
+			Input:    &[]Entry{{Key: "b"}},
+			Expected: &[]Entry{},
+			Error:    nil,
+		},
+		{
+			Name:     "NotSliceError",
+			String:   "a=1;",
+			Input:    &Entry{},
+			Expected: &Entry{},
+			Error:    &NotSlice{reflect.Struct},
+		},
+		{
+			Name:     "NotStructError",
+			String:   "1",
+			Input:    &[]int{},
+			Expected: &[]int{},
+			Error:    &NotStruct{reflect.Int},
+		},
+		{
+			Name:     "MissingFieldError",
+			String:   "string",
+			Input:    &[]MissingFieldStruct{},
+			Expected: &[]MissingFieldStruct{},
+			Error:    &MissingField{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ParseAll(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestParseAllAs(t *testing.T) {
+	entries, err := ParseAllAs[Entry]("a=1;b=2;")
+	require.NoError(t, err)
+	assert.EqualValues(t, []Entry{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, entries)
+}
+
+func TestParseAllContext(t *testing.T) {
+	var entries []Entry
+	err := ParseAllContext(context.Background(), "a=b; c=d;", &entries)
+	require.NoError(t, err)
+	assert.EqualValues(t, []Entry{{Key: "a", Value: "b"}, {Key: "c", Value: "d"}}, entries)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	entries = nil
+	err = ParseAllContext(ctx, "a=b; c=d;", &entries)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, entries)
+}
+
 func TestSetField(t *testing.T) {
 	type TestCase struct {
 		Name     string
@@ -192,6 +739,24 @@ func TestSetField(t *testing.T) {
 				return &s
 			}(),
 		},
+		{
+			Name:   "Byte",
+			String: "x",
+			Input:  new(byte),
+			Expected: func() *byte {
+				var b byte = 'x'
+				return &b
+			}(),
+		},
+		{
+			Name:   "Rune",
+			String: "é",
+			Input:  new(rune),
+			Expected: func() *rune {
+				var r = 'é'
+				return &r
+			}(),
+		},
 		{
 			Name:   "ParsableField",
 			String: "a",
@@ -272,3 +837,105 @@ func TestUnderlyingValue(t *testing.T) {
 		})
 	}
 }
+
+func TestSubmatches(t *testing.T) {
+	regxp := regexp.MustCompile(`(?P<level>[A-Z]+)(?:: (?P<code>\d+))?( (?P<msg>.*))?`)
+	for _, s := range []string{"WARN: 12 disk full", "WARN disk", "WARN", "-"} {
+		loc := regxp.FindStringSubmatchIndex(s)
+		if loc == nil {
+			continue
+		}
+		assert.Equal(t, regxp.FindStringSubmatch(s), submatches(s, loc), s)
+	}
+
+	var value ParentHeaderStruct
+	assert.EqualValues(t, newNoMatch(regxp, "-"), setStructMatchIndex(reflect.ValueOf(&value).Elem(), regxp, nil, "-", nil))
+}
+
+func TestFieldError(t *testing.T) {
+	var value ParentHeaderStruct
+	err := Parse("[WARN: 99999999999999999999] 34", &value)
+	assert.EqualValues(t, &FieldError{"Header.Code", "code", "99999999999999999999", &OutOfRange{reflect.TypeOf(0), "99999999999999999999",
+		&strconv.NumError{Func: "ParseInt", Num: "99999999999999999999", Err: strconv.ErrRange}}}, err)
+	assert.ErrorIs(t, err, strconv.ErrRange)
+	assert.EqualError(t, err, `field Header.Code: capture group code value "99999999999999999999": 99999999999999999999 is out of range of int`)
+
+	var b struct {
+		StructExp `structexp:"^{{test}}$"`
+		Value     bool `structexp.name:"test" structexp.exp:"\\w+"`
+	}
+	err = Parse("yes", &b)
+	assert.EqualError(t, err, `field Value: capture group test value "yes": strconv.ParseBool: parsing "yes": invalid syntax`)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestScanBalanced(t *testing.T) {
+	type TestCase struct {
+		Name  string
+		Input string
+		Start int
+		End   int
+	}
+
+	testCases := []TestCase{
+		{Name: "Flat", Input: "(a) b", Start: 0, End: 3},
+		{Name: "Nested", Input: "x (a (b) [c {d}]) e)", Start: 2, End: 17},
+		{Name: "Unclosed", Input: "(a (b)", Start: 0, End: -1},
+		{Name: "Mismatched", Input: "(a]", Start: 0, End: -1},
+		{Name: "NotBracket", Input: "a (b)", Start: 0, End: -1},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.End, scanBalanced(tc.Input, tc.Start))
+		})
+	}
+}
+
+func TestBracketedTokenAll(t *testing.T) {
+	type Call struct {
+		StructExp `structexp:"{{body:bracketed}} {{rest}}"`
+		Body      string `structexp.name:"body"`
+		Rest      string `structexp.name:"rest" structexp.exp:"\\w+"`
+	}
+	expected := []Call{{Body: "(a (b) c)", Rest: "x"}, {Body: "(d)", Rest: "y"}}
+
+	var calls []Call
+	require.NoError(t, ParseAll("(a (b) c) x (d) y", &calls))
+	assert.EqualValues(t, expected, calls)
+
+	parser := MustCompile[Call]()
+	calls = nil
+	for call, err := range parser.All("(a (b) c) x (d) y") {
+		require.NoError(t, err)
+		calls = append(calls, call)
+	}
+	assert.EqualValues(t, expected, calls)
+
+	call, err := parser.ParseBytes([]byte("(a (b) c) x"))
+	require.NoError(t, err)
+	assert.EqualValues(t, expected[0], call)
+}
+
+func TestBracketedTokenAllWordBoundary(t *testing.T) {
+	type Call struct {
+		StructExp `structexp:"\\b{{name}}{{args:bracketed}}{{tail}}"`
+		Name      string `structexp.name:"name" structexp.exp:"[a-z]"`
+		Args      string `structexp.name:"args"`
+		Tail      string `structexp.name:"tail" structexp.exp:"[a-z]"`
+	}
+	// The z after the first match is not at a word boundary
+	expected := []Call{{Name: "x", Args: "(1)", Tail: "y"}}
+
+	var calls []Call
+	require.NoError(t, ParseAll("x(1)yz(2)w", &calls))
+	assert.EqualValues(t, expected, calls)
+
+	calls = nil
+	for call, err := range MustCompile[Call]().All("x(1)yz(2)w") {
+		require.NoError(t, err)
+		calls = append(calls, call)
+	}
+	assert.EqualValues(t, expected, calls)
+}
@@ -0,0 +1,47 @@
+package structexptest
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+)
+
+// Benchmark benchmarks the parser over the corpus lines, parsing one line per
+// iteration and cycling through the corpus. Along with ns/op, it reports the
+// allocations per operation, and the fraction of lines parsed without error
+// as the match-rate metric, so regressions in a template's performance on
+// real data can be measured.
+func Benchmark[T any](b *testing.B, p *structexp.Parser[T], corpus []string) {
+	b.Helper()
+	if len(corpus) == 0 {
+		b.Fatal("empty corpus")
+		return
+	}
+
+	var bytes int64
+	for _, line := range corpus {
+		bytes += int64(len(line))
+	}
+	b.SetBytes(bytes / int64(len(corpus)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	matched := 0
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(corpus[i%len(corpus)]); err == nil {
+			matched++
+		}
+	}
+	b.ReportMetric(float64(matched)/float64(b.N), "match-rate")
+}
+
+// BenchmarkFile benchmarks the parser over the lines of a corpus file, like Benchmark
+func BenchmarkFile[T any](b *testing.B, p *structexp.Parser[T], corpusFile string) {
+	b.Helper()
+	corpus, err := ReadCorpus(corpusFile)
+	if err != nil {
+		b.Fatalf("reading corpus: %v", err)
+		return
+	}
+	Benchmark(b, p, corpus)
+}
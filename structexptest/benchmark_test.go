@@ -0,0 +1,12 @@
+package structexptest
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/densestvoid/structexp/presets"
+)
+
+func BenchmarkRequestLine(b *testing.B) {
+	BenchmarkFile(b, structexp.MustCompile[presets.HTTPRequestLine](), "testdata/requests.txt")
+}
@@ -0,0 +1,162 @@
+package structexptest
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"testing"
+
+	"github.com/densestvoid/structexp"
+)
+
+// GroupCoverage is the coverage of a named capture group
+type GroupCoverage struct {
+	Name string
+	// Optional is set if the group can be absent from a match
+	Optional bool
+	// Lines is the number of matched lines the group participated in
+	Lines int
+}
+
+// BranchCoverage is the coverage of a single branch of an alternation
+type BranchCoverage struct {
+	Alternation string
+	Branch      string
+	// Lines is the number of matched lines the branch was taken in
+	Lines int
+}
+
+// CoverageReport is the coverage of a struct's regular expression over a corpus,
+// analogous to code coverage. Alternations and branches are reported in their
+// simplified form, as parsed by regexp/syntax, so they may differ slightly from
+// how they are written in the struct's tags.
+type CoverageReport struct {
+	Lines    int
+	Matched  int
+	Groups   []GroupCoverage
+	Branches []BranchCoverage
+}
+
+// MatchRate is the fraction of lines that matched
+func (r *CoverageReport) MatchRate() float64 {
+	if r.Lines == 0 {
+		return 0
+	}
+	return float64(r.Matched) / float64(r.Lines)
+}
+
+func (r *CoverageReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "matched %d/%d lines (%.1f%%)\n", r.Matched, r.Lines, 100*r.MatchRate())
+	for _, group := range r.Groups {
+		optional := ""
+		if group.Optional {
+			optional = " (optional)"
+		}
+		fmt.Fprintf(&b, "group %s%s: %d lines\n", group.Name, optional, group.Lines)
+	}
+	for _, branch := range r.Branches {
+		fmt.Fprintf(&b, "branch %s of %s: %d lines\n", branch.Branch, branch.Alternation, branch.Lines)
+	}
+	return b.String()
+}
+
+// Coverage reports the coverage of T's regular expression over the lines
+func Coverage[T any](lines []string) (*CoverageReport, error) {
+	p, err := structexp.Compile[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := syntax.Parse(p.Regexp().String(), syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	report := &CoverageReport{Lines: len(lines)}
+	var branchGroups []string
+	instrument(exp, false, report, &branchGroups)
+
+	instrumented, err := regexp.Compile(exp.String())
+	if err != nil {
+		return nil, err
+	}
+	groupIndexes := make([]int, len(report.Groups))
+	for i, group := range report.Groups {
+		groupIndexes[i] = instrumented.SubexpIndex(group.Name)
+	}
+	branchIndexes := make([]int, len(branchGroups))
+	for i, name := range branchGroups {
+		branchIndexes[i] = instrumented.SubexpIndex(name)
+	}
+
+	for _, line := range lines {
+		loc := instrumented.FindStringSubmatchIndex(line)
+		if loc == nil {
+			continue
+		}
+		report.Matched++
+		for i, idx := range groupIndexes {
+			if loc[2*idx] >= 0 {
+				report.Groups[i].Lines++
+			}
+		}
+		for i, idx := range branchIndexes {
+			if loc[2*idx] >= 0 {
+				report.Branches[i].Lines++
+			}
+		}
+	}
+	return report, nil
+}
+
+// CoverageFile reports the coverage of T's regular expression over a corpus file
+func CoverageFile[T any](corpusFile string) (*CoverageReport, error) {
+	lines, err := ReadCorpus(corpusFile)
+	if err != nil {
+		return nil, err
+	}
+	return Coverage[T](lines)
+}
+
+// LogCoverage logs the coverage of T's regular expression over a corpus file
+func LogCoverage[T any](t testing.TB, corpusFile string) *CoverageReport {
+	t.Helper()
+	report, err := CoverageFile[T](corpusFile)
+	if err != nil {
+		t.Fatalf("coverage of %s: %v", corpusFile, err)
+		return nil
+	}
+	t.Logf("coverage of %s:\n%s", corpusFile, report)
+	return report
+}
+
+// instrument records the named groups of the expression, and wraps every
+// alternation branch in a capture group to detect which branches are taken
+func instrument(exp *syntax.Regexp, optional bool, report *CoverageReport, branchGroups *[]string) {
+	// nolint:exhaustive // unnecessary
+	switch exp.Op {
+	case syntax.OpCapture:
+		if exp.Name != "" {
+			report.Groups = append(report.Groups, GroupCoverage{Name: exp.Name, Optional: optional})
+		}
+	case syntax.OpStar, syntax.OpQuest:
+		optional = true
+	case syntax.OpRepeat:
+		optional = optional || exp.Min == 0
+	case syntax.OpAlternate:
+		alternation := exp.String()
+		for i, sub := range exp.Sub {
+			name := fmt.Sprintf("structexptest_branch%d", len(*branchGroups))
+			*branchGroups = append(*branchGroups, name)
+			report.Branches = append(report.Branches, BranchCoverage{Alternation: alternation, Branch: sub.String()})
+			instrument(sub, true, report, branchGroups)
+			exp.Sub[i] = &syntax.Regexp{Op: syntax.OpCapture, Name: name, Sub: []*syntax.Regexp{sub}}
+		}
+		return
+	}
+
+	for _, sub := range exp.Sub {
+		instrument(sub, optional, report, branchGroups)
+	}
+}
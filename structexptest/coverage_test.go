@@ -0,0 +1,51 @@
+package structexptest
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Access struct {
+	structexp.StructExp `structexp:"^(?:{{method}}|-) {{path}}(?: {{code}})?$"`
+	Method              string `structexp.name:"method" structexp.exp:"GET|POST"`
+	Path                string `structexp.name:"path" structexp.exp:"/\\S*"`
+	Code                int    `structexp.name:"code"`
+}
+
+func TestCoverage(t *testing.T) {
+	report, err := Coverage[Access]([]string{
+		"GET /",
+		"POST /login 200",
+		"- /health 200",
+		"DELETE /",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Lines)
+	assert.Equal(t, 3, report.Matched)
+	assert.Equal(t, 0.75, report.MatchRate())
+	assert.Equal(t, []GroupCoverage{
+		{Name: "method", Optional: true, Lines: 2},
+		{Name: "path", Optional: false, Lines: 3},
+		{Name: "code", Optional: true, Lines: 2},
+	}, report.Groups)
+
+	lines := map[string]int{}
+	for _, branch := range report.Branches {
+		lines[branch.Branch] = branch.Lines
+	}
+	assert.Equal(t, map[string]int{
+		"(?P<method>GET|POST)": 2,
+		"-":                    1,
+		"GET":                  1,
+		"POST":                 1,
+	}, lines)
+}
+
+func TestLogCoverage(t *testing.T) {
+	report := LogCoverage[Access](t, "testdata/requests.txt")
+	assert.Equal(t, 1, report.Matched)
+}
@@ -0,0 +1,145 @@
+package structexptest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/structexp"
+)
+
+// FieldDiff is a field whose value differs between two parses of a line.
+// Fields missing from one of the struct types have a nil value.
+type FieldDiff struct {
+	Field string
+	Old   interface{}
+	New   interface{}
+}
+
+// LineDiff is a corpus line whose parse results differ
+type LineDiff struct {
+	// Line is the 1-based index of the line in the corpus
+	Line   int
+	Input  string
+	OldErr error
+	NewErr error
+	Fields []FieldDiff
+}
+
+func (d LineDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "line %d %q:", d.Line, d.Input)
+	if (d.OldErr == nil) != (d.NewErr == nil) {
+		fmt.Fprintf(&b, "\n\terror: %v -> %v", d.OldErr, d.NewErr)
+	}
+	for _, field := range d.Fields {
+		fmt.Fprintf(&b, "\n\t%s: %#v -> %#v", field.Field, field.Old, field.New)
+	}
+	return b.String()
+}
+
+// Diff parses every line into new values, the addresses of structexp structs,
+// from newOld and newNew, and reports the lines whose results differ: either
+// only one of them parses, or their fields of the same name have different
+// values. Nested struct fields are named by their path, such as Header.Code.
+// This supports safely refactoring a struct's expressions, by comparing the
+// old and new versions of the struct over a corpus of sample lines.
+func Diff(newOld, newNew func() interface{}, lines []string) []LineDiff {
+	var diffs []LineDiff
+	for i, line := range lines {
+		oldValue, newValue := newOld(), newNew()
+		diff := LineDiff{
+			Line:   i + 1,
+			Input:  line,
+			OldErr: structexp.Parse(line, oldValue),
+			NewErr: structexp.Parse(line, newValue),
+		}
+
+		if diff.OldErr == nil && diff.NewErr == nil {
+			diff.Fields = diffFields(flatten(oldValue), flatten(newValue))
+			if len(diff.Fields) == 0 {
+				continue
+			}
+		} else if (diff.OldErr == nil) == (diff.NewErr == nil) {
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// DiffFile reports the lines of a corpus file whose results differ, like Diff
+func DiffFile(newOld, newNew func() interface{}, corpusFile string) ([]LineDiff, error) {
+	lines, err := ReadCorpus(corpusFile)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(newOld, newNew, lines), nil
+}
+
+// NoDiff fails the test for every line of the corpus file whose results differ, like Diff
+func NoDiff(t testing.TB, newOld, newNew func() interface{}, corpusFile string) {
+	t.Helper()
+	diffs, err := DiffFile(newOld, newNew, corpusFile)
+	if err != nil {
+		t.Fatalf("reading corpus: %v", err)
+		return
+	}
+	for _, diff := range diffs {
+		t.Errorf("%s: %s", corpusFile, diff)
+	}
+}
+
+// diffFields compares the flattened fields of the same name
+func diffFields(oldFields, newFields map[string]interface{}) []FieldDiff {
+	names := make([]string, 0, len(oldFields)+len(newFields))
+	for name := range oldFields {
+		names = append(names, name)
+	}
+	for name := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []FieldDiff
+	for _, name := range names {
+		if old, new := oldFields[name], newFields[name]; !reflect.DeepEqual(old, new) {
+			diffs = append(diffs, FieldDiff{Field: name, Old: old, New: new})
+		}
+	}
+	return diffs
+}
+
+// flatten maps the exported fields of the struct, at any depth, by their path
+func flatten(i interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	flattenValue(reflect.ValueOf(i), "", fields)
+	return fields
+}
+
+func flattenValue(v reflect.Value, path string, fields map[string]interface{}) {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || v.Type() == reflect.TypeOf(time.Time{}) {
+		fields[path] = v.Interface()
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" || field.Type == reflect.TypeOf(structexp.StructExp{}) {
+			continue
+		}
+		name := field.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		flattenValue(v.Field(i), name, fields)
+	}
+}
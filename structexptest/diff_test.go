@@ -0,0 +1,56 @@
+package structexptest
+
+import (
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type AccessV2 struct {
+	structexp.StructExp `structexp:"^{{method}} {{path}}(?:\\?\\S*)? {{code}}$"`
+	Method              string `structexp.name:"method" structexp.exp:"[A-Z]+"`
+	Path                string `structexp.name:"path" structexp.exp:"/[^\\s?]*"`
+	Code                int    `structexp.name:"code"`
+}
+
+func TestDiff(t *testing.T) {
+	diffs := Diff(
+		func() interface{} { return &Access{} },
+		func() interface{} { return &AccessV2{} },
+		[]string{
+			"GET / 200",
+			"DELETE / 200",
+			"- /health 200",
+			"GET /search?q=1 200",
+			"GET /search 200",
+		},
+	)
+
+	require.Len(t, diffs, 3)
+	assert.Equal(t, 2, diffs[0].Line)
+	assert.Error(t, diffs[0].OldErr)
+	assert.NoError(t, diffs[0].NewErr)
+	assert.Equal(t, 3, diffs[1].Line)
+	assert.NoError(t, diffs[1].OldErr)
+	assert.Error(t, diffs[1].NewErr)
+	assert.Equal(t, 4, diffs[2].Line)
+	assert.Equal(t, []FieldDiff{{Field: "Path", Old: "/search?q=1", New: "/search"}}, diffs[2].Fields)
+}
+
+func TestNoDiff(t *testing.T) {
+	NoDiff(t,
+		func() interface{} { return &Access{} },
+		func() interface{} { return &Access{} },
+		"testdata/requests.txt",
+	)
+
+	r := &recorder{TB: t}
+	NoDiff(r,
+		func() interface{} { return &Access{} },
+		func() interface{} { return &AccessV2{} },
+		"testdata/requests.txt",
+	)
+	assert.Len(t, r.errors, 0)
+}
@@ -0,0 +1,225 @@
+package structexptest
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/densestvoid/structexp"
+)
+
+const structexptestPath = "github.com/densestvoid/structexp/structexptest"
+
+// FixtureOptions configures the test file written by EmitFixtures
+type FixtureOptions struct {
+	// Package is the package clause of the test file, such as mypkg_test
+	Package string
+	// PackagePath is the import path of the package the test file is in,
+	// whose types are not qualified. It is empty for external test packages.
+	PackagePath string
+	// TestName is the name of the test function, such as TestRequestFixtures
+	TestName string
+}
+
+// EmitFixtures writes a Go test file to w, with a table-driven test of a case
+// for every corpus line the parser parses without error, wanting the value it
+// parsed into. Lines that fail to parse are left out. The test checks the cases
+// with Run, parsing with the struct's own template, to bootstrap a regression
+// suite from the current behavior of an existing pipeline.
+//
+// Errors occur if a parsed value has a field that cannot be written as a
+// Go literal, such as a func or chan, or the test file fails to format.
+func EmitFixtures[T any](w io.Writer, p *structexp.Parser[T], corpus []string, opts FixtureOptions) error {
+	e := &fixtureEmitter{
+		pkgPath: opts.PackagePath,
+		imports: map[string]string{structexptestPath: "structexptest"},
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	typeName := e.typeName(t)
+
+	var cases bytes.Buffer
+	for _, line := range corpus {
+		v, err := p.Parse(line)
+		if err != nil {
+			continue
+		}
+		want, err := e.literal(reflect.ValueOf(v))
+		if err != nil {
+			return fmt.Errorf("%q: %w", line, err)
+		}
+		fmt.Fprintf(&cases, "{\nInput: %s,\nWant: &%s,\n},\n", strconv.Quote(line), want)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by structexptest.EmitFixtures from %d corpus lines.\n\n", len(corpus))
+	fmt.Fprintf(&src, "package %s\n\n", opts.Package)
+	src.WriteString("import (\n\"testing\"\n\n")
+	paths := make([]string, 0, len(e.imports))
+	for importPath := range e.imports {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+	for _, importPath := range paths {
+		fmt.Fprintf(&src, "%s\n", strconv.Quote(importPath))
+	}
+	src.WriteString(")\n\n")
+	fmt.Fprintf(&src, "func %s(t *testing.T) {\n", opts.TestName)
+	fmt.Fprintf(&src, "structexptest.Run(t, func() interface{} { return new(%s) }, []structexptest.Case{\n", typeName)
+	src.Write(cases.Bytes())
+	src.WriteString("})\n}\n")
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// fixtureEmitter writes Go literals of values,
+// collecting the imports of their types
+type fixtureEmitter struct {
+	pkgPath string
+	imports map[string]string
+}
+
+// typeName returns the name of the type, qualified by
+// its package name if it is not in the emitter's package
+func (e *fixtureEmitter) typeName(t reflect.Type) string {
+	if t.Name() == "" {
+		// nolint:exhaustive // unnecessary
+		switch t.Kind() {
+		case reflect.Ptr:
+			return "*" + e.typeName(t.Elem())
+		case reflect.Slice:
+			return "[]" + e.typeName(t.Elem())
+		case reflect.Array:
+			return fmt.Sprintf("[%d]%s", t.Len(), e.typeName(t.Elem()))
+		case reflect.Map:
+			return fmt.Sprintf("map[%s]%s", e.typeName(t.Key()), e.typeName(t.Elem()))
+		}
+		return t.String()
+	}
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+	if t.PkgPath() == e.pkgPath {
+		return t.Name()
+	}
+	name := path.Base(t.PkgPath())
+	e.imports[t.PkgPath()] = name
+	return name + "." + t.Name()
+}
+
+// literal returns the Go literal of the value, omitting zero struct fields
+func (e *fixtureEmitter) literal(v reflect.Value) (string, error) {
+	// nolint:exhaustive // unnecessary
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		s := fmt.Sprint(v.Interface())
+		if v.Type().Name() != v.Kind().String() {
+			return fmt.Sprintf("%s(%s)", e.typeName(v.Type()), s), nil
+		}
+		return s, nil
+	case reflect.String:
+		s := strconv.Quote(v.String())
+		if v.Type().Name() != "string" {
+			return fmt.Sprintf("%s(%s)", e.typeName(v.Type()), s), nil
+		}
+		return s, nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		elem, err := e.literal(v.Elem())
+		if err != nil {
+			return "", err
+		}
+		if v.Elem().Kind() != reflect.Struct {
+			return "", fmt.Errorf("pointer to %v cannot be written as a literal", v.Elem().Type())
+		}
+		return "&" + elem, nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		return e.literal(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "nil", nil
+		}
+		var b bytes.Buffer
+		fmt.Fprintf(&b, "%s{", e.typeName(v.Type()))
+		for i := 0; i < v.Len(); i++ {
+			elem, err := e.literal(v.Index(i))
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s,", elem)
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	case reflect.Map:
+		if v.IsNil() {
+			return "nil", nil
+		}
+		entries := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := e.literal(iter.Key())
+			if err != nil {
+				return "", err
+			}
+			val, err := e.literal(iter.Value())
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, fmt.Sprintf("%s: %s,", key, val))
+		}
+		sort.Strings(entries)
+		var b bytes.Buffer
+		fmt.Fprintf(&b, "%s{", e.typeName(v.Type()))
+		for _, entry := range entries {
+			b.WriteString(entry)
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	case reflect.Struct:
+		var b bytes.Buffer
+		fmt.Fprintf(&b, "%s{", e.typeName(v.Type()))
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if v.Field(i).IsZero() {
+				continue
+			}
+			if !field.IsExported() {
+				return "", fmt.Errorf("unexported field %v.%s cannot be written as a literal", v.Type(), field.Name)
+			}
+			val, err := e.literal(v.Field(i))
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s: %s,", field.Name, val)
+		}
+		b.WriteString("}")
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf("%v cannot be written as a literal", v.Type())
+	}
+}
+
+// EmitFixturesFile writes a Go test file for the lines of a corpus file, like EmitFixtures
+func EmitFixturesFile[T any](w io.Writer, p *structexp.Parser[T], corpusFile string, opts FixtureOptions) error {
+	corpus, err := ReadCorpus(corpusFile)
+	if err != nil {
+		return err
+	}
+	return EmitFixtures(w, p, corpus, opts)
+}
@@ -0,0 +1,55 @@
+package structexptest
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/densestvoid/structexp"
+	"github.com/densestvoid/structexp/presets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitFixtures(t *testing.T) {
+	var got bytes.Buffer
+	err := EmitFixturesFile(&got, structexp.MustCompile[presets.HTTPRequestLine](), "testdata/requests.txt", FixtureOptions{
+		Package:  "presets_test",
+		TestName: "TestRequestLineFixtures",
+	})
+	require.NoError(t, err)
+
+	goldenFile := "testdata/requests_fixtures_test.go" + GoldenExt
+	if *update {
+		require.NoError(t, os.WriteFile(goldenFile, got.Bytes(), 0o644))
+		return
+	}
+	want, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), got.String())
+}
+
+func TestEmitFixturesLiteral(t *testing.T) {
+	type local struct {
+		Names  []string
+		Counts map[string]int
+		Ptr    *presets.HTTPVersion
+		Any    interface{}
+		Zero   int
+	}
+
+	e := &fixtureEmitter{pkgPath: "github.com/densestvoid/structexp/structexptest", imports: map[string]string{}}
+	got, err := e.literal(reflect.ValueOf(local{
+		Names:  []string{"a", "b"},
+		Counts: map[string]int{"b": 2, "a": 1},
+		Ptr:    &presets.HTTPVersion{Major: 2},
+		Any:    presets.HTTPVersion{Minor: 1},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, `local{Names: []string{"a","b",},Counts: map[string]int{"a": 1,"b": 2,},Ptr: &presets.HTTPVersion{Major: 2,},Any: presets.HTTPVersion{Minor: 1,},}`, got)
+	assert.Equal(t, map[string]string{"github.com/densestvoid/structexp/presets": "presets"}, e.imports)
+
+	_, err = e.literal(reflect.ValueOf(struct{ F func() }{func() {}}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,231 @@
+// Package structexptest provides helpers for testing structexp structs
+// against sample inputs, from single inputs and tables of cases to corpus
+// files of sample lines checked against golden files.
+package structexptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/densestvoid/structexp"
+)
+
+// GoldenExt is the extension appended to a corpus file name for its golden file
+const GoldenExt = ".golden"
+
+// QuotedExt is the extension of a corpus file holding a Go quoted string per
+// line, such as "+OK\r\n", for samples that cannot be held in a line as is,
+// such as records framed by CRLF. Each line is unquoted into its sample.
+const QuotedExt = ".quoted"
+
+var update = flag.Bool("structexptest.update", false, "update structexptest golden files")
+
+// Match parses the input into v, the address of a structexp struct, and fails
+// the test if the input does not parse or v does not then equal want.
+func Match(t testing.TB, v interface{}, input string, want interface{}) {
+	t.Helper()
+	if err := structexp.Parse(input, v); err != nil {
+		t.Errorf("Parse(%q) returned error: %v", input, err)
+		return
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Parse(%q) =\n\t%+v\nwant\n\t%+v", input, v, want)
+	}
+}
+
+// NoMatch fails the test if the input parses into v, the address of a structexp struct
+func NoMatch(t testing.TB, v interface{}, input string) {
+	t.Helper()
+	if err := structexp.Parse(input, v); err == nil {
+		t.Errorf("Parse(%q) = %+v, want an error", input, v)
+	}
+}
+
+// ValidateDeclared fails the test with the problems of the struct types
+// declared with structexp.Declare, found by structexp.ValidateDeclared
+func ValidateDeclared(t testing.TB) {
+	t.Helper()
+	if err := structexp.ValidateDeclared(); err != nil {
+		t.Errorf("ValidateDeclared() returned error: %v", err)
+	}
+}
+
+// Case is a single sample input, and the value it should parse into.
+// A nil Want means the input should not parse.
+type Case struct {
+	Name  string
+	Input string
+	Want  interface{}
+}
+
+// Run runs each case as a subtest, parsing its input into
+// a new value, the address of a structexp struct, from newValue
+func Run(t *testing.T, newValue func() interface{}, cases []Case) {
+	t.Helper()
+	for _, c := range cases {
+		c := c
+		name := c.Name
+		if name == "" {
+			name = c.Input
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Helper()
+			if c.Want == nil {
+				NoMatch(t, newValue(), c.Input)
+				return
+			}
+			Match(t, newValue(), c.Input, c.Want)
+		})
+	}
+}
+
+// goldenResult is the parse result of a single corpus line
+type goldenResult struct {
+	Value interface{} `json:"value,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// RunGolden parses every line of the corpus file into a new value, the address
+// of a structexp struct, from newValue, and compares the results to the golden
+// file with the same name followed by GoldenExt. The golden file holds a JSON
+// line per corpus line, with either the parsed value or the parse error, and is
+// written instead of compared when the -structexptest.update flag is set.
+func RunGolden(t testing.TB, newValue func() interface{}, corpusFile string) {
+	t.Helper()
+	lines, err := ReadCorpus(corpusFile)
+	if err != nil {
+		t.Fatalf("reading corpus: %v", err)
+	}
+	got := goldenResults(t, newValue, lines)
+
+	goldenFile := corpusFile + GoldenExt
+	if *update {
+		if err := os.WriteFile(goldenFile, got, 0o644); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -structexptest.update to create it): %v", err)
+	}
+	compareGolden(t, goldenFile, got, want)
+}
+
+// RunGoldenFS is like RunGolden, for a corpus file and its golden file in the
+// file system, such as the corpora embedded in a package, like those of the
+// presets package. The golden file is never written, even with the
+// -structexptest.update flag, as file systems are read-only.
+func RunGoldenFS(t testing.TB, newValue func() interface{}, fsys fs.FS, corpusFile string) {
+	t.Helper()
+	lines, err := ReadCorpusFS(fsys, corpusFile)
+	if err != nil {
+		t.Fatalf("reading corpus: %v", err)
+	}
+	got := goldenResults(t, newValue, lines)
+
+	goldenFile := corpusFile + GoldenExt
+	want, err := fs.ReadFile(fsys, goldenFile)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	compareGolden(t, goldenFile, got, want)
+}
+
+// goldenResults returns the golden file contents of the results
+// of parsing the corpus lines into new values from newValue
+func goldenResults(t testing.TB, newValue func() interface{}, lines []string) []byte {
+	t.Helper()
+	var got bytes.Buffer
+	for _, line := range lines {
+		var result goldenResult
+		v := newValue()
+		if err := structexp.Parse(line, v); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Value = v
+		}
+		b, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("encoding result of %q: %v", line, err)
+		}
+		got.Write(b)
+		got.WriteByte('\n')
+	}
+	return got.Bytes()
+}
+
+// compareGolden fails the test with every line of the results that differs from the golden file
+func compareGolden(t testing.TB, goldenFile string, got, want []byte) {
+	t.Helper()
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			t.Errorf("%s:%d: got\n\t%s\nwant\n\t%s", goldenFile, i+1, g, w)
+		}
+	}
+}
+
+// ReadCorpus reads the lines of a corpus file, skipping empty lines,
+// and unquoting them if the file has the QuotedExt extension
+func ReadCorpus(corpusFile string) ([]string, error) {
+	f, err := os.Open(corpusFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readCorpus(f, corpusFile)
+}
+
+// ReadCorpusFS reads the lines of a corpus file in the file system, like ReadCorpus
+func ReadCorpusFS(fsys fs.FS, corpusFile string) ([]string, error) {
+	f, err := fsys.Open(corpusFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readCorpus(f, corpusFile)
+}
+
+// readCorpus reads the lines of the named corpus, skipping empty lines
+func readCorpus(r io.Reader, corpusFile string) ([]string, error) {
+	quoted := strings.HasSuffix(corpusFile, QuotedExt)
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if quoted {
+			var err error
+			if line, err = strconv.Unquote(line); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", corpusFile, n, err)
+			}
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", corpusFile, err)
+	}
+	return lines, nil
+}
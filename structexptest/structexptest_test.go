@@ -0,0 +1,114 @@
+package structexptest
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/densestvoid/structexp"
+	"github.com/densestvoid/structexp/presets"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+func TestMatch(t *testing.T) {
+	want := &presets.HTTPRequestLine{Method: "GET", Target: "/", Proto: presets.HTTPVersion{Major: 1, Minor: 1}}
+	Match(t, &presets.HTTPRequestLine{}, "GET / HTTP/1.1", want)
+
+	r := &recorder{TB: t}
+	Match(r, &presets.HTTPRequestLine{}, "GET /other HTTP/1.1", want)
+	Match(r, &presets.HTTPRequestLine{}, "GET /", want)
+	assert.Len(t, r.errors, 2)
+}
+
+func TestNoMatch(t *testing.T) {
+	NoMatch(t, &presets.HTTPRequestLine{}, "GET /")
+
+	r := &recorder{TB: t}
+	NoMatch(r, &presets.HTTPRequestLine{}, "GET / HTTP/1.1")
+	assert.Len(t, r.errors, 1)
+}
+
+type undeclaredGroupStruct struct {
+	structexp.StructExp `structexp:"^{{method}}$"`
+	Method              string
+}
+
+func TestValidateDeclared(t *testing.T) {
+	structexp.Declare[presets.HTTPRequestLine]()
+	ValidateDeclared(t)
+
+	structexp.Declare[undeclaredGroupStruct]()
+	r := &recorder{TB: t}
+	ValidateDeclared(r)
+	assert.Len(t, r.errors, 1)
+}
+
+func TestRun(t *testing.T) {
+	Run(t, func() interface{} { return &presets.HTTPRequestLine{} }, []Case{
+		{
+			Input: "GET / HTTP/1.1",
+			Want:  &presets.HTTPRequestLine{Method: "GET", Target: "/", Proto: presets.HTTPVersion{Major: 1, Minor: 1}},
+		},
+		{
+			Name:  "MissingProto",
+			Input: "GET /",
+			Want:  nil,
+		},
+	})
+}
+
+func TestRunGolden(t *testing.T) {
+	RunGolden(t, func() interface{} { return &presets.HTTPRequestLine{} }, "testdata/requests.txt")
+}
+
+func TestRunGoldenQuoted(t *testing.T) {
+	RunGolden(t, func() interface{} { return &presets.RESPSimple{} }, "testdata/replies.quoted")
+}
+
+func TestReadCorpusQuoted(t *testing.T) {
+	lines, err := ReadCorpus("testdata/replies.quoted")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"+OK\r\n", ":1000\r\n", "-ERR unknown command\n"}, lines)
+
+	_, err = ReadCorpusFS(fstest.MapFS{"bad.quoted": {Data: []byte(`"+OK\r\n"` + "\n+OK\n")}}, "bad.quoted")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Contains(t, err.Error(), "bad.quoted:2")
+}
+
+func TestRunGoldenMismatch(t *testing.T) {
+	if *update {
+		t.Skip("would update the golden file with mismatched results")
+	}
+
+	r := &recorder{TB: t}
+	RunGolden(r, func() interface{} { return &presets.HTTPStatusLine{} }, "testdata/requests.txt")
+	// Its unmatched line also fails to match at a different byte
+	assert.Len(t, r.errors, 3)
+}
+
+func TestRunGoldenFS(t *testing.T) {
+	RunGoldenFS(t, func() interface{} { return &presets.HTTPRequestLine{} }, os.DirFS("testdata"), "requests.txt")
+	RunGoldenFS(t, func() interface{} { return &presets.HTTPRequestLine{} }, presets.Corpora(), "HTTPRequestLine.txt")
+
+	r := &recorder{TB: t}
+	RunGoldenFS(r, func() interface{} { return &presets.HTTPStatusLine{} }, os.DirFS("testdata"), "requests.txt")
+	assert.Len(t, r.errors, 3)
+}
@@ -0,0 +1,118 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
+
+const subParserKey = "structexp.sub"
+
+// SubParser parses a field's capture into the field, given its address.
+// Sub-parsers are registered by name with RegisterSubParser and applied to
+// a field with the structexp.sub tag, to parse the coarse region captured
+// by the field's expression in a second stage.
+type SubParser func(s string, i interface{}) error
+
+// Built-in sub-parsers
+const (
+	// StructExpSubParser parses the capture with the field's own StructExp expression
+	StructExpSubParser = "structexp"
+	// JSONSubParser unmarshals the capture as JSON
+	JSONSubParser = "json"
+	// KeyValueSubParser parses the capture as whitespace separated key=value pairs
+	KeyValueSubParser = "kv"
+)
+
+// Registry holds sub-parsers and transforms by name, isolating those registered
+// by one user of the package, such as a tenant of a service, from another's.
+// Names not registered in a Registry are looked up in the default registry,
+// which holds the built-in sub-parsers and transforms, and those registered
+// with RegisterSubParser and RegisterTransform. Parsers use a Registry given
+// by the WithRegistry option. Patterns, variants, and factories are not held
+// by a Registry, and are shared by every Parser.
+//
+// A Registry is safe for concurrent use by multiple goroutines.
+type Registry struct {
+	parent     *Registry
+	mu         sync.RWMutex
+	subParsers map[string]SubParser
+	transforms map[string]Transform
+}
+
+var defaultRegistry = &Registry{subParsers: map[string]SubParser{}, transforms: map[string]Transform{}}
+
+// NewRegistry returns an empty Registry, falling back to the default registry
+func NewRegistry() *Registry {
+	return &Registry{
+		parent:     defaultRegistry,
+		subParsers: map[string]SubParser{},
+		transforms: map[string]Transform{},
+	}
+}
+
+// WithRegistry looks up the sub-parsers and transforms of the
+// Parser's fields in the registry, instead of the default registry
+func WithRegistry(reg *Registry) Option {
+	return func(o *options) {
+		o.registry = reg
+	}
+}
+
+// fallbackSubParser returns a sub-parser trying each sub-parser in order,
+// resetting the value after each failure, until one parses the capture.
+// If all fail, their errors are returned in a SubParsersFailed error.
+func fallbackSubParser(names []string, subParsers []SubParser) SubParser {
+	return func(s string, i interface{}) error {
+		v := reflect.ValueOf(i).Elem()
+		errs := make([]error, 0, len(subParsers))
+		for _, subParser := range subParsers {
+			err := subParser(s, i)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return &SubParsersFailed{names, errs}
+	}
+}
+
+// Built-in sub-parsers are registered on init, since Parse itself looks up sub-parsers
+func init() {
+	RegisterSubParser(StructExpSubParser, Parse)
+	RegisterSubParser(JSONSubParser, func(s string, i interface{}) error {
+		return json.Unmarshal([]byte(s), i)
+	})
+	RegisterSubParser(KeyValueSubParser, ParseKeyValues)
+}
+
+// RegisterSubParser registers the sub-parser by name, for fields to reference
+// with the structexp.sub tag. Registering an existing name replaces it.
+func RegisterSubParser(name string, subParser SubParser) {
+	defaultRegistry.RegisterSubParser(name, subParser)
+}
+
+// RegisterSubParser registers the sub-parser by name in the registry only.
+// Registering an existing name replaces it, and hides the name in the default registry.
+func (r *Registry) RegisterSubParser(name string, subParser SubParser) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subParsers[name] = subParser
+	if r == defaultRegistry {
+		invalidateCompiledTypes()
+	}
+}
+
+func (r *Registry) lookupSubParser(name string) (SubParser, error) {
+	r.mu.RLock()
+	subParser, ok := r.subParsers[name]
+	r.mu.RUnlock()
+	if !ok {
+		if r.parent != nil {
+			return r.parent.lookupSubParser(name)
+		}
+		return nil, &UnknownSubParser{name}
+	}
+	return subParser, nil
+}
@@ -0,0 +1,57 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/densestvoid/structexp/convert"
+)
+
+// Built-in sub-parsers of time.Time fields, often listed as fallbacks of each
+// other for inputs whose timestamp format changed over time, such as with
+// the structexp.sub:"rfc3339,unix,unixmilli" tag
+const (
+	// RFC3339SubParser parses the capture with time.RFC3339Nano, which also accepts RFC 3339 without fractional seconds
+	RFC3339SubParser = "rfc3339"
+	// UnixSubParser parses the capture as decimal seconds since the Unix epoch
+	UnixSubParser = "unix"
+	// UnixMilliSubParser parses the capture as decimal milliseconds since the Unix epoch
+	UnixMilliSubParser = "unixmilli"
+)
+
+func init() {
+	RegisterSubParser(RFC3339SubParser, timeSubParser(func(s string) (time.Time, error) {
+		return time.Parse(time.RFC3339Nano, s)
+	}))
+	RegisterSubParser(UnixSubParser, timeSubParser(func(s string) (time.Time, error) {
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(sec, 0).UTC(), nil
+	}))
+	RegisterSubParser(UnixMilliSubParser, timeSubParser(func(s string) (time.Time, error) {
+		msec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.UnixMilli(msec).UTC(), nil
+	}))
+}
+
+// timeSubParser returns a sub-parser setting the address of a time.Time with the parse function
+func timeSubParser(parse func(s string) (time.Time, error)) SubParser {
+	return func(s string, i interface{}) error {
+		t, ok := i.(*time.Time)
+		if !ok {
+			return &convert.Unsupported{Type: reflect.TypeOf(i).Elem()}
+		}
+		parsed, err := parse(s)
+		if err != nil {
+			return err
+		}
+		*t = parsed
+		return nil
+	}
+}
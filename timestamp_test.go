@@ -0,0 +1,70 @@
+package structexp
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/densestvoid/structexp/convert"
+	"github.com/stretchr/testify/assert"
+)
+
+type TimestampStruct struct {
+	StructExp `structexp:"^{{time}} {{msg}}$"`
+	Time      time.Time `structexp.name:"time" structexp.sub:"rfc3339, unixmilli" structexp.exp:"\\S+"`
+	Message   string    `structexp.name:"msg"`
+}
+
+func TestFallbackSubParsers(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected TimestampStruct
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "RFC3339",
+			String:   "2021-03-04T05:06:07Z started",
+			Expected: TimestampStruct{Time: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Message: "started"},
+		},
+		{
+			Name:     "UnixMilli",
+			String:   "1614834367123 started",
+			Expected: TimestampStruct{Time: time.Date(2021, 3, 4, 5, 6, 7, 123000000, time.UTC), Message: "started"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := MustCompile[TimestampStruct]().Parse(tc.String)
+			assert.NoError(t, err)
+			assert.EqualValues(t, tc.Expected, v)
+		})
+	}
+}
+
+func TestFallbackSubParsersFailed(t *testing.T) {
+	_, err := MustCompile[TimestampStruct]().Parse("yesterday started")
+	var failed *SubParsersFailed
+	assert.True(t, errors.As(err, &failed))
+	assert.EqualValues(t, []string{"rfc3339", "unixmilli"}, failed.Names)
+	assert.Len(t, failed.Errors, 2)
+
+	var numErr *strconv.NumError
+	assert.True(t, errors.As(err, &numErr))
+}
+
+func TestUnixSubParser(t *testing.T) {
+	subParser, err := defaultRegistry.lookupSubParser(UnixSubParser)
+	assert.NoError(t, err)
+
+	var v time.Time
+	assert.NoError(t, subParser("1614834367", &v))
+	assert.EqualValues(t, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), v)
+
+	var i int
+	assert.IsType(t, &convert.Unsupported{}, subParser("1", &i))
+}
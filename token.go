@@ -0,0 +1,282 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// token is a built-in expression that can be applied to a placeholder by
+// suffixing its name with the token name: {{name:token}}
+type token struct {
+	Exp       string
+	Transform func(string) (string, error)
+	// Escape reverses Transform, when formatting a value back into the string
+	Escape func(string) string
+	// Scan returns the end of the capture starting at the offset in the input,
+	// found by scanning forward from it, or -1 to keep the end matched by Exp
+	Scan func(s string, start int) int
+}
+
+// Built-in placeholder tokens
+const (
+	// QuotedToken matches a double quoted string that may contain escaped quotes,
+	// and unescapes it before parsing: "say \"hi\"" is parsed as say "hi"
+	QuotedToken = "quoted"
+	// BracketedToken matches a region enclosed in balanced (), [], or {} brackets,
+	// which a regular expression alone cannot do. The region starts at the opening
+	// bracket where the expression matches it, and is scanned forward to its
+	// matching closing bracket, with the rest of the expression matched after it:
+	// "(a (b) c)" is parsed as is, and "(a [b)" is an error. Methods reading
+	// from an io.Reader in chunks, like AllMatches, do not scan the region.
+	BracketedToken = "bracketed"
+)
+
+var tokens = map[string]token{
+	QuotedToken: {
+		Exp:       `"(?:[^"\\]|\\.)*"`,
+		Transform: unquote,
+		Escape:    quote,
+	},
+	BracketedToken: {
+		Exp:       scanSentinelExp + `|[(\[{][\s\S]*?[)\]}]`,
+		Transform: balanced,
+		Scan:      scanBalanced,
+	},
+}
+
+// unquote removes the surrounding double quotes and unescapes
+// escaped double quotes and backslashes; other escapes are left as is
+func unquote(s string) (string, error) {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, `"`), `"`)
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String(), nil
+}
+
+// quote surrounds the string with double quotes, escaping
+// double quotes and backslashes; the reverse of unquote
+func quote(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+var closingBrackets = map[rune]rune{
+	'(': ')',
+	'[': ']',
+	'{': '}',
+}
+
+// balanced verifies the string is a single region of balanced brackets
+func balanced(s string) (string, error) {
+	var expected []rune
+	for i, r := range s {
+		switch r {
+		case '(', '[', '{':
+			expected = append(expected, closingBrackets[r])
+		case ')', ']', '}':
+			if len(expected) == 0 || expected[len(expected)-1] != r {
+				return "", &UnbalancedBrackets{s}
+			}
+			expected = expected[:len(expected)-1]
+		default:
+			continue
+		}
+		if len(expected) == 0 && i+1 != len(s) {
+			return "", &UnbalancedBrackets{s}
+		}
+	}
+	if len(expected) != 0 {
+		return "", &UnbalancedBrackets{s}
+	}
+	return s, nil
+}
+
+// scanBalanced returns the end of the region of balanced brackets
+// starting with the opening bracket at the offset in the string,
+// or -1 if it does not start with an opening bracket or is not closed
+func scanBalanced(s string, start int) int {
+	var expected []rune
+	for i, r := range s[start:] {
+		switch r {
+		case '(', '[', '{':
+			expected = append(expected, closingBrackets[r])
+		case ')', ']', '}':
+			if len(expected) == 0 || expected[len(expected)-1] != r {
+				return -1
+			}
+			expected = expected[:len(expected)-1]
+			if len(expected) == 0 {
+				return start + i + 1
+			}
+		default:
+			if len(expected) == 0 {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// scanSentinel replaces each scanned region of the input when it is matched
+// again, and is matched first by the expressions of the tokens with a Scan
+// function. It is a Unicode noncharacter, which does not appear in text.
+const (
+	scanSentinel    = "\uFDD0"
+	scanSentinelExp = `\x{FDD0}`
+)
+
+// scannedRegion is the capture of a field with a Scan function
+type scannedRegion struct {
+	group      int
+	start, end int
+}
+
+// scannedFields returns the fields with a Scan function
+func scannedFields(fields []*field) []*field {
+	var scanned []*field
+	for _, f := range fields {
+		if f.Scan != nil {
+			scanned = append(scanned, f)
+		}
+	}
+	return scanned
+}
+
+// scanMatch returns the location of the match of the searcher's regular
+// expression in the string, found searching from the position, with the
+// capture of each of the scanned fields ending where the field's Scan function
+// finds from its start. A regular expression cannot find such ends, like that
+// of a balanced bracket region, so it is matched again with every region
+// replaced by the scanSentinel, until the captures are the scanned regions. If
+// the rest of the expression does not match after a region, the location is
+// returned as first matched.
+func scanMatch(sr *searcher, scanned []*field, s string, pos int, loc []int) []int {
+	if loc == nil || len(scanned) == 0 {
+		return loc
+	}
+	names := sr.regexp.SubexpNames()
+	// Each match may start a scanned capture elsewhere, so matching
+	// again is bounded, like the regions found by each match
+	for range scanned {
+		var regions []scannedRegion
+		done := true
+		for _, f := range scanned {
+			group := f.groupIndex(names)
+			if group <= 0 || loc[2*group] < 0 {
+				continue
+			}
+			start := loc[2*group]
+			end := f.Scan(s, start)
+			if end < 0 {
+				continue
+			}
+			regions = append(regions, scannedRegion{group, start, end})
+			done = done && end == loc[2*group+1]
+		}
+		if done {
+			return loc
+		}
+		rematched := matchRegions(sr, s, pos, regions)
+		if rematched == nil {
+			return loc
+		}
+		loc = rematched
+	}
+	return loc
+}
+
+// matchRegions searches the string from the position for the searcher's
+// regular expression, with each region, all after the position, replaced by
+// the scanSentinel, and returns the location of the match in the string,
+// or nil if there is no match with each region captured by its group
+func matchRegions(sr *searcher, s string, pos int, regions []scannedRegion) []int {
+	sort.Slice(regions, func(i, j int) bool { return regions[i].start < regions[j].start })
+	var b strings.Builder
+	b.Grow(len(s))
+	prev := 0
+	for _, r := range regions {
+		if r.start < prev {
+			// A region within another is left to the outer one
+			return nil
+		}
+		b.WriteString(s[prev:r.start])
+		b.WriteString(scanSentinel)
+		prev = r.end
+	}
+	b.WriteString(s[prev:])
+
+	loc := sr.find(b.String(), pos)
+	if loc == nil {
+		return nil
+	}
+	for i, offset := range loc {
+		if offset < 0 {
+			continue
+		}
+		shift := 0
+		for _, r := range regions {
+			if offset < r.start-shift+len(scanSentinel) {
+				break
+			}
+			shift += r.end - r.start - len(scanSentinel)
+		}
+		loc[i] = offset + shift
+	}
+	for _, r := range regions {
+		if loc[2*r.group] != r.start || loc[2*r.group+1] != r.end {
+			return nil
+		}
+	}
+	return loc
+}
+
+// findSubmatchIndex returns the location of the match of the regular expression
+// in the string, like FindStringSubmatchIndex, scanning the scanned fields
+func findSubmatchIndex(regxp *regexp.Regexp, scanned []*field, s string) []int {
+	loc := regxp.FindStringSubmatchIndex(s)
+	if loc == nil || len(scanned) == 0 {
+		return loc
+	}
+	return scanMatch(newSearcher(regxp), scanned, s, 0, loc)
+}
+
+// findAllSubmatchIndex returns the location of every match of the regular
+// expression in the string, like FindAllStringSubmatchIndex, scanning the
+// scanned fields, with the search for each match after the end of the last
+func findAllSubmatchIndex(regxp *regexp.Regexp, scanned []*field, s string) [][]int {
+	if len(scanned) == 0 {
+		return regxp.FindAllStringSubmatchIndex(s, -1)
+	}
+	sr := newSearcher(regxp)
+	var locs [][]int
+	// Follows the search of regexp's FindAll methods from each match's end
+	for pos, prevEnd := 0, -1; pos <= len(s); {
+		loc := sr.findScanned(scanned, s, pos)
+		if loc == nil {
+			break
+		}
+		var accept bool
+		pos, prevEnd, accept = nextSearch(s, pos, prevEnd, loc)
+		if accept {
+			locs = append(locs, loc)
+		}
+	}
+	return locs
+}
@@ -0,0 +1,87 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const transformKey = "structexp.transform"
+
+// Transform rewrites a field's capture before it is converted. Transforms are
+// registered by name with RegisterTransform and applied to a field with the
+// structexp.transform tag, such as to remove the thousands separators of a
+// number, without defining a ParsableField type for it.
+type Transform func(s string) (string, error)
+
+// Built-in transforms
+const (
+	// LowerTransform maps the capture to lower case
+	LowerTransform = "lower"
+	// UpperTransform maps the capture to upper case
+	UpperTransform = "upper"
+	// StripCommasTransform removes the commas of the capture, such as of 1,234
+	StripCommasTransform = "stripcommas"
+	// UnquoteTransform unquotes the capture as a Go string literal, with strconv.Unquote
+	UnquoteTransform = "unquote"
+)
+
+func init() {
+	RegisterTransform(LowerTransform, func(s string) (string, error) {
+		return strings.ToLower(s), nil
+	})
+	RegisterTransform(UpperTransform, func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+	RegisterTransform(StripCommasTransform, func(s string) (string, error) {
+		return strings.ReplaceAll(s, ",", ""), nil
+	})
+	RegisterTransform(UnquoteTransform, strconv.Unquote)
+}
+
+// RegisterTransform registers the transform by name, for fields to reference
+// with the structexp.transform tag. Registering an existing name replaces it.
+func RegisterTransform(name string, transform Transform) {
+	defaultRegistry.RegisterTransform(name, transform)
+}
+
+// RegisterTransform registers the transform by name in the registry only.
+// Registering an existing name replaces it, and hides the name in the default registry.
+func (r *Registry) RegisterTransform(name string, transform Transform) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transforms[name] = transform
+	if r == defaultRegistry {
+		invalidateCompiledTypes()
+	}
+}
+
+func (r *Registry) lookupTransform(name string) (Transform, error) {
+	r.mu.RLock()
+	transform, ok := r.transforms[name]
+	r.mu.RUnlock()
+	if !ok {
+		if r.parent != nil {
+			return r.parent.lookupTransform(name)
+		}
+		return nil, &UnknownTransform{name}
+	}
+	return transform, nil
+}
+
+// lookupTransforms sets the field's transforms to those named by the comma
+// separated list of its structexp.transform tag, applied in order
+func (f *field) lookupTransforms(tag reflect.StructTag, reg *Registry) error {
+	names, ok := tag.Lookup(transformKey)
+	if !ok {
+		return nil
+	}
+	for _, name := range strings.Split(names, ",") {
+		transform, err := reg.lookupTransform(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		f.Transforms = append(f.Transforms, transform)
+	}
+	return nil
+}
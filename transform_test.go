@@ -0,0 +1,100 @@
+package structexp
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TransformStruct struct {
+	StructExp `structexp:"^{{name}} {{amount}} {{level}}$"`
+	Name      string `structexp.name:"name" structexp.exp:"\"[^\"]*\"" structexp.transform:"unquote,lower"`
+	Amount    int    `structexp.name:"amount" structexp.exp:"[0-9,]+" structexp.transform:"stripcommas"`
+	Level     string `structexp.name:"level" structexp.exp:"[a-z]+" structexp.transform:"upper" structexp.default:"INFO" structexp.optional:"true"`
+}
+
+type UnknownTransformStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test" structexp.transform:"reverse"`
+}
+
+func TestTransform(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "transforms",
+			String:   `"Alice" 1,234,567 warn`,
+			Input:    &TransformStruct{},
+			Expected: &TransformStruct{Name: "alice", Amount: 1234567, Level: "WARN"},
+			Error:    nil,
+		},
+		{
+			Name:     "default is not transformed",
+			String:   `"Bob" 12 `,
+			Input:    &TransformStruct{},
+			Expected: &TransformStruct{Name: "bob", Amount: 12, Level: "INFO"},
+			Error:    nil,
+		},
+		{
+			Name:     "transform fails",
+			String:   `"\q" 12 warn`,
+			Input:    &TransformStruct{},
+			Expected: &TransformStruct{},
+			Error:    &FieldError{"Name", "name", `"\q"`, strconv.ErrSyntax},
+		},
+		{
+			Name:     "unknown transform",
+			String:   "abc",
+			Input:    &UnknownTransformStruct{},
+			Expected: &UnknownTransformStruct{},
+			Error:    &UnknownTransform{"reverse"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestRegistryTransform(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterTransform("reverse", func(s string) (string, error) {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	})
+
+	parser, err := Compile[UnknownTransformStruct](WithRegistry(reg))
+	require.NoError(t, err)
+	value, err := parser.Parse("abc")
+	require.NoError(t, err)
+	assert.EqualValues(t, UnknownTransformStruct{Value: "cba"}, value)
+
+	// Names not in the registry fall back to the default registry
+	_, err = Compile[TransformStruct](WithRegistry(reg))
+	assert.NoError(t, err)
+}
+
+func TestValidateTransform(t *testing.T) {
+	assert.NoError(t, Validate(TransformStruct{}))
+	assert.EqualValues(t, &InvalidDefinition{reflect.TypeOf(UnknownTransformStruct{}), []error{
+		&UnknownTransform{"reverse"},
+	}}, Validate(UnknownTransformStruct{}))
+}
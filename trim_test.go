@@ -0,0 +1,49 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type FixedWidthStruct struct {
+	StructExp `structexp:"^{{name}}{{count}}{{id}}$"`
+	Name      string `structexp.name:"name" structexp.exp:".{8}" structexp.trim:"true"`
+	Count     int    `structexp.name:"count" structexp.exp:".{6}" structexp.trim:"true" structexp.default:"1"`
+	ID        string `structexp.name:"id" structexp.exp:".{6}" structexp.trim:"0"`
+}
+
+func TestTrim(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Expected FixedWidthStruct
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "Padded",
+			String:   "disk        42 00120",
+			Expected: FixedWidthStruct{Name: "disk", Count: 42, ID: " 0012"},
+		},
+		{
+			Name:     "Cutset",
+			String:   "memory  7     001200",
+			Expected: FixedWidthStruct{Name: "memory", Count: 7, ID: "12"},
+		},
+		{
+			Name:     "TrimmedToDefault",
+			String:   "cpu             0012",
+			Expected: FixedWidthStruct{Name: "cpu", Count: 1, ID: "  0012"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			v, err := MustCompile[FixedWidthStruct]().Parse(tc.String)
+			assert.NoError(t, err)
+			assert.EqualValues(t, tc.Expected, v)
+		})
+	}
+}
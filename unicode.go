@@ -0,0 +1,83 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Unicode aware default regular expressions, used by the UnicodeDefaults option
+const (
+	UnicodeIntRegexp    = `\p{Nd}+`
+	UnicodeStringRegexp = `[\p{L}\p{M}\p{N}\p{P}\p{S}\p{Zs}]+`
+)
+
+// UnicodeDefaults matches the fields without a structexp.exp tag with the
+// Unicode aware default expressions, instead of the POSIX classes matching
+// ASCII only: ints match UnicodeIntRegexp, the decimal digits of any script,
+// such as ١٢٣, which are converted to their value, and strings match
+// UnicodeStringRegexp, the printable characters of any script. Structs with
+// their own StructExp field keep the expressions they were compiled with.
+func UnicodeDefaults() Option {
+	return func(o *options) {
+		o.unicodeDefaults = true
+	}
+}
+
+func unicodeKindExp(k reflect.Kind) string {
+	// nolint:exhaustive // unnecessary
+	switch k {
+	case reflect.Int:
+		return UnicodeIntRegexp
+	case reflect.String:
+		return UnicodeStringRegexp
+	default:
+		return kindExp(k)
+	}
+}
+
+// setUnicodeDefaults replaces the default expressions of the fields with the
+// Unicode aware ones, converting the digits of int captures to ASCII
+func setUnicodeDefaults(fields []*field) {
+	for _, f := range fields {
+		if f.DefaultKind == reflect.Invalid {
+			continue
+		}
+		exp := unicodeKindExp(f.DefaultKind)
+		if f.Split != "" {
+			exp = splitExp(exp, f.Split)
+		}
+		f.Exp = exp
+		if f.DefaultKind == reflect.Int {
+			f.Transforms = append(f.Transforms, asciiDigits)
+		}
+	}
+}
+
+// asciiDigits replaces the decimal digits of any script in
+// the string with the ASCII digits of the same value
+func asciiDigits(s string) (string, error) {
+	return strings.Map(func(r rune) rune {
+		if r <= unicode.MaxASCII || !unicode.Is(unicode.Nd, r) {
+			return r
+		}
+		return '0' + digitValue(r)
+	}, s), nil
+}
+
+// digitValue returns the value of the decimal digit. The digits of
+// every script are in ranges of ten, from zero to nine, so the value
+// is the offset of the digit from the start of its range, modulo ten.
+func digitValue(r rune) rune {
+	for _, r16 := range unicode.Nd.R16 {
+		if lo, hi := rune(r16.Lo), rune(r16.Hi); lo <= r && r <= hi {
+			return (r - lo) % 10
+		}
+	}
+	for _, r32 := range unicode.Nd.R32 {
+		if lo, hi := rune(r32.Lo), rune(r32.Hi); lo <= r && r <= hi {
+			return (r - lo) % 10
+		}
+	}
+	return 0
+}
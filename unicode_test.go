@@ -0,0 +1,98 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Reading struct {
+	StructExp `structexp:"^{{name}}={{value}}(?: {{samples}})?$"`
+	Name      string `structexp.name:"name"`
+	Value     int    `structexp.name:"value"`
+	Samples   []int  `structexp.name:"samples" structexp.split:","`
+}
+
+func TestUnicodeDefaults(t *testing.T) {
+	type TestCase struct {
+		Name    string
+		Options []Option
+		Input   string
+		Value   Reading
+		Error   error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:    "ascii",
+			Options: []Option{UnicodeDefaults()},
+			Input:   "temp=21 20,22",
+			Value:   Reading{Name: "temp", Value: 21, Samples: []int{20, 22}},
+			Error:   nil,
+		},
+		{
+			Name:    "arabic-indic digits",
+			Options: []Option{UnicodeDefaults()},
+			Input:   "حرارة=٢١ ٢٠,٢٢",
+			Value:   Reading{Name: "حرارة", Value: 21, Samples: []int{20, 22}},
+			Error:   nil,
+		},
+		{
+			Name:    "devanagari digits",
+			Options: []Option{UnicodeDefaults()},
+			Input:   "तापमान=१०९",
+			Value:   Reading{Name: "तापमान", Value: 109},
+			Error:   nil,
+		},
+		{
+			Name:    "posix defaults",
+			Options: nil,
+			Input:   "حرارة=٢١",
+			Value:   Reading{},
+			Error:   noMatch[Reading]("حرارة=٢١"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := Compile[Reading](tc.Options...)
+			require.NoError(t, err)
+
+			value, err := parser.Parse(tc.Input)
+			assert.EqualValues(t, tc.Value, value)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestUnicodeDefaultsExp(t *testing.T) {
+	parser, err := Compile[Reading](UnicodeDefaults())
+	require.NoError(t, err)
+	assert.Equal(t,
+		`^(?P<name>[\p{L}\p{M}\p{N}\p{P}\p{S}\p{Zs}]+)=(?P<value>\p{Nd}+)(?: (?P<samples>(?:\p{Nd}+)(?:,(?:\p{Nd}+))*))?$`,
+		parser.Regexp().String())
+}
+
+func TestASCIIDigits(t *testing.T) {
+	s, err := asciiDigits("x٠١٢٣٤٥٦٧٨٩ ０９ 𝟘𝟡 42")
+	require.NoError(t, err)
+	assert.Equal(t, "x0123456789 09 09 42", s)
+}
+
+func TestUnicodeDefaultsVariant(t *testing.T) {
+	RegisterVariant[VariantStruct]("status=ascii", Variant{
+		Exps: map[string]string{"status": `[0-9]{3}`},
+	})
+
+	parser, err := Compile[VariantStruct](WithVariant("status=ascii"), UnicodeDefaults())
+	require.NoError(t, err)
+
+	// The expressions of the variant are not defaults
+	_, err = parser.Parse("10.0.0.1 ٢٠٠")
+	assert.EqualValues(t, NewNoMatch(parser.Regexp(), "10.0.0.1 ٢٠٠"), err)
+	value, err := parser.Parse("10.0.0.1 200")
+	require.NoError(t, err)
+	assert.EqualValues(t, VariantStruct{Client: "10.0.0.1", Status: 200}, value)
+}
@@ -0,0 +1,244 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Tag keys of the package, checked for typos by Validate
+var tagKeys = map[string]bool{
+	tagKey:              true,
+	captureGroupNameKey: true,
+	expKey:              true,
+	subParserKey:        true,
+	discriminatorKey:    true,
+	optionalKey:         true,
+	defaultKey:          true,
+	ignoreCaseKey:       true,
+	trimKey:             true,
+	onlyKey:             true,
+	indexKey:            true,
+	splitKey:            true,
+	rawKey:              true,
+	transformKey:        true,
+	flagsKey:            true,
+	minKey:              true,
+	maxKey:              true,
+	oneOfKey:            true,
+	matchKey:            true,
+}
+
+// Tag keys whose values are parsed with strconv.ParseBool
+var boolTagKeys = map[string]bool{
+	optionalKey:   true,
+	ignoreCaseKey: true,
+}
+
+// Validate checks the struct definition of the argument, which may be a
+// struct, a pointer to one, or its reflect.Type, for the problems otherwise
+// discovered on the first parse, or never:
+//  - the StructExp field is missing
+//  - a field's tag is malformed, has an unknown structexp key, or an invalid bool or structexp.only value
+//  - two fields have the same capture group name
+//  - a placeholder in the template has no field
+//  - a field's capture group has no placeholder in the template
+//  - a placeholder names an unknown token
+//  - a field's structexp.index tag is not the position of an unnamed capture group
+//  - a field's structexp.raw tag names no other field's capture group
+//  - a field's default fails to parse into the field
+//  - a field's validation tag has an invalid value, or compares the length of a type without one
+//  - a field names an unknown sub-parser or transform, or the regular expression fails to compile
+//
+// Each template is checked, if the struct has several StructExp fields,
+// and a field's capture group needs a placeholder in only one of them.
+// All problems found are returned at once in an InvalidDefinition error.
+// Validate is meant for tests and startup, as it rebuilds the regular expression.
+func Validate(i interface{}) error {
+	t, ok := i.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(i)
+	}
+	if t == nil {
+		return &NotStruct{reflect.Invalid}
+	}
+	if t = derefType(t); t.Kind() != reflect.Struct {
+		return &NotStruct{t.Kind()}
+	}
+
+	var problems []error
+	problems = append(problems, validateTags(t, nil)...)
+
+	bases, err := regexpBases(t)
+	if err != nil {
+		problems = append(problems, err)
+		return newInvalidDefinition(t, problems)
+	}
+
+	fields, err := listFields(t, nil, nil, defaultRegistry)
+	if err != nil {
+		problems = append(problems, err)
+		return newInvalidDefinition(t, problems)
+	}
+
+	if err := checkDuplicateGroups(t, fields); err != nil {
+		problems = append(problems, err)
+	}
+	if err := checkRawGroups(t, fields); err != nil {
+		problems = append(problems, err)
+	}
+	groups := map[string]bool{}
+	for _, f := range fields {
+		groups[f.CaptureGroupName] = true
+	}
+	for _, f := range fields {
+		reflectField := structField(t, f.Index)
+		if _, err := newChecks(reflectField.Type, reflectField.Tag); err != nil {
+			problems = append(problems, &InvalidTag{fieldPath(t, f.Index), string(reflectField.Tag), "invalid validation tag", err})
+		}
+	}
+	for _, f := range fields {
+		if f.Default == "" {
+			continue
+		}
+		if err := f.SetDefault(reflect.New(t).Elem(), func(string) string { return "" }); err != nil {
+			path := fieldPath(t, f.Index)
+			problems = append(problems, &InvalidTag{path, string(structField(t, f.Index).Tag), fmt.Sprintf("invalid value of %s", defaultKey), err})
+		}
+	}
+	// A field only needs a placeholder in one of the templates
+	placeholders := map[string]bool{}
+	for _, base := range bases {
+		problems = append(problems, validateTemplate(t, base, fields, groups, placeholders)...)
+	}
+	for _, f := range fields {
+		if !placeholders[f.CaptureGroupName] && f.GroupIndex == 0 && f.CaptureGroupName != WholeMatch && f.Raw == "" {
+			problems = append(problems, &UnplacedField{fieldPath(t, f.Index), f.CaptureGroupName})
+		}
+	}
+	return newInvalidDefinition(t, problems)
+}
+
+// validateTemplate checks the placeholders of one of the templates
+// of the struct type, adding their names to the placeholders
+func validateTemplate(t reflect.Type, base string, fields []*field, groups, placeholders map[string]bool) []error {
+	var problems []error
+	var unknown []string
+	for _, submatches := range placeholderRegexp.FindAllStringSubmatch(base, -1) {
+		name, tokenName := submatches[1], submatches[2]
+		placeholders[name] = true
+		if !groups[name] {
+			unknown = append(unknown, name)
+		}
+		if _, ok := tokens[tokenName]; tokenName != "" && !ok {
+			problems = append(problems, &UnknownToken{tokenName})
+		}
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, &UnknownPlaceholder{unknown})
+	}
+
+	regxp, err := fillRegexp(base, fields, placeholderRegexp)
+	if err != nil {
+		switch err.(type) {
+		case *UnknownToken, *UnknownPlaceholder:
+		default:
+			problems = append(problems, err)
+		}
+		return problems
+	}
+	if _, err := nameIndexedGroups(t, regxp, fields); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
+func newInvalidDefinition(t reflect.Type, problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return &InvalidDefinition{t, problems}
+}
+
+// validateTags checks the tags of the struct's fields, and of the
+// fields of nested struct types, given the struct types already checked
+func validateTags(t reflect.Type, checked []reflect.Type) []error {
+	var problems []error
+	checked = append(checked, t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if err := validateTag(field); err != nil {
+			problems = append(problems, err)
+		}
+		if field.Type != reflect.TypeOf(StructExp{}) && field.Tag.Get(tagKey) == "-" {
+			continue
+		}
+
+		structType := derefType(field.Type)
+		if structType.Kind() == reflect.Slice {
+			structType = derefType(structType.Elem())
+		}
+		if structType.Kind() == reflect.Struct && structType != reflect.TypeOf(StructExp{}) && !containsType(checked, structType) {
+			problems = append(problems, validateTags(structType, checked)...)
+		}
+	}
+	return problems
+}
+
+// validateTag checks the tag follows the conventional key:"value" format,
+// and that every key of the package is known
+func validateTag(field reflect.StructField) error {
+	tag := string(field.Tag)
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			break
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return &InvalidTag{field.Name, string(field.Tag), "malformed key:\"value\" pair", nil}
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("unterminated value of %s", key), nil}
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("invalid value of %s", key), err}
+		}
+		if boolTagKeys[key] {
+			if _, err := strconv.ParseBool(value); err != nil {
+				return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("invalid value of %s", key), err}
+			}
+		}
+		if key == flagsKey && !validFlags(value) {
+			return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("invalid value of %s", key), nil}
+		}
+		if key == onlyKey && value != parseOnly && value != formatOnly {
+			return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("invalid value of %s", key), nil}
+		}
+		if index, err := strconv.Atoi(value); key == indexKey && (err != nil || index < 1) {
+			return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("invalid value of %s", key), err}
+		}
+		tag = tag[i+1:]
+
+		if (key == tagKey || strings.HasPrefix(key, tagKey+".")) && !tagKeys[key] {
+			return &InvalidTag{field.Name, string(field.Tag), fmt.Sprintf("unknown key %s", key), nil}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package structexp
+
+import (
+	"errors"
+	"reflect"
+	"regexp/syntax"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TypoStruct struct {
+	StructExp `structexp:"^{{level}} {{cod}} {{msg:unknown}}$"`
+	Level     string `structexp.nmae:"level"`
+	Code      int    `structexp.name:"code"`
+	Message   string `structexp.name:"msg"`
+}
+
+type InvalidExpStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test" structexp.exp:"(a"`
+}
+
+type InvalidOptionalStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test" structexp.optional:"maybe"`
+}
+
+type InvalidIndexStruct struct {
+	StructExp `structexp:"^(a)(?P<b>b)$"`
+	A         string `structexp.index:"first"`
+	B         string `structexp.index:"2"`
+}
+
+type InvalidRawStruct struct {
+	StructExp `structexp:"^{{code}}$"`
+	Code      int    `structexp.name:"code"`
+	CodeText  string `structexp.raw:"cod"`
+}
+
+type InvalidOnlyStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     string `structexp.name:"test" structexp.only:"write"`
+}
+
+type InvalidDefaultStruct struct {
+	StructExp `structexp:"^{{test}}$"`
+	Value     int `structexp.name:"test" structexp.default:"none"`
+}
+
+func TestValidate(t *testing.T) {
+	// Built with reflection, since vet rejects malformed tags
+	malformedTagType := reflect.StructOf([]reflect.StructField{
+		{Name: "StructExp", Type: reflect.TypeOf(StructExp{}), Tag: `structexp:"^{{test}}$"`},
+		{Name: "Value", Type: reflect.TypeOf(""), Tag: `structexp.name:test`},
+	})
+
+	type TestCase struct {
+		Name  string
+		Input interface{}
+		Error error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:  "Valid",
+			Input: &ParentHeaderStruct{},
+			Error: nil,
+		},
+		{
+			Name:  "ValidType",
+			Input: reflect.TypeOf(SubParsedStruct{}),
+			Error: nil,
+		},
+		{
+			Name:  "IgnoredFields",
+			Input: IgnoredFieldStruct{},
+			Error: nil,
+		},
+		{
+			Name:  "NotStructError",
+			Input: 1,
+			Error: &NotStruct{reflect.Int},
+		},
+		{
+			Name:  "MissingFieldError",
+			Input: MissingFieldStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(MissingFieldStruct{}), []error{&MissingField{}}},
+		},
+		{
+			Name:  "AllProblems",
+			Input: TypoStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(TypoStruct{}), []error{
+				&InvalidTag{"Level", `structexp.nmae:"level"`, "unknown key structexp.nmae", nil},
+				&UnknownToken{"unknown"},
+				&UnknownPlaceholder{[]string{"level", "cod"}},
+				&UnplacedField{"Level", "Level"},
+				&UnplacedField{"Code", "code"},
+			}},
+		},
+		{
+			Name:  "MalformedTag",
+			Input: malformedTagType,
+			Error: &InvalidDefinition{malformedTagType, []error{
+				&InvalidTag{"Value", `structexp.name:test`, "malformed key:\"value\" pair", nil},
+				&UnknownPlaceholder{[]string{"test"}},
+				&UnplacedField{"Value", "Value"},
+			}},
+		},
+		{
+			Name:  "InvalidBoolTag",
+			Input: InvalidOptionalStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(InvalidOptionalStruct{}), []error{
+				&InvalidTag{"Value", `structexp.name:"test" structexp.optional:"maybe"`, "invalid value of structexp.optional", &strconv.NumError{Func: "ParseBool", Num: "maybe", Err: strconv.ErrSyntax}},
+			}},
+		},
+		{
+			Name:  "InvalidOnlyTag",
+			Input: InvalidOnlyStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(InvalidOnlyStruct{}), []error{
+				&InvalidTag{"Value", `structexp.name:"test" structexp.only:"write"`, "invalid value of structexp.only", nil},
+			}},
+		},
+		{
+			Name:  "DuplicateGroup",
+			Input: DuplicateGroupStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(DuplicateGroupStruct{}), []error{
+				&DuplicateGroup{"code", [2]string{"Code", "Nested.Status"}},
+			}},
+		},
+		{
+			Name:  "InvalidIndex",
+			Input: InvalidIndexStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(InvalidIndexStruct{}), []error{
+				&InvalidTag{"A", `structexp.index:"first"`, "invalid value of structexp.index", &strconv.NumError{Func: "Atoi", Num: "first", Err: strconv.ErrSyntax}},
+				&UnknownIndex{"B", 2},
+				&UnplacedField{"A", "A"},
+			}},
+		},
+		{
+			Name:  "InvalidRaw",
+			Input: InvalidRawStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(InvalidRawStruct{}), []error{
+				&UnknownRawGroup{"CodeText", "cod"},
+			}},
+		},
+		{
+			Name:  "InvalidDefault",
+			Input: InvalidDefaultStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(InvalidDefaultStruct{}), []error{
+				&InvalidTag{"Value", `structexp.name:"test" structexp.default:"none"`, "invalid value of structexp.default", &strconv.NumError{Func: "ParseInt", Num: "none", Err: strconv.ErrSyntax}},
+			}},
+		},
+		{
+			Name:  "InvalidExp",
+			Input: InvalidExpStruct{},
+			Error: &InvalidDefinition{reflect.TypeOf(InvalidExpStruct{}), []error{
+				&syntax.Error{Code: syntax.ErrMissingParen, Expr: "^(?P<test>(a)$"},
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.EqualValues(t, tc.Error, Validate(tc.Input))
+		})
+	}
+}
+
+func TestValidateUnwrap(t *testing.T) {
+	var unknown *UnknownToken
+	assert.True(t, errors.As(Validate(TypoStruct{}), &unknown))
+	assert.Equal(t, "unknown", unknown.Token)
+
+	// The errors of invalid tag values are wrapped
+	assert.ErrorIs(t, Validate(InvalidOptionalStruct{}), strconv.ErrSyntax)
+	badQuoteType := reflect.StructOf([]reflect.StructField{
+		{Name: "StructExp", Type: reflect.TypeOf(StructExp{}), Tag: `structexp:"^{{test}}$"`},
+		{Name: "Value", Type: reflect.TypeOf(""), Tag: `structexp.name:"te\st"`},
+	})
+	var invalidTag *InvalidTag
+	err := Validate(reflect.New(badQuoteType).Elem().Interface())
+	require.ErrorAs(t, err, &invalidTag)
+	assert.Equal(t, "invalid value of structexp.name", invalidTag.Reason)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
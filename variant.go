@@ -0,0 +1,79 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Variant adjusts the definition of a struct type for one source of its
+// input, such as the access logs of nginx and of apache, so that a single
+// struct type parses the slightly different formats of several sources.
+// Variants are registered by key with RegisterVariant, and selected when
+// compiling a Parser with the WithVariant option.
+type Variant struct {
+	// Template replaces the template of the StructExp field, if not empty
+	Template string
+	// Exps replaces the expressions of the fields by capture group name
+	Exps map[string]string
+}
+
+var variants = struct {
+	mu    sync.RWMutex
+	types map[reflect.Type]map[string]Variant
+}{types: map[reflect.Type]map[string]Variant{}}
+
+// RegisterVariant registers the variant of the struct type T by key, such
+// as "source=nginx". Registering an existing key replaces it.
+// Variants are shared by every Parser, whatever its Registry.
+func RegisterVariant[T any](key string, variant Variant) {
+	exps := make(map[string]string, len(variant.Exps))
+	for name, exp := range variant.Exps {
+		exps[name] = exp
+	}
+	variant.Exps = exps
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	variants.mu.Lock()
+	defer variants.mu.Unlock()
+	if variants.types[t] == nil {
+		variants.types[t] = map[string]Variant{}
+	}
+	variants.types[t][key] = variant
+}
+
+// WithVariant compiles the Parser with the variant of its struct type
+// registered by key. A template given by WithTemplate takes precedence
+// over the variant's template.
+func WithVariant(key string) Option {
+	return func(o *options) {
+		o.variant = key
+	}
+}
+
+func lookupVariant(t reflect.Type, key string) (Variant, error) {
+	variants.mu.RLock()
+	defer variants.mu.RUnlock()
+	variant, ok := variants.types[t][key]
+	if !ok {
+		return Variant{}, &UnknownVariant{t, key}
+	}
+	return variant, nil
+}
+
+// apply replaces the expressions of the fields with those of the variant
+func (variant Variant) apply(fields []*field) error {
+	for name, exp := range variant.Exps {
+		found := false
+		for _, f := range fields {
+			if f.CaptureGroupName == name {
+				f.Exp = exp
+				f.DefaultKind = reflect.Invalid
+				found = true
+			}
+		}
+		if !found {
+			return &UnknownGroup{name}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package structexp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type VariantStruct struct {
+	StructExp `structexp:"^{{client}} {{status}}$"`
+	Client    string `structexp.name:"client" structexp.exp:"[0-9.]+"`
+	Status    int    `structexp.name:"status"`
+}
+
+func init() {
+	RegisterVariant[VariantStruct]("source=nginx", Variant{})
+	RegisterVariant[VariantStruct]("source=apache", Variant{
+		Template: `^{{client}} - - {{status}}$`,
+		Exps:     map[string]string{"client": `[0-9a-f:.]+`},
+	})
+	RegisterVariant[VariantStruct]("source=unknown", Variant{
+		Exps: map[string]string{"host": `\S+`},
+	})
+}
+
+func TestVariant(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		String   string
+		Expected VariantStruct
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "NoVariant",
+			String:   "10.0.0.1 200",
+			Expected: VariantStruct{Client: "10.0.0.1", Status: 200},
+		},
+		{
+			Name:     "EmptyVariant",
+			Options:  []Option{WithVariant("source=nginx")},
+			String:   "10.0.0.1 200",
+			Expected: VariantStruct{Client: "10.0.0.1", Status: 200},
+		},
+		{
+			Name:     "TemplateAndExps",
+			Options:  []Option{WithVariant("source=apache")},
+			String:   "::1 - - 404",
+			Expected: VariantStruct{Client: "::1", Status: 404},
+		},
+		{
+			Name:     "WithTemplateTakesPrecedence",
+			Options:  []Option{WithVariant("source=apache"), WithTemplate(`^{{status}} {{client}}$`)},
+			String:   "500 ::1",
+			Expected: VariantStruct{Client: "::1", Status: 500},
+		},
+		{
+			Name:    "UnknownVariant",
+			Options: []Option{WithVariant("source=iis")},
+			Error:   &UnknownVariant{reflect.TypeOf(VariantStruct{}), "source=iis"},
+		},
+		{
+			Name:    "UnknownGroup",
+			Options: []Option{WithVariant("source=unknown")},
+			Error:   &UnknownGroup{"host"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			parser, err := Compile[VariantStruct](tc.Options...)
+			assert.EqualValues(t, tc.Error, err)
+			if err != nil {
+				return
+			}
+			v, err := parser.Parse(tc.String)
+			assert.NoError(t, err)
+			assert.EqualValues(t, tc.Expected, v)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package structexp // nolint:golint // in another file
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Warning is a problem that does not fail a parse, or the compilation of a
+// Parser, but is likely a mistake in the definition or the data, such as:
+//  - the structexp.exp tag on a bool or int field, which is not recommended
+//  - an optional field's capture group matching the empty string
+//  - a number out of range of its field, saturated or wrapped by the WithOverflowPolicy option
+//
+// Warnings of parses are returned in a ParseResult, and passed to the
+// WarningHook option; warnings of the definition are returned by
+// Parser.Warnings, and passed to the WarningHook once by Compile.
+type Warning struct {
+	// Field is the path of the field from the struct, such as Header.Level
+	Field string
+	// Group is the name of the field's capture group
+	Group string
+	// Reason describes the problem
+	Reason string
+}
+
+func (w *Warning) Error() string {
+	return fmt.Sprintf("warning: field %s (group %q): %s", w.Field, w.Group, w.Reason)
+}
+
+// WarningHook is called with every Warning of a Parser
+type WarningHook func(w *Warning)
+
+// WithWarningHook calls the hook with the warnings of the definition of the
+// Parser, and of every parse by Parse and ParseResult. Checking parses for
+// warnings matches the string again, so the hook is only for finding problems.
+func WithWarningHook(hook WarningHook) Option {
+	return func(o *options) {
+		o.warningHook = hook
+	}
+}
+
+// definitionWarnings returns the warnings of the fields of the struct type
+func definitionWarnings(t reflect.Type, fields []*field) []error {
+	var warnings []error
+	for _, f := range fields {
+		reflectField := structField(t, f.Index)
+		if _, ok := reflectField.Tag.Lookup(expKey); !ok {
+			continue
+		}
+		if kind := reflectField.Type.Kind(); kind == reflect.Bool || kind == reflect.Int {
+			warnings = append(warnings, &Warning{fieldPath(t, f.Index), f.CaptureGroupName,
+				fmt.Sprintf("%s tag on %v fields is not recommended", expKey, kind)})
+		}
+	}
+	return warnings
+}
+
+// matchWarnings returns the warnings of parsing the fields of the struct
+// type from the submatch indexes of the regular expression in the string
+func matchWarnings(t reflect.Type, regxp *regexp.Regexp, fields []*field, s string, loc []int) []error {
+	var warnings []error
+	for _, f := range fields {
+		idx := subexpIndex(regxp.SubexpNames(), f.CaptureGroupName)
+		if idx == -1 || loc[2*idx] < 0 || f.Raw != "" {
+			continue
+		}
+		capture := s[loc[2*idx]:loc[2*idx+1]]
+		if capture == "" {
+			if f.Optional {
+				warnings = append(warnings, &Warning{fieldPath(t, f.Index), f.CaptureGroupName, "optional capture is empty"})
+			}
+			continue
+		}
+		if f.Overflow == OverflowError || f.SubParser != nil || f.Discriminator != "" || f.Nested != nil {
+			continue
+		}
+		if f.Transform != nil {
+			var err error
+			if capture, err = f.Transform(capture); err != nil {
+				continue
+			}
+		}
+		// The elements of split fields are each set from their part of the capture
+		typ, parts := fieldType(t, f.Index), []string{capture}
+		if f.Split != "" {
+			typ, parts = derefType(typ).Elem(), strings.Split(capture, f.Split)
+		}
+		for _, part := range parts {
+			val := reflect.New(typ).Elem()
+			if err := setField(val, part); overflow(val, err, f.Overflow) {
+				action := "saturated"
+				if f.Overflow == OverflowWrap {
+					action = "wrapped"
+				}
+				warnings = append(warnings, &Warning{fieldPath(t, f.Index), f.CaptureGroupName,
+					fmt.Sprintf("%s is out of range, %s to %v", part, action, underlyingValue(val).Interface())})
+			}
+		}
+	}
+	return warnings
+}
+
+// Warnings returns the warnings of the definition of T
+func (p *Parser[T]) Warnings() []error {
+	return p.warnings
+}
+
+// warn passes the warnings to the WarningHook option, if set
+func (p *typeParser) warn(warnings []error) {
+	if p.opts.warningHook == nil {
+		return
+	}
+	for _, w := range warnings {
+		p.opts.warningHook(w.(*Warning))
+	}
+}
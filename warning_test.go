@@ -0,0 +1,94 @@
+package structexp
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type WarningStruct struct {
+	StructExp `structexp:"^{{level}} {{code}} {{count}}$"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]*" structexp.optional:"true"`
+	Code      int    `structexp.name:"code" structexp.exp:"-?[0-9]+"`
+	Count     int    `structexp.name:"count"`
+}
+
+func TestWarnings(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Options  []Option
+		String   string
+		Expected ParseResult[WarningStruct]
+	}
+
+	definition := &Warning{"Code", "code", "structexp.exp tag on int fields is not recommended"}
+	testCases := []TestCase{
+		{
+			Name:   "NoWarnings",
+			String: "INFO 1 2",
+			Expected: ParseResult[WarningStruct]{
+				Value: WarningStruct{Level: "INFO", Code: 1, Count: 2},
+			},
+		},
+		{
+			Name:   "EmptyOptional",
+			String: " 1 2",
+			Expected: ParseResult[WarningStruct]{
+				Value:    WarningStruct{Code: 1, Count: 2},
+				Warnings: []error{&Warning{"Level", "level", "optional capture is empty"}},
+			},
+		},
+		{
+			Name:    "Saturated",
+			Options: []Option{LenientConversions()},
+			String:  "INFO -99999999999999999999 99999999999999999999",
+			Expected: ParseResult[WarningStruct]{
+				Value: WarningStruct{Level: "INFO", Code: math.MinInt, Count: math.MaxInt},
+				Warnings: []error{
+					&Warning{"Code", "code", "-99999999999999999999 is out of range, saturated to " + strconv.Itoa(math.MinInt)},
+					&Warning{"Count", "count", "99999999999999999999 is out of range, saturated to " + strconv.Itoa(math.MaxInt)},
+				},
+			},
+		},
+		{
+			Name:   "NotLenient",
+			String: "INFO 1 99999999999999999999",
+			Expected: ParseResult[WarningStruct]{
+				Value: WarningStruct{Level: "INFO", Code: 1},
+				Err: &FieldError{"Count", "count", "99999999999999999999", &OutOfRange{reflect.TypeOf(0), "99999999999999999999",
+					&strconv.NumError{Func: "ParseInt", Num: "99999999999999999999", Err: strconv.ErrRange}}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			var hooked []*Warning
+			opts := append([]Option{WithWarningHook(func(w *Warning) { hooked = append(hooked, w) })}, tc.Options...)
+			parser := MustCompile[WarningStruct](opts...)
+			assert.EqualValues(t, []error{definition}, parser.Warnings())
+
+			result := parser.ParseResult(tc.String)
+			assert.EqualValues(t, tc.Expected.Value, result.Value)
+			assert.EqualValues(t, tc.Expected.Warnings, result.Warnings)
+			assert.EqualValues(t, tc.Expected.Err, result.Err)
+
+			expected := []*Warning{definition}
+			for _, w := range tc.Expected.Warnings {
+				expected = append(expected, w.(*Warning))
+			}
+			assert.EqualValues(t, expected, hooked)
+
+			// Parse passes the same warnings to the hook
+			hooked = hooked[:0]
+			value, err := parser.Parse(tc.String)
+			assert.EqualValues(t, tc.Expected.Value, value)
+			assert.EqualValues(t, tc.Expected.Err, err)
+			assert.EqualValues(t, expected[1:], hooked)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package structexp // nolint:golint // in another file
+
+// WholeMatch is the structexp.name of a field set to the whole match of the
+// regular expression, like the $0 of awk, such as structexp.name:"$0" on a
+// string field keeping the raw record alongside the fields parsed from it.
+// The field needs no placeholder, and is not rendered by Format.
+const WholeMatch = "$0"
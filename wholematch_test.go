@@ -0,0 +1,105 @@
+package structexp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RawHeader struct {
+	StructExp `structexp:"{{level}}: {{code}}"`
+	Raw       string `structexp.name:"$0"`
+	Level     string `structexp.name:"level" structexp.exp:"[A-Z]+"`
+	Code      int    `structexp.name:"code"`
+}
+
+type RawHeaderPair struct {
+	StructExp `structexp:"^{{first}} / {{second}}$"`
+	Line      string    `structexp.name:"$0"`
+	First     RawHeader `structexp.name:"first"`
+	Second    RawHeader `structexp.name:"second"`
+}
+
+func TestWholeMatch(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		String   string
+		Input    interface{}
+		Expected interface{}
+		Error    error
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "whole match",
+			String:   "WARN: 12",
+			Input:    &RawHeader{},
+			Expected: &RawHeader{Raw: "WARN: 12", Level: "WARN", Code: 12},
+			Error:    nil,
+		},
+		{
+			Name:     "unanchored",
+			String:   "at 10:00 WARN: 12 ms",
+			Input:    &RawHeader{},
+			Expected: &RawHeader{Raw: "WARN: 12", Level: "WARN", Code: 12},
+			Error:    nil,
+		},
+		{
+			Name:   "nested",
+			String: "WARN: 12 / INFO: 3",
+			Input:  &RawHeaderPair{},
+			Expected: &RawHeaderPair{
+				Line:   "WARN: 12 / INFO: 3",
+				First:  RawHeader{Raw: "WARN: 12", Level: "WARN", Code: 12},
+				Second: RawHeader{Raw: "INFO: 3", Level: "INFO", Code: 3},
+			},
+			Error: nil,
+		},
+		{
+			Name:     "no match",
+			String:   "warn: 12",
+			Input:    &RawHeader{},
+			Expected: &RawHeader{},
+			Error:    noMatch[RawHeader]("warn: 12"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.Name, func(t *testing.T) {
+			err := Parse(tc.String, tc.Input)
+			assert.EqualValues(t, tc.Expected, tc.Input)
+			assert.EqualValues(t, tc.Error, err)
+		})
+	}
+}
+
+func TestWholeMatchParseAll(t *testing.T) {
+	values, err := ParseAllAs[RawHeader]("WARN: 12, INFO: 3")
+	require.NoError(t, err)
+	assert.EqualValues(t, []RawHeader{
+		{Raw: "WARN: 12", Level: "WARN", Code: 12},
+		{Raw: "INFO: 3", Level: "INFO", Code: 3},
+	}, values)
+}
+
+func TestWholeMatchFormat(t *testing.T) {
+	s, err := Format(RawHeader{Raw: "ignored", Level: "WARN", Code: 12})
+	require.NoError(t, err)
+	assert.EqualValues(t, "WARN: 12", s)
+}
+
+func TestWholeMatchValidate(t *testing.T) {
+	assert.NoError(t, Validate(&RawHeader{}))
+	assert.NoError(t, Validate(&RawHeaderPair{}))
+}
+
+func TestWholeMatchOffsets(t *testing.T) {
+	parser, err := Compile[RawHeader]()
+	require.NoError(t, err)
+
+	_, offsets, err := parser.ParseWithOffsets("at WARN: 12")
+	require.NoError(t, err)
+	assert.EqualValues(t, []FieldOffset{{"Raw", "$0", 3, 11}, {"Level", "level", 3, 7}, {"Code", "code", 9, 11}}, offsets)
+}